@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package evidence
+
+import (
+	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/spf13/cobra"
+)
+
+func EvidenceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evidence",
+		Short: "Inspects evidence archives",
+		Long: "Lists, shows and extracts the files of an evidence archive together with the statuses of their checks.\n" +
+			"If the archive contains a manifest, the files are verified against the hashes in the manifest.",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	cmd.AddCommand(lsCommand(), treeCommand(), catCommand(), extractCommand())
+	return cmd
+}
+
+func lsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls <evidence.zip>",
+		Short: "Lists the files of an evidence archive",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initLogger()
+			return onyx.Ls(parameters(cmd, args), cmd.OutOrStdout())
+		},
+	}
+	addFilterFlags(cmd)
+	return cmd
+}
+
+func treeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree <evidence.zip>",
+		Short: "Shows the files of an evidence archive as tree",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initLogger()
+			return onyx.Tree(parameters(cmd, args), cmd.OutOrStdout())
+		},
+	}
+	addFilterFlags(cmd)
+	return cmd
+}
+
+func catCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cat <evidence.zip> <file>",
+		Short: "Prints a file of an evidence archive",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initLogger()
+			return onyx.Cat(args[0], args[1], cmd.OutOrStdout())
+		},
+	}
+}
+
+func extractCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extract <evidence.zip>",
+		Short: "Extracts files of an evidence archive",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initLogger()
+			output, _ := cmd.Flags().GetString("output")
+			return onyx.Extract(parameters(cmd, args), output, cmd.OutOrStdout())
+		},
+	}
+	addFilterFlags(cmd)
+	cmd.Flags().String("output", ".", "Directory to extract the files to")
+	return cmd
+}
+
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("check", "", "Only files of the check with the ID in the format <chapter>_<requirement>_<check>")
+	cmd.Flags().String("step", "", "Only files of the step with the ID")
+	cmd.Flags().String("glob", "", "Only files whose path or name matches the glob, e.g. '*.json'")
+}
+
+func parameters(cmd *cobra.Command, args []string) onyx.Parameters {
+	check, _ := cmd.Flags().GetString("check")
+	step, _ := cmd.Flags().GetString("step")
+	glob, _ := cmd.Flags().GetString("glob")
+	return onyx.Parameters{
+		Archive: args[0],
+		Filter:  evidence.Filter{Check: check, Step: step, Glob: glob},
+	}
+}
+
+func initLogger() {
+	logger.Set(logger.NewConsoleFileLogger(logger.Settings{
+		Files: []string{"onyx.log"},
+	}))
+}
@@ -6,25 +6,35 @@ package exec
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
 
 	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/exec"
 	"github.com/B-S-F/yaku/onyx/pkg/parameter"
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	resultV2 "github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/runner"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 const DefaultTimeout = 10 * 60
 
 func ExecCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "exec [input-folder]",
+		Use:   "exec [input-folder | input-archive | -] | --projects <project-folder>... | --workspace <workspace-file>",
 		Short: "Executes the project",
-		Long:  "If no input folder is specified the current directory is used",
-		Args:  cobra.MaximumNArgs(1),
-		RunE:  Run,
+		Long: "If no input folder is specified the current directory is used.\n" +
+			"The input can also be a .tar, .tar.gz, .tgz or .zip archive, or '-' to read a tar stream from stdin.\n" +
+			"With --projects or --workspace several projects are run at once, " +
+			"their outputs are written to a subfolder per project next to a combined portfolio result and report.",
+		Args: cobra.ArbitraryArgs,
+		RunE: Run,
 	}
 	cmd.Flags().String("output-dir", ".", "output folder, defaults to the current directory")
 	cmd.Flags().String("secrets-name", onyx.SECRETS_FILE, "Name of the secrets file in the input folder")
@@ -32,11 +42,40 @@ func ExecCommand() *cobra.Command {
 	cmd.Flags().String("config-name", "qg-config.yaml", "Path to the config file")
 	cmd.Flags().Bool("strict", false, "If set to true, the autopilot will return a ERROR status if the JSON line output is not valid")
 	cmd.Flags().Int("check-timeout", DefaultTimeout, "Timeout for a each check in seconds")
+	cmd.Flags().String("history-dir", "", "Directory to store the history of runs in, required to notify on status changes between runs")
+	cmd.Flags().String("previous-result", "", "Result file of a previous run, the durations of its checks are used to start the longest running checks first")
+	cmd.Flags().String("result-version", "", "Format of the result file, either 'v1' or 'v2', defaults to 'v1' for v0 and v1 configs and to 'v2' otherwise")
+	cmd.Flags().String("result-logs", "full", "How logs are provided in the result: 'full' embeds them, 'reference' stores them in the evidence and references them, 'none' leaves them out")
+	cmd.Flags().Bool("projects", false, "If set, all arguments are project folders which are run together")
+	cmd.Flags().String("workspace", "", "Path to a workspace file listing the projects to run together")
+	cmd.Flags().Int("max-concurrency", 0, "Maximum number of autopilots and finalizers run at the same time across all projects, unlimited if 0")
+	cmd.Flags().String("shell", runner.ShellBash, "Shell which runs the scripts, either 'bash' to use /bin/bash or 'embedded' to use the built-in shell interpreter")
+	cmd.Flags().Bool("audit-network", false, "If set, the destinations of the steps of all automated checks are recorded in the result, not only of autopilots with network settings")
+	cmd.Flags().String("tsa-url", "", "URL of an RFC 3161 time-stamping authority, if set the evidence manifest is timestamped and the token is stored next to the result")
+	cmd.Flags().StringArray("overlay", nil, "Overlay file in the input folder which patches the config, can be repeated and is applied in the given order")
 	cmd.Flags().StringP("check", "c", "", "Used with a value in the format <chapterId>_<requirementId>_<checkId> to select a single check to run, others will be skipped")
 	return cmd
 }
 
 func Run(cmd *cobra.Command, args []string) error {
+	return run(cmd, args, nil)
+}
+
+func run(cmd *cobra.Command, args []string, recorder *parameter.HttpRecorder) error {
+	projectsMode, _ := cmd.Flags().GetBool("projects")
+	workspace, _ := cmd.Flags().GetString("workspace")
+	if projectsMode && workspace != "" {
+		return errors.New("projects and workspace can not be used together")
+	}
+	if workspace != "" && len(args) != 0 {
+		return errors.New("no input folder can be specified together with a workspace")
+	}
+	if !projectsMode && len(args) > 1 {
+		return fmt.Errorf("accepts at most 1 arg(s), received %d, use --projects to run several projects", len(args))
+	}
+	if projectsMode && len(args) == 0 {
+		return errors.New("at least one project folder is required")
+	}
 	inputFolder := "."
 	if len(args) != 0 {
 		inputFolder = args[0]
@@ -48,6 +87,15 @@ func Run(cmd *cobra.Command, args []string) error {
 	_ = viper.BindPFlag("strict", cmd.Flags().Lookup("strict"))
 	_ = viper.BindPFlag("check-timeout", cmd.Flags().Lookup("check-timeout"))
 	_ = viper.BindPFlag("check", cmd.Flags().Lookup("check"))
+	_ = viper.BindPFlag("history-dir", cmd.Flags().Lookup("history-dir"))
+	_ = viper.BindPFlag("previous-result", cmd.Flags().Lookup("previous-result"))
+	_ = viper.BindPFlag("result-version", cmd.Flags().Lookup("result-version"))
+	_ = viper.BindPFlag("result-logs", cmd.Flags().Lookup("result-logs"))
+	_ = viper.BindPFlag("max-concurrency", cmd.Flags().Lookup("max-concurrency"))
+	_ = viper.BindPFlag("shell", cmd.Flags().Lookup("shell"))
+	_ = viper.BindPFlag("tsa-url", cmd.Flags().Lookup("tsa-url"))
+	_ = viper.BindPFlag("audit-network", cmd.Flags().Lookup("audit-network"))
+	overlays, _ := cmd.Flags().GetStringArray("overlay")
 
 	execParams := parameter.ExecutionParameter{
 		Strict:          viper.GetBool("strict"),
@@ -58,6 +106,31 @@ func Run(cmd *cobra.Command, args []string) error {
 		SecretsName:     viper.GetString("secrets-name"),
 		CheckIdentifier: viper.GetString("check"),
 		CheckTimeout:    viper.GetDuration("check-timeout") * time.Second,
+		ResultVersion:   viper.GetString("result-version"),
+		ResultLogs:      viper.GetString("result-logs"),
+		MaxConcurrency:  viper.GetInt("max-concurrency"),
+		Shell:           viper.GetString("shell"),
+		TsaURL:          viper.GetString("tsa-url"),
+		AuditNetwork:    viper.GetBool("audit-network"),
+		Overlays:        overlays,
+		HttpRecorder:    recorder,
+	}
+	// the flags are recorded in the provenance of the run
+	execParams.Flags = make(map[string]string)
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		execParams.Flags[flag.Name] = flag.Value.String()
+	})
+	if historyDir := viper.GetString("history-dir"); historyDir != "" {
+		execParams.HistoryDir = filepath.Clean(historyDir)
+	}
+	if previousResult := viper.GetString("previous-result"); previousResult != "" {
+		execParams.PreviousResult = filepath.Clean(previousResult)
+	}
+
+	// notifications can also be configured in the onyx.yaml to be shared by all projects
+	notifications, err := readNotifications()
+	if err != nil {
+		return err
 	}
 
 	if !strings.HasPrefix(execParams.SecretsName, onyx.SECRETS_FILE) {
@@ -69,5 +142,56 @@ func Run(cmd *cobra.Command, args []string) error {
 	if execParams.CheckTimeout <= 0 {
 		return errors.New("check-timeout value should be a positive number")
 	}
-	return onyx.Exec(execParams)
+	if execParams.ResultVersion != "" && execParams.ResultVersion != onyx.RESULT_VERSION_V1 && execParams.ResultVersion != onyx.RESULT_VERSION_V2 {
+		return fmt.Errorf("result-version should be either '%s' or '%s'", onyx.RESULT_VERSION_V1, onyx.RESULT_VERSION_V2)
+	}
+	if _, err := resultV2.ParseLogMode(execParams.ResultLogs); err != nil {
+		return err
+	}
+	if execParams.MaxConcurrency < 0 {
+		return errors.New("max-concurrency value should not be negative")
+	}
+	if execParams.Shell != runner.ShellBash && execParams.Shell != runner.ShellEmbedded {
+		return fmt.Errorf("shell should be either '%s' or '%s'", runner.ShellBash, runner.ShellEmbedded)
+	}
+	if execParams.TsaURL != "" {
+		if u, err := url.Parse(execParams.TsaURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("tsa-url should be an http or https URL")
+		}
+	}
+	if execParams.PreviousResult != "" && (projectsMode || workspace != "") {
+		return errors.New("previous-result can not be used with several projects, use history-dir instead")
+	}
+
+	switch {
+	case workspace != "":
+		projects, err := readWorkspace(workspace)
+		if err != nil {
+			return err
+		}
+		return onyx.ExecProjects(projects, execParams, notifications)
+	case projectsMode:
+		return onyx.ExecProjects(projectsFromFolders(args), execParams, notifications)
+	default:
+		execParams.InputFolder, err = onyx.UnpackInput(execParams.InputFolder, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		return onyx.Exec(execParams, notifications)
+	}
+}
+
+func readNotifications() ([]v2.Notification, error) {
+	if !viper.IsSet("notifications") {
+		return nil, nil
+	}
+	content, err := yaml.Marshal(viper.Get("notifications"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications from onyx config: %w", err)
+	}
+	var notifications []v2.Notification
+	if err := yaml.Unmarshal(content, &notifications); err != nil {
+		return nil, fmt.Errorf("invalid notifications in onyx config: %w", err)
+	}
+	return notifications, nil
 }
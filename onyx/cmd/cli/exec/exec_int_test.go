@@ -10,6 +10,7 @@ package exec
 import (
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -31,17 +32,19 @@ var (
 
 const (
 	// ignoring the temp directories, as they are different on each run
-	ignoreTempDir = `((\/var\/folders\/.*?\/(exec|apps)\/)|(\/tmp\/.*?\/(exec|apps)\/)|(C:\\\\Temp\\\\.*?\\\\(exec|apps)\\\\))`
+	ignoreTempDir = `((\/var\/folders\/.*?\/(exec\/apps|exec|apps)\/)|(\/tmp\/.*?\/(exec\/apps|exec|apps)\/)|(C:\\\\Temp\\\\.*?\\\\(exec\\\\apps|exec|apps)\\\\))`
 	// ignoring the bash line number, as it differs between different Bash versions
 	ignoreBashLine = "(/bin/bash: line [0-9]+:)"
 	// ignoring the date, as it is different on each run
 	ignoreDate = `\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(?:\+\d{2}:\d{2}|Z)|\s\d{2}:\d{2})`
+	// ignoring the duration of the autopilots, as it is different on each run
+	ignoreDuration = `(?m)^\s*duration: [0-9.]+\n`
 )
 
 func TestExecCommandIntegration(t *testing.T) {
 	versions := []string{"v1", "v2"}
 	appsPath := "testdata/apps"
-	re := regexp.MustCompile(fmt.Sprintf("%s|%s|%s", ignoreTempDir, ignoreBashLine, ignoreDate))
+	re := regexp.MustCompile(fmt.Sprintf("%s|%s|%s|%s", ignoreTempDir, ignoreBashLine, ignoreDate, ignoreDuration))
 	serveApps(appsPath, "8081", t)
 
 	for _, version := range versions {
@@ -93,12 +96,12 @@ func TestExecCommandIntegration(t *testing.T) {
 }
 
 func serveApps(directory string, port string, t *testing.T) {
-	http.Handle("/", http.FileServer(http.Dir(directory)))
+	// listen before returning, the apps are downloaded as soon as the first config is run
+	listener, err := net.Listen("tcp", ":"+port)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
 	go func() {
-		err := http.ListenAndServe(":"+port, nil)
-		if err != nil {
-			t.Fail()
-		}
+		_ = http.Serve(listener, http.FileServer(http.Dir(directory)))
 	}()
 }
 
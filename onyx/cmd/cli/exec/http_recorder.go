@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/B-S-F/yaku/onyx/pkg/parameter"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/httprecorder"
+	"github.com/spf13/cobra"
+)
+
+// HttpRecorderCommand executes the project like exec, while the HTTP traffic of the automated checks is recorded or replayed
+func HttpRecorderCommand() *cobra.Command {
+	cmd := ExecCommand()
+	cmd.Use = "http-recorder record|replay [input-folder]"
+	cmd.Short = "Executes the project and records or replays the HTTP traffic of the automated checks"
+	cmd.Long = "The automated checks send their HTTP and HTTPS requests through a local proxy.\n" +
+		"In record mode the requests are sent to the original servers and the responses are stored in a cassette per check.\n" +
+		"In replay mode the recorded responses are returned without contacting the original servers.\n" +
+		"The cassettes are stored in the 'cassettes' folder of the input folder unless --cassette-dir is given."
+	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.ValidArgs = []string{httprecorder.ModeRecord, httprecorder.ModeReplay}
+	cmd.RunE = runHttpRecorder
+	cmd.Flags().String("cassette-dir", "", "Directory of the cassettes, defaults to the 'cassettes' folder of the input folder")
+	_ = cmd.Flags().MarkHidden("projects")
+	_ = cmd.Flags().MarkHidden("workspace")
+	_ = cmd.Flags().MarkHidden("audit-network")
+	return cmd
+}
+
+func runHttpRecorder(cmd *cobra.Command, args []string) error {
+	mode := args[0]
+	if mode != httprecorder.ModeRecord && mode != httprecorder.ModeReplay {
+		return fmt.Errorf("mode should be either '%s' or '%s'", httprecorder.ModeRecord, httprecorder.ModeReplay)
+	}
+	projectsMode, _ := cmd.Flags().GetBool("projects")
+	workspace, _ := cmd.Flags().GetString("workspace")
+	if projectsMode || workspace != "" {
+		return errors.New("http-recorder can only run a single project")
+	}
+	inputFolder := "."
+	if len(args) > 1 {
+		inputFolder = args[1]
+	}
+	cassetteDir, _ := cmd.Flags().GetString("cassette-dir")
+	if cassetteDir == "" {
+		if info, err := os.Stat(inputFolder); err != nil || !info.IsDir() {
+			return errors.New("cassette-dir is required if the input is not a folder")
+		}
+		cassetteDir = filepath.Join(inputFolder, "cassettes")
+	}
+	return run(cmd, args[1:], &parameter.HttpRecorder{
+		Mode:        mode,
+		CassetteDir: filepath.Clean(cassetteDir),
+	})
+}
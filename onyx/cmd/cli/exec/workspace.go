@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/exec"
+	"github.com/B-S-F/yaku/onyx/pkg/archive"
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace lists the projects of a multi-project run
+type Workspace struct {
+	Projects []WorkspaceProject `yaml:"projects"`
+}
+
+type WorkspaceProject struct {
+	// Input folder or archive of the project, relative to the workspace file
+	Path string `yaml:"path"`
+	// Name of the project, defaults to the name of its folder or archive
+	Name string `yaml:"name"`
+}
+
+func readWorkspace(path string) ([]onyx.Project, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+	var workspace Workspace
+	if err := yaml.Unmarshal(content, &workspace); err != nil {
+		return nil, fmt.Errorf("invalid workspace file: %w", err)
+	}
+	if len(workspace.Projects) == 0 {
+		return nil, fmt.Errorf("workspace file '%s' does not contain any projects", path)
+	}
+	baseDir := filepath.Dir(path)
+	projects := make([]onyx.Project, 0, len(workspace.Projects))
+	for _, p := range workspace.Projects {
+		if p.Path == "" {
+			return nil, fmt.Errorf("invalid workspace file: path of project '%s' is missing", p.Name)
+		}
+		inputFolder := p.Path
+		if !filepath.IsAbs(inputFolder) {
+			inputFolder = filepath.Join(baseDir, inputFolder)
+		}
+		project := projectFromFolder(inputFolder)
+		if p.Name != "" {
+			project.Name = p.Name
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+func projectsFromFolders(folders []string) []onyx.Project {
+	projects := make([]onyx.Project, 0, len(folders))
+	for _, folder := range folders {
+		projects = append(projects, projectFromFolder(folder))
+	}
+	return projects
+}
+
+func projectFromFolder(folder string) onyx.Project {
+	folder = filepath.Clean(folder)
+	name := filepath.Base(folder)
+	if abs, err := filepath.Abs(folder); err == nil {
+		name = filepath.Base(abs)
+	}
+	// archives are named after the archive file without its extension
+	return onyx.Project{Name: archive.TrimExtension(name), InputFolder: folder}
+}
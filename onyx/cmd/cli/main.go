@@ -9,9 +9,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/B-S-F/yaku/onyx/cmd/cli/evidence"
 	"github.com/B-S-F/yaku/onyx/cmd/cli/exec"
 	"github.com/B-S-F/yaku/onyx/cmd/cli/migrate"
+	"github.com/B-S-F/yaku/onyx/cmd/cli/plan"
+	"github.com/B-S-F/yaku/onyx/cmd/cli/result"
 	"github.com/B-S-F/yaku/onyx/cmd/cli/schema"
+	"github.com/B-S-F/yaku/onyx/cmd/cli/validate"
 	"github.com/B-S-F/yaku/onyx/pkg/helper"
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
@@ -53,9 +57,14 @@ func initViper() {
 func initFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(logLevel, "", "info", "log level, one of: debug, info, warn, error, fatal, panic")
 	_ = viper.BindPFlag(logLevel, cmd.PersistentFlags().Lookup(logLevel))
+	cmd.AddCommand(evidence.EvidenceCommand())
 	cmd.AddCommand(exec.ExecCommand())
+	cmd.AddCommand(exec.HttpRecorderCommand())
 	cmd.AddCommand(migrate.MigrateCommand())
+	cmd.AddCommand(plan.PlanCommand())
+	cmd.AddCommand(result.ResultCommand())
 	cmd.AddCommand(schema.SchemaCommand())
+	cmd.AddCommand(validate.ValidateCommand())
 	cmd.SilenceErrors = true
 }
 
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package plan
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/B-S-F/yaku/onyx/internal/onyx/exec"
+	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/plan"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func PlanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan [input-folder]",
+		Short: "Shows the order in which the checks of the project are started",
+		Long: "The automated checks are started longest expected duration first.\n" +
+			"Expected durations are taken from the 'expectedDuration' of the checks, a previous result or the history of runs.\n" +
+			"Checks whose conditions are not met with the vars are not started and not included in the estimate.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: Run,
+	}
+	cmd.Flags().String("config-name", "qg-config.yaml", "Path to the config file")
+	cmd.Flags().String("vars-name", exec.VARS_FILE, "Path to the variables file, the conditions of the checks are evaluated with its vars")
+	cmd.Flags().String("history-dir", "", "Directory with the history of runs to read the durations of the checks from")
+	cmd.Flags().String("previous-result", "", "Result file of a previous run to read the durations of the checks from")
+	cmd.Flags().Bool("estimate", false, "If set, the total run time is predicted")
+	cmd.Flags().Int("parallelism", 0, "Number of checks run at the same time for the estimate, unlimited if 0")
+	cmd.Flags().StringArray("overlay", nil, "Overlay file in the input folder which patches the config, can be repeated and is applied in the given order")
+	return cmd
+}
+
+func Run(cmd *cobra.Command, args []string) error {
+	inputFolder := "."
+	if len(args) != 0 {
+		inputFolder = args[0]
+	}
+	_ = viper.BindPFlag("config-name", cmd.Flags().Lookup("config-name"))
+	_ = viper.BindPFlag("vars-name", cmd.Flags().Lookup("vars-name"))
+	_ = viper.BindPFlag("history-dir", cmd.Flags().Lookup("history-dir"))
+	_ = viper.BindPFlag("previous-result", cmd.Flags().Lookup("previous-result"))
+	_ = viper.BindPFlag("estimate", cmd.Flags().Lookup("estimate"))
+	_ = viper.BindPFlag("parallelism", cmd.Flags().Lookup("parallelism"))
+	overlays, _ := cmd.Flags().GetStringArray("overlay")
+
+	params := onyx.Parameters{
+		InputFolder:    filepath.Clean(inputFolder),
+		ConfigName:     viper.GetString("config-name"),
+		VarsName:       viper.GetString("vars-name"),
+		HistoryDir:     viper.GetString("history-dir"),
+		PreviousResult: viper.GetString("previous-result"),
+		Estimate:       viper.GetBool("estimate"),
+		Parallelism:    viper.GetInt("parallelism"),
+		Overlays:       overlays,
+	}
+	if params.Parallelism < 0 {
+		return errors.New("parallelism value should not be negative")
+	}
+	logger.Set(logger.NewConsoleFileLogger(logger.Settings{
+		Files: []string{"onyx.log"},
+	}))
+	return onyx.Plan(params, cmd.OutOrStdout())
+}
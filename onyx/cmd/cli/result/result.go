@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/result"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+func ResultCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result",
+		Short: "Works with the results of runs",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	cmd.AddCommand(verifyCommand())
+	return cmd
+}
+
+func verifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <evidence.zip>",
+		Short: "Verifies the evidence of a run and its timestamp",
+		Long: "Checks that all files of the evidence archive match its manifest and that the manifest was timestamped\n" +
+			"by a trusted RFC 3161 time-stamping authority, which proves that the result and the evidence existed at that time.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, _ := cmd.Flags().GetString("result")
+			timestamp, _ := cmd.Flags().GetString("timestamp")
+			tsaCA, _ := cmd.Flags().GetString("tsa-ca")
+			logger.Set(logger.NewConsoleFileLogger(logger.Settings{
+				Files: []string{"onyx.log"},
+			}))
+			return onyx.Verify(onyx.Parameters{
+				Archive:   args[0],
+				Result:    result,
+				Timestamp: timestamp,
+				TsaCA:     tsaCA,
+			}, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().String("result", "", "Result file which must match the result in the evidence, e.g. the qg-result.yaml next to the archive")
+	cmd.Flags().String("timestamp", "", "Timestamp token of the manifest to verify instead of the one in the evidence, e.g. the evidence-manifest.tsr next to the result")
+	cmd.Flags().String("tsa-ca", "", "PEM file with the certificates trusted to issue the certificate of the time-stamping authority, defaults to the system certificates")
+	return cmd
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"path/filepath"
+
+	onyx "github.com/B-S-F/yaku/onyx/internal/onyx/validate"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [input-folder]",
+		Short: "Validates the config of the project without running it",
+		Long: "The config is checked the same way as before a run.\n" +
+			"In addition, all scripts are parsed to report shell syntax errors with their line and column.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: Run,
+	}
+	cmd.Flags().String("config-name", "qg-config.yaml", "Path to the config file")
+	cmd.Flags().StringArray("overlay", nil, "Overlay file in the input folder which patches the config, can be repeated and is applied in the given order")
+	return cmd
+}
+
+func Run(cmd *cobra.Command, args []string) error {
+	inputFolder := "."
+	if len(args) != 0 {
+		inputFolder = args[0]
+	}
+	_ = viper.BindPFlag("config-name", cmd.Flags().Lookup("config-name"))
+	overlays, _ := cmd.Flags().GetStringArray("overlay")
+
+	params := onyx.Parameters{
+		InputFolder: filepath.Clean(inputFolder),
+		ConfigName:  viper.GetString("config-name"),
+		Overlays:    overlays,
+	}
+	logger.Set(logger.NewConsoleFileLogger(logger.Settings{
+		Files: []string{"onyx.log"},
+	}))
+	return onyx.Validate(params, cmd.OutOrStdout())
+}
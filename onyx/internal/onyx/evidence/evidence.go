@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package evidence
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+)
+
+type Parameters struct {
+	Archive string
+	Filter  evidence.Filter
+}
+
+// Ls lists the files of the archive with the statuses of their checks and the outcome of the verification
+func Ls(params Parameters, out io.Writer) error {
+	return withEntries(params, func(entries []evidence.Entry) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHECK\tSTATUS\tSTEP\tSIZE\tVERIFICATION\tPATH")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", orDash(entry.Check), orDash(entry.Status), orDash(entry.Step), entry.Size, entry.Verification, entry.Path)
+		}
+		return w.Flush()
+	})
+}
+
+// Tree shows the files of the archive as tree, check directories are annotated with their status
+func Tree(params Parameters, out io.Writer) error {
+	return withEntries(params, func(entries []evidence.Entry) error {
+		root := &node{children: map[string]*node{}}
+		statuses := map[string]string{}
+		for _, entry := range entries {
+			root.add(strings.Split(entry.Path, "/"), entry)
+			if entry.Check != "" {
+				statuses[entry.Check] = entry.Status
+			}
+		}
+		root.print(out, 0, func(name string, depth int) string {
+			if status, ok := statuses[name]; ok && depth == 0 && status != "" {
+				return fmt.Sprintf(" [%s]", status)
+			}
+			return ""
+		})
+		return nil
+	})
+}
+
+// Cat writes the content of a file of the archive to out
+func Cat(archivePath, name string, out io.Writer) error {
+	archive, err := evidence.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	warnWithoutManifest(archive)
+	content, err := archive.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(content)
+	return err
+}
+
+// Extract writes the files of the archive matching the filter to dest
+func Extract(params Parameters, dest string, out io.Writer) error {
+	archive, err := evidence.Open(params.Archive)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	warnWithoutManifest(archive)
+	entries, err := archive.Extract(params.Filter, dest)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "extracted %d files to '%s'\n", len(entries), dest)
+	return nil
+}
+
+// withEntries calls show with the filtered entries of the archive and fails afterwards if the verification failed
+func withEntries(params Parameters, show func(entries []evidence.Entry) error) error {
+	archive, err := evidence.Open(params.Archive)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	warnWithoutManifest(archive)
+	entries, err := archive.Entries(params.Filter)
+	if err != nil {
+		return err
+	}
+	if err := show(entries); err != nil {
+		return err
+	}
+	var missing []string
+	if params.Filter == (evidence.Filter{}) {
+		missing = archive.Missing()
+	}
+	return evidence.VerificationErr(entries, missing)
+}
+
+func warnWithoutManifest(archive *evidence.Archive) {
+	if !archive.HasManifest() {
+		logger.Get().Warnf("evidence archive has no '%s', files can't be verified", evidence.MANIFEST_FILE)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+type node struct {
+	children map[string]*node
+	entry    *evidence.Entry
+}
+
+func (n *node) add(parts []string, entry evidence.Entry) {
+	child, ok := n.children[parts[0]]
+	if !ok {
+		child = &node{children: map[string]*node{}}
+		n.children[parts[0]] = child
+	}
+	if len(parts) == 1 {
+		child.entry = &entry
+		return
+	}
+	child.add(parts[1:], entry)
+}
+
+func (n *node) print(out io.Writer, depth int, annotate func(name string, depth int) string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := n.children[name]
+		indent := strings.Repeat("  ", depth)
+		if child.entry != nil {
+			suffix := ""
+			if child.entry.Verification == evidence.Modified || child.entry.Verification == evidence.Unlisted {
+				suffix = fmt.Sprintf(" (%s)", child.entry.Verification)
+			}
+			fmt.Fprintf(out, "%s%s%s\n", indent, name, suffix)
+			continue
+		}
+		fmt.Fprintf(out, "%s%s/%s\n", indent, name, annotate(name, depth))
+		child.print(out, depth+1, annotate)
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package evidence
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/zip"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArchive(t *testing.T, modified string) string {
+	dir := filepath.Join(t.TempDir(), "run")
+	files := map[string]string{
+		"qg-result.yaml":                    "chapters:\n  \"1\":\n    requirements:\n      \"2\":\n        checks:\n          \"3\":\n            evaluation:\n              status: RED\n",
+		"1_2_3/steps/fetch/files/data.json": "{}",
+		"1_2_3/evaluation/result.json":      "{}",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	require.NoError(t, evidence.WriteManifest(dir))
+	if modified != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, modified), []byte("modified"), 0644))
+	}
+	archive := filepath.Join(t.TempDir(), "evidence.zip")
+	z := zip.New(afero.NewOsFs())
+	require.NoError(t, z.Directory(dir, archive))
+	return archive
+}
+
+func TestLs(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Ls(Parameters{Archive: createArchive(t, ""), Filter: evidence.Filter{Check: "1_2_3"}}, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, `CHECK  STATUS  STEP   SIZE  VERIFICATION  PATH
+1_2_3  RED     -      2     verified      1_2_3/evaluation/result.json
+1_2_3  RED     fetch  2     verified      1_2_3/steps/fetch/files/data.json
+`, out.String())
+}
+
+func TestTree(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Tree(Parameters{Archive: createArchive(t, "1_2_3/evaluation/result.json")}, &out)
+
+	assert.ErrorContains(t, err, "file '1_2_3/evaluation/result.json' does not match the hash in the manifest")
+	assert.Equal(t, `1_2_3/ [RED]
+  evaluation/
+    result.json (modified)
+  steps/
+    fetch/
+      files/
+        data.json
+evidence-manifest.json
+qg-result.yaml
+`, out.String())
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/egress"
+	model "github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/schedule"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// startEgressProxy starts the proxy which records the destinations of the steps and enforces the network settings of the autopilots.
+// Only the steps of autopilots with network settings are routed through the proxy, unless the network of all checks is audited.
+// No proxy is started if no step is routed through it. The evaluation, services, setup, teardown and finalizers are never routed.
+func (e *exec) startEgressProxy(ep *model.ExecutionPlan) (*egress.Proxy, error) {
+	if !e.execParams.AuditNetwork && !restrictsNetwork(ep) {
+		return nil, nil
+	}
+	e.logger.Info("[ START EGRESS PROXY ]")
+	proxy := egress.New(e.logger)
+	if err := proxy.Start(); err != nil {
+		return nil, err
+	}
+	for i := range ep.AutopilotChecks {
+		check := &ep.AutopilotChecks[i]
+		if !e.execParams.AuditNetwork && check.Autopilot.Network == nil {
+			continue
+		}
+		key := schedule.Key(*check)
+		for _, level := range check.Autopilot.Steps {
+			for j := range level {
+				step := &level[j]
+				env, err := proxy.Register(key, step.ID, check.Autopilot.Name, check.Autopilot.Network, upstreamProxy(ep.Env, step.Env, check.Autopilot.Env))
+				if err != nil {
+					proxy.Stop()
+					return nil, err
+				}
+				step.ProxyEnv = env
+			}
+		}
+	}
+	return proxy, nil
+}
+
+// restrictsNetwork returns whether any autopilot of the automated checks has network settings
+func restrictsNetwork(ep *model.ExecutionPlan) bool {
+	for _, check := range ep.AutopilotChecks {
+		if check.Autopilot.Network != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// upstreamProxy returns the proxy configuration of a step as configured by the user, in the environment of onyx or the env of the config.
+// The variables of the egress proxy replace it in the environment of the step, so the egress proxy forwards the traffic accordingly.
+func upstreamProxy(envs ...map[string]string) httpproxy.Config {
+	environ := make(map[string]string)
+	for _, key := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		environ[key] = os.Getenv(key)
+	}
+	env := helper.MergeMaps(append([]map[string]string{environ}, envs...)...)
+	// the upper case variables take precedence, like in the standard library
+	get := func(key string) string {
+		if env[key] != "" {
+			return env[key]
+		}
+		return env[strings.ToLower(key)]
+	}
+	return httpproxy.Config{
+		HTTPProxy:  get("HTTP_PROXY"),
+		HTTPSProxy: get("HTTPS_PROXY"),
+		NoProxy:    get("NO_PROXY"),
+	}
+}
+
+// stopEgressProxy stops the proxy and adds the destinations to the step results.
+// Checks with steps which tried to connect to destinations that are not allowed fail with an error.
+func (e *exec) stopEgressProxy(proxy *egress.Proxy, runResult *model.RunResult) {
+	if proxy == nil {
+		return
+	}
+	e.logger.Info("[ STOP EGRESS PROXY ]")
+	proxy.Stop()
+	if runResult == nil {
+		return
+	}
+	for _, run := range runResult.Autopilots {
+		if run.Result == nil {
+			continue
+		}
+		key := schedule.Key(run.AutopilotCheck)
+		var denied []string
+		for i := range run.Result.StepResults {
+			stepResult := &run.Result.StepResults[i]
+			stepResult.Destinations = proxy.Destinations(key, stepResult.ID)
+			var hosts []string
+			for _, destination := range stepResult.Destinations {
+				if destination.Denied {
+					hosts = append(hosts, fmt.Sprintf("'%s'", net.JoinHostPort(destination.Host, strconv.Itoa(destination.Port))))
+				}
+			}
+			if len(hosts) > 0 {
+				denied = append(denied, fmt.Sprintf("step '%s' tried to connect to %s", stepResult.ID, strings.Join(hosts, ", ")))
+			}
+		}
+		if len(denied) > 0 {
+			run.Result.EvaluateResult.Status = "ERROR"
+			run.Result.EvaluateResult.Reason = fmt.Sprintf("%s, which is not allowed by network.allow of autopilot '%s'", strings.Join(denied, " and "), run.AutopilotCheck.Autopilot.Name)
+			e.logger.Warnf("check '%s': %s", key, run.Result.EvaluateResult.Reason)
+		}
+	}
+}
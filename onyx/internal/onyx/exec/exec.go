@@ -8,31 +8,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/B-S-F/yaku/onyx/internal/onyx/common"
-	"github.com/B-S-F/yaku/onyx/pkg/configuration"
-	"github.com/B-S-F/yaku/onyx/pkg/finalize"
+	"github.com/B-S-F/yaku/onyx/internal/onyx/migrate"
 	"github.com/B-S-F/yaku/onyx/pkg/helper"
-	"github.com/B-S-F/yaku/onyx/pkg/item"
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/parameter"
 	"github.com/B-S-F/yaku/onyx/pkg/reader"
-	"github.com/B-S-F/yaku/onyx/pkg/replacer"
 	"github.com/B-S-F/yaku/onyx/pkg/repository"
-	"github.com/B-S-F/yaku/onyx/pkg/repository/app"
-	"github.com/B-S-F/yaku/onyx/pkg/repository/registry"
-	"github.com/B-S-F/yaku/onyx/pkg/result"
-	v1Result "github.com/B-S-F/yaku/onyx/pkg/result/v1"
 	"github.com/B-S-F/yaku/onyx/pkg/schema"
 	"github.com/B-S-F/yaku/onyx/pkg/tempdir"
-	"github.com/B-S-F/yaku/onyx/pkg/transformer"
 	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
 	model "github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/notifier"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/orchestrator"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/overlay"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/provenance"
 	replacerV2 "github.com/B-S-F/yaku/onyx/pkg/v2/replacer"
 	appV2 "github.com/B-S-F/yaku/onyx/pkg/v2/repository/app"
 	registryV2 "github.com/B-S-F/yaku/onyx/pkg/v2/repository/registry"
 	resultV2 "github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/schedule"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/service"
 	transformerV2 "github.com/B-S-F/yaku/onyx/pkg/v2/transformer"
 	"github.com/B-S-F/yaku/onyx/pkg/workdir"
 
@@ -40,7 +41,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
-	yaml "gopkg.in/yaml.v3"
 )
 
 const (
@@ -51,52 +51,63 @@ const (
 	SECRETS_FILE  = ".secrets"
 )
 
+const (
+	RESULT_VERSION_V1 = "v1"
+	RESULT_VERSION_V2 = "v2"
+)
+
 var (
 	ROOT_WORK_DIRECTORY = tempdir.GetPath("evidences")
 	APP_DIRECTORY       = tempdir.GetPath("apps")
+	SETUP_DIRECTORY     = tempdir.GetPath("setup")
 )
 
 func OverrideDirectoriesForTest(path string) {
 	ROOT_WORK_DIRECTORY = path + "/evidences"
 	APP_DIRECTORY = path + "/apps"
+	SETUP_DIRECTORY = path + "/setup"
+	INPUT_DIRECTORY = path + "/input"
 }
 
 type exec struct {
-	wdUtils         workdir.Utilizer
-	configCreator   common.ConfigCreator
-	schema          schema.SchemaHandler
-	resultEngine    result.ResultEngine
-	itemEngine      *item.Engine
-	finalizerEngine *finalize.Engine
-	transformer     []transformer.Transformer
-	transformerV2   []transformerV2.Transformer
-	logger          logger.Logger
-	execParams      parameter.ExecutionParameter
+	wdUtils       workdir.Utilizer
+	configCreator common.ConfigCreator
+	schema        schema.SchemaHandler
+	transformerV2 []transformerV2.Transformer
+	logger        logger.Logger
+	execParams    parameter.ExecutionParameter
+	// notifications of the onyx config which are sent in addition to the ones of the qg-config
+	notifications   []v2.Notification
+	resultVersion   string
+	rootWorkDir     string
+	appDir          string
+	setupDir        string
+	repositoryCache *repository.Cache
+	limiter         *orchestrator.Limiter
+	configFile      []byte
+	apps            []provenance.App
+	startedOn       time.Time
 }
 
-func newExec(execParams parameter.ExecutionParameter) *exec {
-	itemEngine := item.NewEngine(ROOT_WORK_DIRECTORY, execParams.Strict, execParams.CheckTimeout)
-	finalizeEngine := finalize.NewEngine(ROOT_WORK_DIRECTORY, execParams.CheckTimeout)
-	resultEngine := result.NewDefaultEngine(ROOT_WORK_DIRECTORY)
-	transformer := []transformer.Transformer{
-		transformer.NewAutopilotSkipper(execParams),
-		transformer.NewConfigsLoader(ROOT_WORK_DIRECTORY),
-	}
+func newExec(execParams parameter.ExecutionParameter, notifications []v2.Notification, rootWorkDir, appDir, setupDir string) *exec {
 	return &exec{
-		wdUtils:         workdir.NewUtils(afero.NewOsFs()),
-		configCreator:   &common.ConfigCreatorImpl{},
-		schema:          &schema.Schema{},
-		resultEngine:    resultEngine,
-		itemEngine:      itemEngine,
-		finalizerEngine: finalizeEngine,
-		transformer:     transformer,
-		logger:          logger.Get(),
-		execParams:      execParams,
-		transformerV2:   []transformerV2.Transformer{transformerV2.NewAutopilotSkipper(execParams), transformerV2.NewConfigsLoader(ROOT_WORK_DIRECTORY)},
+		wdUtils:       workdir.NewUtils(afero.NewOsFs()),
+		configCreator: &common.ConfigCreatorImpl{},
+		schema:        &schema.Schema{},
+		logger:        logger.Get(),
+		execParams:    execParams,
+		notifications: notifications,
+		transformerV2: []transformerV2.Transformer{transformerV2.NewAutopilotSkipper(execParams), transformerV2.NewConfigsLoader(rootWorkDir)},
+		resultVersion: execParams.ResultVersion,
+		rootWorkDir:   rootWorkDir,
+		appDir:        appDir,
+		setupDir:      setupDir,
+		limiter:       orchestrator.NewLimiter(execParams.MaxConcurrency),
 	}
 }
 
-func Exec(execParams parameter.ExecutionParameter) error {
+// Exec runs the config in the input folder, the notifications are sent in addition to the ones of the config
+func Exec(execParams parameter.ExecutionParameter, notifications []v2.Notification) error {
 	logger.Get().Info("[ PREPARATION ]")
 
 	configFile, vars, secrets, err := ReadFiles(execParams, reader.New())
@@ -112,11 +123,22 @@ func Exec(execParams parameter.ExecutionParameter) error {
 	}) // this logger prevents secrets from being logged
 
 	logger.Set(defaultLogger)
-	e := newExec(execParams)
-	err = e.prepareRootFolder(ROOT_WORK_DIRECTORY, execParams.InputFolder)
+	return newExec(execParams, notifications, ROOT_WORK_DIRECTORY, APP_DIRECTORY, SETUP_DIRECTORY).run(configFile, vars, secrets)
+}
+
+func (e *exec) run(configFile []byte, vars, secrets map[string]string) error {
+	e.startedOn = time.Now()
+	err := e.prepareRootFolder(e.rootWorkDir, e.execParams.InputFolder)
 	if err != nil {
 		return errors.Wrap(err, "error setting up root directory")
 	}
+	if len(e.execParams.Overlays) > 0 {
+		configFile, err = e.applyOverlays(configFile)
+		if err != nil {
+			return err
+		}
+	}
+	e.configFile = configFile
 
 	e.logger.Info("[ INITIALIZE EXECUTION PLAN ]")
 	e.logger.Info("parsing config file")
@@ -141,78 +163,161 @@ func Exec(execParams parameter.ExecutionParameter) error {
 
 	switch version {
 	case "v0", "v1":
-		configV1, ok := cfg.(configuration.Config)
-		if !ok {
-			return errors.Errorf("provided config for version '%s' is of unexpected type '%T'", version, configFile)
-		}
-		execPlan, err := e.initPlanV1(configV1, vars, secrets)
+		// legacy configs are migrated in memory and run on the v2 engine
+		e.logger.Infof("migrating config from version '%s' to version 'v2'", version)
+		migrated, err := migrate.Run(version, "v2", configFile)
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "error migrating config from version '%s'", version)
 		}
-		return e.execPlanV1(execPlan, vars, secrets)
-
-	case "v2":
-		configV2, ok := cfg.(*v2.Config)
-		if !ok {
-			return errors.Errorf("provided config for version '%s' is of unexpected type '%T'", version, configFile)
-		}
-
-		ep, err := e.initPlanV2(configV2, vars, secrets)
+		cfg, err = e.configCreator.New("v2", migrated)
 		if err != nil {
-			var userErr model.UserError
-			if errors.As(err, &userErr) {
-				e.logger.UserErrorf("initialization of execution plan failed: %s", userErr.Error())
-			}
-			return err
+			return errors.Wrap(err, "error creating migrated config")
 		}
+		if e.resultVersion == "" {
+			e.resultVersion = RESULT_VERSION_V1
+		}
+		return e.runV2(cfg, version, vars, secrets)
 
-		return e.execPlanV2(ep, secrets)
+	case "v2":
+		return e.runV2(cfg, version, vars, secrets)
 	default:
 		return errors.Errorf("unsupported version '%s'", version)
 	}
 }
 
-func (e *exec) execPlanV1(ep *configuration.ExecutionPlan, vars map[string]string, secrets map[string]string) error {
-	e.logger.Info("[ RUN EXECUTION PLAN ]")
-	err := e.executePlan(ep, vars, secrets)
-	if err != nil {
-		return errors.Wrap(err, "error executing execution plan")
+// applyOverlays patches the config with the overlay files, the patched config is validated and run instead of the original one
+func (e *exec) applyOverlays(configFile []byte) ([]byte, error) {
+	files := make([]string, 0, len(e.execParams.Overlays))
+	for _, name := range e.execParams.Overlays {
+		files = append(files, filepath.Join(e.execParams.InputFolder, name))
 	}
-	resultFilePath := filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE)
-	err = e.storeResultFile(e.resultEngine.GetResult(), resultFilePath)
+	e.logger.Infof("applying overlays %s to config file", strings.Join(e.execParams.Overlays, ", "))
+	patched, changes, err := overlay.ApplyFiles(configFile, files)
 	if err != nil {
-		return errors.Wrap(err, "error storing result file")
+		var userErr model.UserError
+		if errors.As(err, &userErr) {
+			e.logger.UserErrorf("error applying overlays: %s", userErr.Error())
+		}
+		return nil, err
 	}
-	e.logger.Info("[ RUN FINALIZER ]")
-	err = e.executeFinalizer(ep, secrets)
-	if err != nil {
-		return errors.Wrap(err, "error executing finalizer")
+	for _, change := range changes {
+		e.logger.Infof("overlay '%s': %s", change.Overlay, change.String())
+	}
+	return patched, nil
+}
+
+func (e *exec) runV2(cfg interface{}, version string, vars, secrets map[string]string) error {
+	configV2, ok := cfg.(*v2.Config)
+	if !ok {
+		return errors.Errorf("provided config for version '%s' is of unexpected type '%T'", version, cfg)
 	}
-	err = e.storeResultFile(e.resultEngine.GetResult(), resultFilePath)
+	if err := e.validateResultLogs(); err != nil {
+		e.logger.UserError(err.Error())
+		return err
+	}
+
+	ep, err := e.initPlanV2(configV2, vars, secrets)
 	if err != nil {
-		return errors.Wrap(err, "error storing result file")
+		var userErr model.UserError
+		if errors.As(err, &userErr) {
+			e.logger.UserErrorf("initialization of execution plan failed: %s", userErr.Error())
+		}
+		return err
 	}
-	err = e.provideResultFiles()
+	if version != "v2" {
+		err = transformerV2.NewLegacyEnv().Transform(ep)
+		if err != nil {
+			return errors.Wrap(err, "error transforming execution plan")
+		}
+	}
+	if err := e.validateHttpRecorder(ep); err != nil {
+		e.logger.UserError(err.Error())
+		return err
+	}
+
+	return e.execPlanV2(ep, vars, secrets)
+}
+
+// validateResultLogs checks that the logs can be provided as requested, results of version v1 can only embed them
+func (e *exec) validateResultLogs() error {
+	logMode, err := resultV2.ParseLogMode(e.execParams.ResultLogs)
 	if err != nil {
-		return errors.Wrap(err, "error providing result files")
+		return model.NewUserErr(err, "invalid result logs")
+	}
+	if e.resultVersion == RESULT_VERSION_V1 && logMode != resultV2.LogModeFull {
+		return model.NewUserErr(errors.Errorf("result-logs '%s' is not supported for results of version '%s', use result-version '%s'", logMode, RESULT_VERSION_V1, RESULT_VERSION_V2), "invalid result logs")
 	}
 	return nil
 }
 
-func (e *exec) execPlanV2(ep *model.ExecutionPlan, secrets map[string]string) error {
+func (e *exec) execPlanV2(ep *model.ExecutionPlan, vars, secrets map[string]string) error {
+	e.scheduleChecks(ep)
+	orchestrator := orchestrator.New(e.rootWorkDir, e.execParams.Strict, e.execParams.CheckTimeout, e.logger).WithLimiter(e.limiter).WithShell(e.execParams.Shell)
+	// services are stopped and teardown steps run even if the run fails before they are reached
+	services := service.New(e.rootWorkDir, e.execParams.Shell, e.logger)
+	e.startServices(services, ep, secrets)
+	servicesStopped := false
+	defer func() {
+		if !servicesStopped {
+			e.stopServices(services, ep)
+		}
+	}()
+	setupRuns, err := e.runSetup(orchestrator, ep, secrets)
+	if err != nil {
+		return errors.Wrap(err, "error running setup")
+	}
+	teardownDone := false
+	defer func() {
+		if !teardownDone {
+			e.runTeardown(orchestrator, ep, secrets)
+		}
+	}()
+	recorder, err := e.startHttpRecorder(ep, secrets)
+	if err != nil {
+		return err
+	}
+	// the http recorder and the egress proxy are never active together, see validateHttpRecorder
+	proxy, err := e.startEgressProxy(ep)
+	if err != nil {
+		_ = e.stopHttpRecorder(recorder)
+		return err
+	}
 	e.logger.Info("[ RUN EXECUTION PLAN ]")
-	orchestrator := orchestrator.New(ROOT_WORK_DIRECTORY, e.execParams.Strict, e.execParams.CheckTimeout, e.logger)
 	runResult, err := orchestrator.Run(ep.ManualChecks, ep.AutopilotChecks, ep.Env, secrets)
 	if err != nil {
+		e.stopEgressProxy(proxy, nil)
+		_ = e.stopHttpRecorder(recorder)
 		return errors.Wrap(err, "error executing execution plan")
 	}
-	resFilePath := filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE)
-	resCreator := resultV2.New(e.logger)
+	e.stopEgressProxy(proxy, &runResult)
+	if err := e.stopHttpRecorder(recorder); err != nil {
+		return err
+	}
+	resFilePath := filepath.Join(e.rootWorkDir, RESULT_FILE)
+	logMode, err := resultV2.ParseLogMode(e.execParams.ResultLogs)
+	if err != nil {
+		return model.NewUserErr(err, "invalid result logs")
+	}
+	resCreator := resultV2.New(e.logger).WithLogs(logMode, e.rootWorkDir)
+	if e.resultVersion == RESULT_VERSION_V1 {
+		resCreator = resCreator.WithOutputLines()
+	}
 	createdResult, err := resCreator.Create(*ep, runResult)
 	if err != nil {
 		return errors.Wrap(err, "error creating execution result")
 	}
-	err = resCreator.WriteResultFile(*createdResult, resFilePath)
+	createdResult.Header.Provenance = provenance.PROVENANCE_FILE
+	createdResult.Header.SBOM = provenance.SBOM_FILE
+	if e.execParams.TsaURL != "" {
+		createdResult.Header.Timestamp = evidence.TIMESTAMP_FILE
+	}
+	for _, setupRun := range setupRuns {
+		err = resCreator.AppendSetupRun(createdResult, setupRun)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.writeResultFile(resCreator, createdResult, resFilePath)
 	if err != nil {
 		return errors.Wrap(err, "error writing result file")
 	}
@@ -228,11 +333,80 @@ func (e *exec) execPlanV2(ep *model.ExecutionPlan, secrets map[string]string) er
 			return err
 		}
 
-		err = resCreator.WriteResultFile(*createdResult, resFilePath)
+		err = e.writeResultFile(resCreator, createdResult, resFilePath)
+		if err != nil {
+			return errors.Wrap(err, "error writing result file")
+		}
+	}
+	statusVars := resultV2.StatusVariables(createdResult)
+	for _, finalizer := range ep.Finalizers {
+		e.logger.Infof("[ RUN FINALIZER %s ]", strings.ToUpper(finalizer.Name))
+		finalizerRun := orchestrator.RunNamedFinalizer(finalizer, statusVars, ep.Env, secrets)
+		err = resCreator.AppendFinalizerRun(createdResult, finalizerRun)
+		if err != nil {
+			return err
+		}
+
+		// finalizers later in the list can read the outcome of the previous ones from the result file
+		err = e.writeResultFile(resCreator, createdResult, resFilePath)
+		if err != nil {
+			return errors.Wrap(err, "error writing result file")
+		}
+	}
+	teardownDone = true
+	teardownRuns := e.runTeardown(orchestrator, ep, secrets)
+	if len(teardownRuns) > 0 {
+		for _, teardownRun := range teardownRuns {
+			err = resCreator.AppendTeardownRun(createdResult, teardownRun)
+			if err != nil {
+				return err
+			}
+		}
+		err = e.writeResultFile(resCreator, createdResult, resFilePath)
+		if err != nil {
+			return errors.Wrap(err, "error writing result file")
+		}
+	}
+	servicesStopped = true
+	serviceRuns := e.stopServices(services, ep)
+	if len(serviceRuns) > 0 {
+		for _, serviceRun := range serviceRuns {
+			resCreator.AppendServiceRun(createdResult, serviceRun)
+		}
+		err = e.writeResultFile(resCreator, createdResult, resFilePath)
 		if err != nil {
 			return errors.Wrap(err, "error writing result file")
 		}
 	}
+	var historyStore *history.Store
+	var previousRun *history.Entry
+	if e.execParams.HistoryDir != "" {
+		historyStore = history.New(e.execParams.HistoryDir)
+		previousRun, err = historyStore.Latest(createdResult.Header.Name)
+		if err != nil {
+			e.logger.Warnf("failed to read history of previous runs: %s", err.Error())
+		}
+	}
+	if len(ep.Notifications) > 0 {
+		e.logger.Info("[ SEND NOTIFICATIONS ]")
+		for _, notification := range ep.Notifications {
+			resCreator.AppendNotificationRun(createdResult, notifier.Send(notification, createdResult, previousRun, time.Now()))
+		}
+		err = e.writeResultFile(resCreator, createdResult, resFilePath)
+		if err != nil {
+			return errors.Wrap(err, "error writing result file")
+		}
+	}
+	if historyStore != nil {
+		err = historyStore.Append(history.NewEntry(createdResult))
+		if err != nil {
+			e.logger.Warnf("failed to store run in history: %s", err.Error())
+		}
+	}
+	err = e.writeProvenance(resFilePath, vars)
+	if err != nil {
+		return errors.Wrap(err, "error writing provenance")
+	}
 	err = e.provideResultFiles()
 	if err != nil {
 		return errors.Wrap(err, "error providing result files")
@@ -240,6 +414,40 @@ func (e *exec) execPlanV2(ep *model.ExecutionPlan, secrets map[string]string) er
 	return nil
 }
 
+// scheduleChecks orders the automated checks so that the longest running ones are started first
+func (e *exec) scheduleChecks(ep *model.ExecutionPlan) {
+	known, err := schedule.Known(e.execParams.HistoryDir, ep.Header.Name, e.execParams.PreviousResult)
+	if err != nil {
+		e.logger.Warnf("failed to read durations of previous runs: %s", err.Error())
+	}
+	expected := schedule.Expected(ep.AutopilotChecks, known)
+	schedule.Order(ep.AutopilotChecks, expected)
+	e.logger.Infof("scheduling checks by expected duration, %d of %d automated checks have an expected duration", len(expected), len(ep.AutopilotChecks))
+}
+
+// writeProvenance records what produced the result file in the evidence, it must be called after the last change of the result file
+func (e *exec) writeProvenance(resFilePath string, vars map[string]string) error {
+	e.logger.Infof("storing provenance in '%s' and '%s'", provenance.PROVENANCE_FILE, provenance.SBOM_FILE)
+	finishedOn := time.Now()
+	statement, err := provenance.New(provenance.Run{
+		Config:     e.configFile,
+		Vars:       vars,
+		Apps:       e.apps,
+		Flags:      e.execParams.Flags,
+		ResultFile: resFilePath,
+		StartedOn:  e.startedOn,
+		FinishedOn: finishedOn,
+	})
+	if err != nil {
+		return err
+	}
+	err = provenance.WriteFile(statement, filepath.Join(e.rootWorkDir, provenance.PROVENANCE_FILE))
+	if err != nil {
+		return err
+	}
+	return provenance.WriteFile(provenance.NewSBOM(e.apps, finishedOn), filepath.Join(e.rootWorkDir, provenance.SBOM_FILE))
+}
+
 func (e *exec) prepareRootFolder(rootFolder, inputFolder string) error {
 	rootPath, err := e.wdUtils.CreateDir(rootFolder)
 	if err != nil {
@@ -258,50 +466,9 @@ func (e *exec) prepareRootFolder(rootFolder, inputFolder string) error {
 	return nil
 }
 
-func (e *exec) initPlanV1(config configuration.Config, vars, secrets map[string]string) (*configuration.ExecutionPlan, error) {
-	ep, err := config.Parse()
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating execution plan")
-	}
-
-	e.logger.Info("replacing parameters in execution plan")
-	err = replacer.Run(ep, vars, secrets, replacer.Initial)
-	if err != nil {
-		return nil, errors.Wrap(err, "error replacing parameters in execution plan")
-	}
-	e.logger.Info("transform execution plan")
-	for _, transformer := range e.transformer {
-		err = transformer.Transform(ep)
-		if err != nil {
-			return nil, errors.Wrap(err, "error transforming execution plan")
-		}
-	}
-	e.logger.Info("replacing config file parameters in execution plan")
-	err = replacer.Run(ep, vars, secrets, replacer.ConfigValues)
-	if err != nil {
-		return nil, errors.Wrap(err, "error replacing config file parameters second time in execution plan")
-	}
-	e.logger.Info("initializing repositories")
-	repositories, err := initializeRepository(ep.Repositories)
-	if err != nil {
-		return nil, errors.Wrap(err, "error parsing repositories")
-	}
-	e.logger.Info("initializing app registry")
-	appRegistry, err := initializeAppRegistry(ep, repositories)
-	if err != nil {
-		return nil, errors.Wrap(err, "error initializing app registry")
-	}
-	e.logger.Info(appRegistry.Stats())
-	e.logger.Info("configuring aliases in execution plan items")
-	err = e.initializeItemApps(ep, appRegistry)
-	if err != nil {
-		return nil, errors.Wrap(err, "error initializing item apps")
-	}
-	e.logger.Debug("execution plan", zap.String("execution plan", fmt.Sprintf("%+v", ep)))
-	return ep, nil
-}
-
 func (e *exec) initPlanV2(config *v2.Config, vars, secrets map[string]string) (*model.ExecutionPlan, error) {
+	config.Notifications = append(config.Notifications, e.notifications...)
+
 	e.logger.Info("executing custom config validation")
 	if err := v2.Validate(config); err != nil {
 		return nil, err
@@ -323,11 +490,37 @@ func (e *exec) initPlanV2(config *v2.Config, vars, secrets map[string]string) (*
 		}
 	}
 
+	e.logger.Info("evaluating applicability of checks in execution plan")
+	err = transformerV2.NewApplicability(helper.MergeMaps(ep.DefaultVars, vars), time.Now()).Transform(ep)
+	if err != nil {
+		var userErr model.UserError
+		if errors.As(err, &userErr) {
+			e.logger.UserErrorf("error evaluating applicability of checks: %s", userErr.Error())
+		}
+		return nil, err
+	}
+
+	e.logger.Info("checking secrets of secret files in execution plan")
+	err = transformerV2.NewSecretFilesChecker(secrets).Transform(ep)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking secret files")
+	}
+
+	e.logger.Info("rendering config templates in execution plan")
+	err = transformerV2.NewConfigTemplates(vars, secrets).Transform(ep)
+	if err != nil {
+		var userErr model.UserError
+		if errors.As(err, &userErr) {
+			e.logger.UserErrorf("error rendering config templates: %s", userErr.Error())
+		}
+		return nil, err
+	}
+
 	e.logger.Info("replacing config file parameters in execution plan")
 	replacerV2.Run(ep, vars, secrets, replacerV2.ConfigValues)
 
 	e.logger.Info("initializing repositories")
-	repositories, err := initializeRepository(ep.Repositories)
+	repositories, err := initializeRepository(ep.Repositories, e.repositoryCache)
 	if err != nil {
 		var userErr model.UserError
 		if errors.As(err, &userErr) {
@@ -343,8 +536,17 @@ func (e *exec) initPlanV2(config *v2.Config, vars, secrets map[string]string) (*
 	}
 
 	e.logger.Info(registry.Stats())
+	for _, installed := range registry.Apps() {
+		reference := installed.Reference()
+		e.apps = append(e.apps, provenance.App{
+			Repository: reference.Repository,
+			Name:       reference.Name,
+			Version:    reference.Version,
+			Checksum:   installed.Checksum(),
+		})
+	}
 	e.logger.Info("configuring aliases in execution plan items")
-	err = appV2.Initialize(ep, registry)
+	err = appV2.Initialize(ep, registry, e.appDir)
 	if err != nil {
 		return nil, errors.Wrap(err, "error initializing item apps")
 	}
@@ -373,105 +575,11 @@ func validateSchema(schema schema.SchemaHandler, config interface{}, content []b
 	return schema.Validate(content)
 }
 
-func initializeAppRegistry(ep *configuration.ExecutionPlan, repositories []repository.Repository) (*registry.Registry, error) {
-	appReferences := allAppReferences(ep)
-	appRegistry := registry.NewRegistry(repositories)
-	for _, appReference := range appReferences {
-		err := appRegistry.Install(appReference)
-		if err != nil {
-			return nil, errors.Wrap(err, "error adding app to registry")
-		}
-	}
-	return appRegistry, nil
-}
-
-func allAppReferences(ep *configuration.ExecutionPlan) []*app.Reference {
-	var appReferences []*app.Reference
-	for itemIndex := range ep.Items {
-		item := &ep.Items[itemIndex]
-		for _, itemAppReference := range item.AppReferences {
-			appReferences = append(appReferences, toRepositoryAppReference(itemAppReference))
-		}
-	}
-	return appReferences
-}
-
-func (e *exec) initializeItemApps(ep *configuration.ExecutionPlan, appRegistry *registry.Registry) error {
-	for index := range ep.Items {
-		item := &ep.Items[index]
-		for _, configAppReference := range item.AppReferences {
-			appReference := toRepositoryAppReference(configAppReference)
-			app, err := appRegistry.Get(appReference)
-			if err != nil {
-				return errors.Wrap(err, "error getting app")
-			}
-
-			appExecutablePath := app.ExecutablePath()
-			checkReference := fmt.Sprintf("%s_%s_%s", item.Chapter.Id, item.Requirement.Id, item.Check.Id)
-			checkAppDirectory := filepath.Join(APP_DIRECTORY, checkReference)
-			err = os.MkdirAll(checkAppDirectory, 0755)
-			if err != nil {
-				return errors.Wrapf(err, "error creating directory for app %s for check %s", app.Reference(), checkReference)
-			}
-			e.logger.Infof("configured app %s with checksum %s for check %s", app.Reference(), app.Checksum(), checkReference)
-
-			symlinks := app.PossibleReferences()
-			err = helper.CreateSymlinks(appExecutablePath, checkAppDirectory, symlinks)
-			if err != nil {
-				return errors.Wrap(err, "error creating symlinks")
-			}
-			item.AppPath = checkAppDirectory
-		}
-	}
-	return nil
-}
-
-func toRepositoryAppReference(ref *configuration.AppReference) *app.Reference {
-	return &app.Reference{
-		Repository: ref.Repository,
-		Name:       ref.Name,
-		Version:    ref.Version,
-	}
-}
-
-func (e *exec) executePlan(ep *configuration.ExecutionPlan, vars, secrets map[string]string) error {
-	itemResults, err := e.itemEngine.Run(&ep.Items, ep.Env, vars, secrets)
-	if err != nil {
-		return errors.Wrap(err, "error running execution plan")
-	}
-	e.resultEngine.CreateNewResult(ep, &itemResults)
-	return nil
-}
-
-func (e *exec) executeFinalizer(ep *configuration.ExecutionPlan, secrets map[string]string) error {
-	if ep.Finalize.Name != "" {
-		result, err := e.finalizerEngine.Run(&ep.Finalize, ep.Env, secrets)
-		if err != nil {
-			return errors.Wrap(err, "error running finalizer")
-		}
-		e.resultEngine.AppendFinalizerResult(result.Output)
+func (e *exec) writeResultFile(creator *resultV2.Creator, res *resultV2.Result, path string) error {
+	if e.resultVersion == RESULT_VERSION_V1 {
+		return creator.WriteV1ResultFile(*res, path)
 	}
-	return nil
-}
-
-func (e *exec) storeResultFile(data *v1Result.Result, path string) error {
-	e.logger.Info(fmt.Sprintf("storing results in result file '%s'", filepath.Base(path)))
-	resultYaml, err := yaml.Marshal(data)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling result")
-	}
-	out := common.SelectOutputWriter(path)
-	_, err = out.Write(resultYaml)
-	if err != nil {
-		return err
-	}
-	if out != os.Stdout {
-		err = out.Close()
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return creator.WriteResultFile(*res, path)
 }
 
 func (e *exec) provideResultFiles() error {
@@ -482,16 +590,36 @@ func (e *exec) provideResultFiles() error {
 			return errors.Wrap(err, "error creating output directory")
 		}
 	}
-	data, rerr := os.ReadFile(filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE))
+	data, rerr := os.ReadFile(filepath.Join(e.rootWorkDir, RESULT_FILE))
 	if rerr != nil {
 		rerr = errors.Wrap(rerr, "error copying result file")
 	} else {
 		rerr = os.WriteFile(filepath.Join(e.execParams.OutputFolder, RESULT_FILE), data, 0644)
 	}
+	if err := evidence.WriteManifest(e.rootWorkDir); err != nil {
+		return helper.Join(rerr, errors.Wrap(err, "error writing evidence manifest"))
+	}
+	terr := e.writeTimestamp()
 	zip := zip.New(afero.NewOsFs())
-	eerr := zip.Directory(ROOT_WORK_DIRECTORY, filepath.Join(e.execParams.OutputFolder, EVIDENCE_FILE))
+	eerr := zip.Directory(e.rootWorkDir, filepath.Join(e.execParams.OutputFolder, EVIDENCE_FILE))
 	if eerr != nil {
 		eerr = errors.Wrap(eerr, "error zipping evidence")
 	}
-	return helper.Join(rerr, eerr)
+	return helper.Join(rerr, terr, eerr)
+}
+
+// writeTimestamp stores a timestamp token of the evidence manifest in the evidence and next to the result file
+func (e *exec) writeTimestamp() error {
+	if e.execParams.TsaURL == "" {
+		return nil
+	}
+	e.logger.Infof("requesting timestamp of '%s' from '%s'", evidence.MANIFEST_FILE, e.execParams.TsaURL)
+	token, err := evidence.WriteTimestamp(e.rootWorkDir, e.execParams.TsaURL)
+	if err != nil {
+		return errors.Wrap(err, "error timestamping evidence")
+	}
+	if err := os.WriteFile(filepath.Join(e.execParams.OutputFolder, evidence.TIMESTAMP_FILE), token, 0644); err != nil {
+		return errors.Wrap(err, "error copying timestamp")
+	}
+	return nil
 }
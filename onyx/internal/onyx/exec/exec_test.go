@@ -8,385 +8,32 @@
 package exec
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 	"testing"
 	"time"
 
-	"github.com/B-S-F/yaku/onyx/internal/onyx/common"
-	"github.com/B-S-F/yaku/onyx/pkg/configuration"
 	v1 "github.com/B-S-F/yaku/onyx/pkg/configuration/versions/v1"
-	"github.com/B-S-F/yaku/onyx/pkg/finalize"
-	"github.com/B-S-F/yaku/onyx/pkg/item"
-	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/parameter"
-	"github.com/B-S-F/yaku/onyx/pkg/result"
 	resultv1 "github.com/B-S-F/yaku/onyx/pkg/result/v1"
-	"github.com/B-S-F/yaku/onyx/pkg/transformer"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/httprecorder"
+	resultv2 "github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/timestamp"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v3"
 )
 
-type mockSchema struct {
-	mock.Mock
-}
-
-func (s *mockSchema) Load(config interface{}) error {
-	args := s.Called(config)
-	return args.Error(0)
-}
-
-func (s *mockSchema) JSON() []byte {
-	args := s.Called()
-	return args.Get(0).([]byte)
-}
-
-func (s *mockSchema) Validate(content []byte) error {
-	args := s.Called(content)
-	return args.Error(0)
-}
-
-var configContent = `
-metadata:
-  version: v1
-autopilots:
-  autopilot1:
-    run: echo "Hello Autopilot 1!"
-chapters:
-  '1':
-    title: chapter 1
-    requirements:
-      '1':
-        title: requirement 1
-        text: requirement text 1
-        checks:
-          '1':
-            title: check 1
-            automation:
-              autopilot: autopilot1
-              config:
-                - config1.yaml
-          '2':
-            title: check 2
-            manual:
-              status: UNANSWERED
-              reason: Not answered yet
-          '3':
-            title: check 3
-            automation:
-              autopilot: autopilot1
-              config:
-                - config1.yaml
-`
-
-var configContentWithFinalizer = configContent + `
-finalize:
-  run: |
-    echo "Hello World!"
-`
-
-func TestInitPlan(t *testing.T) {
-	cases := []struct {
-		config           string
-		containsFinalize bool
-	}{
-		{
-			config:           configContent,
-			containsFinalize: false,
-		},
-		{
-			config:           configContentWithFinalizer,
-			containsFinalize: true,
-		},
-	}
-
-	for _, tc := range cases {
-		tmpDir := t.TempDir()
-		configContent := strings.TrimSpace(tc.config)
-		schema := &mockSchema{}
-		timeout := 10 * time.Minute
-		schema.On("Load", mock.Anything).Return(nil)
-		schema.On("Validate", mock.Anything).Return(nil)
-		e := &exec{
-			schema:          schema,
-			configCreator:   &common.ConfigCreatorImpl{},
-			resultEngine:    result.NewDefaultEngine(tmpDir),
-			itemEngine:      item.NewEngine(tmpDir, false, timeout),
-			finalizerEngine: finalize.NewEngine(tmpDir, timeout),
-			logger:          logger.Get(),
-			execParams: parameter.ExecutionParameter{
-				Strict: false,
-			},
-		}
-
-		var cfg *v1.Config
-		err := yaml.Unmarshal([]byte(configContent), &cfg)
-		require.NoError(t, err)
-
-		ep, err := e.initPlanV1(cfg, map[string]string{}, map[string]string{})
-		assert.NoError(t, err)
-		assert.NotNil(t, ep)
-
-		err = e.executePlan(ep, map[string]string{}, map[string]string{})
-		assert.NoError(t, err)
-		assert.NotNil(t, e.resultEngine.GetResult())
-
-		err = e.executeFinalizer(ep, nil)
-		assert.NoError(t, err)
-		assert.NotNil(t, e.resultEngine.GetResult())
-
-		foundFinalize := e.resultEngine.GetResult().Finalize != nil
-		assert.Equal(t, tc.containsFinalize, foundFinalize)
-	}
-}
-
-func TestNoSkipOnEmptyCheckIdentifier(t *testing.T) {
-	tmpDir := t.TempDir()
-	configContent := strings.TrimSpace(configContent)
-	schema := &mockSchema{}
-	schema.On("Load", mock.Anything).Return(nil)
-	schema.On("Validate", mock.Anything).Return(nil)
-	execParams := parameter.ExecutionParameter{
-		Strict:          false,
-		CheckIdentifier: "",
-		CheckTimeout:    10 * time.Minute,
-	}
-	transformer := []transformer.Transformer{
-		transformer.NewAutopilotSkipper(execParams),
-		transformer.NewConfigsLoader(ROOT_WORK_DIRECTORY),
-	}
-	e := &exec{
-		schema:          schema,
-		configCreator:   &common.ConfigCreatorImpl{},
-		resultEngine:    result.NewDefaultEngine(tmpDir),
-		itemEngine:      item.NewEngine(tmpDir, false, execParams.CheckTimeout),
-		finalizerEngine: finalize.NewEngine(tmpDir, execParams.CheckTimeout),
-		transformer:     transformer,
-		logger:          logger.Get(),
-		execParams:      execParams,
-	}
-
-	var cfg *v1.Config
-	err := yaml.Unmarshal([]byte(configContent), &cfg)
-	require.NoError(t, err)
-
-	ep, err := e.initPlanV1(cfg, map[string]string{}, map[string]string{})
-	assert.NoError(t, err)
-	assert.Equal(t, 3, len(ep.Items))
-	for _, item := range ep.Items {
-		assert.NotEqual(t, "SKIPPED", item.Manual.Status)
-	}
-}
-
-func TestInitPlanSingleCheck(t *testing.T) {
-	tmpDir := t.TempDir()
-	configContent := strings.TrimSpace(configContent)
-	schema := &mockSchema{}
-	schema.On("Load", mock.Anything).Return(nil)
-	schema.On("Validate", mock.Anything).Return(nil)
-	execParams := parameter.ExecutionParameter{
-		Strict:          false,
-		CheckIdentifier: "1_1_1",
-		CheckTimeout:    10 * time.Minute,
-	}
-	transformer := []transformer.Transformer{
-		transformer.NewAutopilotSkipper(execParams),
-		transformer.NewConfigsLoader(ROOT_WORK_DIRECTORY),
-	}
-	e := &exec{
-		schema:          schema,
-		configCreator:   &common.ConfigCreatorImpl{},
-		resultEngine:    result.NewDefaultEngine(tmpDir),
-		itemEngine:      item.NewEngine(tmpDir, false, execParams.CheckTimeout),
-		finalizerEngine: finalize.NewEngine(tmpDir, execParams.CheckTimeout),
-		transformer:     transformer,
-		logger:          logger.Get(),
-		execParams:      execParams,
-	}
-
-	getCheck := func(items []configuration.Item, check string) *configuration.Item {
-		for _, item := range items {
-			if item.Check.Id == check {
-				return &item
-			}
-		}
-		return nil
-	}
-
-	var cfg *v1.Config
-	err := yaml.Unmarshal([]byte(configContent), &cfg)
-	require.NoError(t, err)
-
-	ep, err := e.initPlanV1(cfg, map[string]string{}, map[string]string{})
-	assert.NoError(t, err)
-	assert.Equal(t, 3, len(ep.Items))
-	assert.Equal(t, "SKIPPED", getCheck(ep.Items, "3").Manual.Status)
-	assert.Equal(t, "UNANSWERED", getCheck(ep.Items, "2").Manual.Status)
-	assert.Equal(t, configuration.Manual{}, getCheck(ep.Items, "1").Manual)
-	assert.Equal(t, "", ep.Finalize.Autopilot.Run)
-}
-
-func TestInitPlanSingleCheckIsManual(t *testing.T) {
-	tmpDir := t.TempDir()
-	configContent := strings.TrimSpace(configContent)
-	schema := &mockSchema{}
-	schema.On("Load", mock.Anything).Return(nil)
-	schema.On("Validate", mock.Anything).Return(nil)
-	execParams := parameter.ExecutionParameter{
-		Strict:          false,
-		CheckIdentifier: "1_1_2",
-		CheckTimeout:    10 * time.Minute,
-	}
-	transformer := []transformer.Transformer{
-		transformer.NewAutopilotSkipper(execParams),
-		transformer.NewConfigsLoader(ROOT_WORK_DIRECTORY),
-	}
-	e := &exec{
-		schema:          schema,
-		configCreator:   &common.ConfigCreatorImpl{},
-		resultEngine:    result.NewDefaultEngine(tmpDir),
-		itemEngine:      item.NewEngine(tmpDir, false, execParams.CheckTimeout),
-		finalizerEngine: finalize.NewEngine(tmpDir, execParams.CheckTimeout),
-		transformer:     transformer,
-		logger:          logger.Get(),
-		execParams:      execParams,
-	}
-
-	var cfg *v1.Config
-	err := yaml.Unmarshal([]byte(configContent), &cfg)
-	require.NoError(t, err)
-
-	_, err = e.initPlanV1(cfg, map[string]string{}, map[string]string{})
-	assert.Error(t, err)
-}
-
-func TestAllAppReferences(t *testing.T) {
-	app1RepoTest := configuration.AppReference{
-		Name:       "app1",
-		Version:    "1.0.0",
-		Repository: "test",
-	}
-	app2NoRepo := configuration.AppReference{
-		Name:    "app2",
-		Version: "1.0.0",
-	}
-	app1NoRepo := configuration.AppReference{
-		Name:    "app1",
-		Version: "1.0.0",
-	}
-	executionPlan := &configuration.ExecutionPlan{
-		Items: []configuration.Item{
-			{
-				Chapter: configuration.Chapter{
-					Id: "1",
-				},
-				Requirement: configuration.Requirement{
-					Id: "1",
-				},
-				Check: configuration.Check{
-					Id: "1",
-				},
-				AppReferences: []*configuration.AppReference{
-					&app1RepoTest,
-					&app2NoRepo,
-				},
-			},
-			{
-				Chapter: configuration.Chapter{
-					Id: "1",
-				},
-				Requirement: configuration.Requirement{
-					Id: "1",
-				},
-				Check: configuration.Check{
-					Id: "3",
-				},
-				AppReferences: []*configuration.AppReference{
-					&app1NoRepo,
-				},
-			},
-		},
-	}
-
-	appReferences := allAppReferences(executionPlan)
-
-	assert.Equal(t, 3, len(appReferences))
-	assert.Equal(t, app1RepoTest.Name, appReferences[0].Name)
-	assert.Equal(t, app1RepoTest.Version, appReferences[0].Version)
-	assert.Equal(t, app1RepoTest.Repository, appReferences[0].Repository)
-
-	assert.Equal(t, app2NoRepo.Name, appReferences[1].Name)
-	assert.Equal(t, app2NoRepo.Version, appReferences[1].Version)
-	assert.Equal(t, app2NoRepo.Repository, appReferences[1].Repository)
-
-	assert.Equal(t, app1NoRepo.Name, appReferences[2].Name)
-	assert.Equal(t, app1NoRepo.Version, appReferences[2].Version)
-	assert.Equal(t, app1NoRepo.Repository, appReferences[2].Repository)
-}
-
-func TestStoreResultFile(t *testing.T) {
-	resultData := &resultv1.Result{
-		Metadata: resultv1.Metadata{
-			Version: "v1",
-		},
-		Chapters: map[string]*resultv1.Chapter{
-			"1": {
-				Title:  "chapter 1",
-				Status: "GREEN",
-				Requirements: map[string]*resultv1.Requirement{
-					"1": {
-						Title:  "requirement 1",
-						Status: "GREEN",
-						Checks: map[string]*resultv1.Check{
-							"1": {
-								Title:  "check 1",
-								Status: "GREEN",
-								Type:   "autopilot",
-								Evaluation: resultv1.CheckResult{
-									Status: "GREEN",
-									Reason: "This is my reason",
-									Results: []resultv1.AutopilotResult{
-										{
-											Criterion:     "This is my criterion",
-											Fulfilled:     true,
-											Justification: "  Line 1\n  Line 2\nLine 3",
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	schema := &mockSchema{}
-	schema.On("Load", mock.Anything).Return(nil)
-	schema.On("Validate", mock.Anything).Return(nil)
-
-	tmpDir := t.TempDir()
-	timeout := 10 * time.Minute
-	e := &exec{
-		schema:          schema,
-		configCreator:   &common.ConfigCreatorImpl{},
-		resultEngine:    result.NewDefaultEngine(tmpDir),
-		itemEngine:      item.NewEngine(tmpDir, false, timeout),
-		finalizerEngine: finalize.NewEngine(tmpDir, timeout),
-		logger:          logger.Get(),
-	}
-
-	err := e.storeResultFile(resultData, filepath.Join(tmpDir, "qg-result.json"))
-	assert.NoError(t, err)
-	_, err = os.Stat(filepath.Join(tmpDir, "qg-result.json"))
-	assert.NoError(t, err)
-}
-
 func TestExecErrors(t *testing.T) {
 	tests := map[string]struct {
 		execParams parameter.ExecutionParameter
@@ -563,7 +210,7 @@ header:
 
 			tt.prep(t, tt.execParams.InputFolder)
 
-			err := Exec(tt.execParams)
+			err := Exec(tt.execParams, nil)
 			require.Equal(t, err != nil, tt.want != nil)
 			if tt.want != nil {
 				require.ErrorContains(t, err, tt.want.Error())
@@ -689,7 +336,7 @@ func TestExecUserErrors(t *testing.T) {
 
 			tt.prep(t, tt.execParams.InputFolder)
 
-			err := Exec(tt.execParams)
+			err := Exec(tt.execParams, nil)
 			require.Equal(t, err != nil, tt.want != nil)
 			if tt.want != nil {
 				assert.ErrorContains(t, err, tt.want.Error())
@@ -729,7 +376,7 @@ func TestExecBackwardsCompatibilityQGConfigV1(t *testing.T) {
 		CheckTimeout: 10 * 60 * time.Second,
 	}
 
-	err = Exec(execParams)
+	err = Exec(execParams, nil)
 	assert.NoError(t, err)
 
 	// qg-result.yaml file should exist
@@ -785,13 +432,770 @@ func TestExecBackwardsCompatibilityQGConfigV1(t *testing.T) {
 	assert.Equal(t, result.Chapters["1"].Requirements["1"].Checks["1"].Evaluation.Outputs["output2"], "out2")
 }
 
-func simpleResultV1() *resultv1.Result {
-	return &resultv1.Result{
-		Metadata: resultv1.Metadata{Version: "v1"},
-		Header:   resultv1.Header{Name: "testv1", Version: "1.0"},
+func TestExecQGConfigV1WithResultVersionV2(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+
+	cfg, err := yaml.Marshal(simpleConfigV1())
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfg, 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, ".vars"), nil, 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, ".secrets"), nil, 0644)
+	require.NoError(t, err)
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:    "qg-config.yaml",
+		InputFolder:   tmpDir,
+		VarsName:      ".vars",
+		SecretsName:   ".secrets",
+		OutputFolder:  tmpDir,
+		CheckTimeout:  10 * 60 * time.Second,
+		ResultVersion: "v2",
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+
+	var result resultv2.Result
+	err = yaml.Unmarshal(resFile, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2", result.Metadata.Version)
+	assert.Equal(t, "GREEN", result.OverallStatus)
+	check := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "automation", check.Type)
+	assert.Equal(t, "checker", check.Autopilots[0].Name)
+	assert.Equal(t, "This is a reason", check.Evaluation.Reason)
+	assert.Equal(t, "provenance.json", result.Header.Provenance)
+	assert.Equal(t, "sbom.cdx.json", result.Header.SBOM)
+	assert.FileExists(t, filepath.Join(tmpDir, "exec", "evidences", "provenance.json"))
+	assert.FileExists(t, filepath.Join(tmpDir, "exec", "evidences", "sbom.cdx.json"))
+}
+
+func TestExecQGConfigV1LegacyAutopilots(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+
+	cfgV1 := simpleConfigV1()
+	cfgV1.Autopilots["checker"] = v1.Autopilot{
+		Run: `echo '{"status": "FAILED", "reason": "This is a reason"}'`,
+	}
+	check := cfgV1.Chapters["1"].Requirements["1"].Checks["1"]
+	check.Title = "check ${{ env.NAME }}"
+	check.Automation.Env = map[string]string{"NAME": "one"}
+	cfgV1.Chapters["1"].Requirements["1"].Checks["1"] = check
+
+	cfg, err := yaml.Marshal(cfgV1)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfg, 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, ".vars"), nil, 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, ".secrets"), nil, 0644)
+	require.NoError(t, err)
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+
+	var result resultv1.Result
+	err = yaml.Unmarshal(resFile, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "FAILED", result.OverallStatus)
+	resultCheck := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "check one", resultCheck.Title)
+	assert.Equal(t, "FAILED", resultCheck.Status)
+	assert.Equal(t, "FAILED", resultCheck.Evaluation.Status)
+	assert.Equal(t, "This is a reason", resultCheck.Evaluation.Reason)
+	assert.Equal(t, []string{`{"status": "FAILED", "reason": "This is a reason"}`}, resultCheck.Evaluation.Execution.Logs)
+}
+
+func TestExecInlineConfigs(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		status  string
+		wantLog string
+	}{
+		"should materialise inline config with replaced variables": {
+			content: `{"status": "${{ vars.STATUS }}", "reason": "from inline config"}`,
+			status:  "GREEN",
+		},
+		"should not allow secrets in inline config": {
+			content: `{"status": "${{ secrets.TOKEN }}"}`,
+			status:  "ERROR",
+			wantLog: "secrets are not allowed in config files: found 1 secrets in file 'status.json'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfg := simpleConfigV2()
+			cfg.Autopilots["checker"] = config.Autopilot{
+				Evaluate: config.Evaluate{
+					Config: []config.ConfigFile{{Name: "status.json", Content: &tc.content}},
+					Run:    "cat status.json",
+				},
+			}
+			cfgContent, err := yaml.Marshal(cfg)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{"STATUS": "GREEN"}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{"TOKEN": "secret-token"}`), 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:   "qg-config.yaml",
+				InputFolder:  tmpDir,
+				VarsName:     ".vars",
+				SecretsName:  ".secrets",
+				OutputFolder: tmpDir,
+				CheckTimeout: 10 * 60 * time.Second,
+			}, nil)
+			require.NoError(t, err)
+
+			resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+			require.NoError(t, err)
+			assert.NotContains(t, string(resFile), "secret-token")
+			var result resultv2.Result
+			require.NoError(t, yaml.Unmarshal(resFile, &result))
+			check := result.Chapters["1"].Requirements["1"].Checks["1"]
+			assert.Equal(t, tc.status, check.Evaluation.Status)
+			assert.Equal(t, []string{"status.json"}, check.Evaluation.ConfigFiles)
+			if tc.wantLog != "" {
+				log, err := os.ReadFile(filepath.Join(tmpDir, "onyx.log"))
+				require.NoError(t, err)
+				assert.Contains(t, string(log), tc.wantLog)
+			}
+		})
+	}
+}
+
+func TestExecResultLogs(t *testing.T) {
+	testCases := map[string]struct {
+		mode        string
+		wantLogs    bool
+		wantLogsRef bool
+	}{
+		"should embed logs by default": {
+			wantLogs: true,
+		},
+		"should reference logs in the evidence": {
+			mode:        "reference",
+			wantLogsRef: true,
+		},
+		"should leave out logs": {
+			mode: "none",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfgContent, err := yaml.Marshal(simpleConfigV2())
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:   "qg-config.yaml",
+				InputFolder:  tmpDir,
+				VarsName:     ".vars",
+				SecretsName:  ".secrets",
+				OutputFolder: tmpDir,
+				CheckTimeout: 10 * 60 * time.Second,
+				ResultLogs:   tc.mode,
+			}, nil)
+			require.NoError(t, err)
+
+			resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+			require.NoError(t, err)
+			var result resultv2.Result
+			require.NoError(t, yaml.Unmarshal(resFile, &result))
+			evaluation := result.Chapters["1"].Requirements["1"].Checks["1"].Evaluation
+			assert.Equal(t, tc.wantLogs, len(evaluation.Logs) > 0)
+			if !tc.wantLogsRef {
+				assert.Nil(t, evaluation.LogsRef)
+				return
+			}
+			require.NotNil(t, evaluation.LogsRef)
+			assert.Equal(t, "1_1_1/evaluation/logs.jsonl", evaluation.LogsRef.Path)
+			archive, err := evidence.Open(filepath.Join(tmpDir, "evidence.zip"))
+			require.NoError(t, err)
+			defer archive.Close()
+			logs, err := archive.ReadFile(evaluation.LogsRef.Path)
+			require.NoError(t, err)
+			assert.Len(t, logs, int(evaluation.LogsRef.Size))
+		})
 	}
 }
 
+func TestExecResultLogsV1(t *testing.T) {
+	testCases := map[string]struct {
+		mode          string
+		resultVersion string
+		wantErr       string
+	}{
+		"should embed logs in v1 results": {
+			mode: "full",
+		},
+		"should reject referenced logs in v1 results": {
+			mode:    "reference",
+			wantErr: "result-logs 'reference' is not supported for results of version 'v1'",
+		},
+		"should reject left out logs in v1 results": {
+			mode:    "none",
+			wantErr: "result-logs 'none' is not supported for results of version 'v1'",
+		},
+		"should reference logs in v2 results of v1 configs": {
+			mode:          "reference",
+			resultVersion: "v2",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfgContent, err := yaml.Marshal(simpleConfigV1())
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), nil, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), nil, 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:    "qg-config.yaml",
+				InputFolder:   tmpDir,
+				VarsName:      ".vars",
+				SecretsName:   ".secrets",
+				OutputFolder:  tmpDir,
+				CheckTimeout:  10 * 60 * time.Second,
+				ResultLogs:    tc.mode,
+				ResultVersion: tc.resultVersion,
+			}, nil)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(tmpDir, "qg-result.yaml"))
+		})
+	}
+}
+
+func TestExecConditionsWithDefaultVars(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfg := simpleConfigV2()
+	cfg.Default.Vars = map[string]string{"STAGE": "dev"}
+	check := cfg.Chapters["1"].Requirements["1"].Checks["1"]
+	check.When = `vars.STAGE == "prod"`
+	cfg.Chapters["1"].Requirements["1"].Checks["1"] = check
+	cfgContent, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	assert.Equal(t, "NA", result.Chapters["1"].Requirements["1"].Checks["1"].Evaluation.Status)
+}
+
+func TestExecOverlays(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfgContent, err := yaml.Marshal(simpleConfigV2())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "prod.yaml"), []byte(`chapters:
+  "1":
+    requirements:
+      "1":
+        checks:
+          "1":
+            automation: null
+            manual:
+              status: GREEN
+              reason: answered by the prod overlay
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+		Overlays:     []string{"prod.yaml"},
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	check := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "manual", check.Type)
+	assert.Equal(t, "GREEN", check.Evaluation.Status)
+	assert.Equal(t, "answered by the prod overlay", check.Evaluation.Reason)
+}
+
+func TestExecSetupAndTeardown(t *testing.T) {
+	tests := map[string]struct {
+		setupRun         string
+		wantSetupStatus  string
+		wantCheckStatus  string
+		wantCheckReason  string
+		wantTeardownLogs []string
+	}{
+		"should export env and outputs of setup to checks": {
+			setupRun: `echo "GREETING=hello" >> "$SETUP_ENV"
+echo "TOKEN=s3cr3t" >> "$SETUP_SECRET_ENV"
+echo "world" > "$SETUP_OUTPUT_DIR/name.txt"`,
+			wantSetupStatus:  "SUCCEEDED",
+			wantCheckStatus:  "GREEN",
+			wantCheckReason:  "hello world",
+			wantTeardownLogs: []string{`{"source":"stdout","text":"logout ***TOKEN***"}`},
+		},
+		"should not execute checks but run teardown if setup fails": {
+			setupRun:         "exit 1",
+			wantSetupStatus:  "FAILED",
+			wantCheckStatus:  "ERROR",
+			wantTeardownLogs: []string{`{"source":"stdout","text":"logout "}`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfg := simpleConfigV2()
+			cfg.Autopilots["checker"] = config.Autopilot{
+				Evaluate: config.Evaluate{
+					Run: `echo "{\"status\": \"GREEN\", \"reason\": \"$GREETING $(cat "$SETUP_OUTPUT_DIR/name.txt")\"}"`,
+				},
+			}
+			cfg.Setup = []config.Hook{{Name: "login", Run: tt.setupRun}}
+			cfg.Teardown = []config.Hook{{Name: "logout", Run: `echo "logout $TOKEN"`}}
+			cfgContent, err := yaml.Marshal(cfg)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:   "qg-config.yaml",
+				InputFolder:  tmpDir,
+				VarsName:     ".vars",
+				SecretsName:  ".secrets",
+				OutputFolder: tmpDir,
+				CheckTimeout: 10 * 60 * time.Second,
+			}, nil)
+			require.NoError(t, err)
+
+			resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+			require.NoError(t, err)
+			var result resultv2.Result
+			require.NoError(t, yaml.Unmarshal(resFile, &result))
+			require.Len(t, result.Setup, 1)
+			assert.Equal(t, tt.wantSetupStatus, result.Setup[0].Status)
+			check := result.Chapters["1"].Requirements["1"].Checks["1"]
+			assert.Equal(t, tt.wantCheckStatus, check.Evaluation.Status)
+			if tt.wantCheckReason != "" {
+				assert.Equal(t, tt.wantCheckReason, check.Evaluation.Reason)
+			}
+			require.Len(t, result.Teardown, 1)
+			assert.Equal(t, "SUCCEEDED", result.Teardown[0].Status)
+			assert.Equal(t, tt.wantTeardownLogs, result.Teardown[0].Logs)
+			assert.NoDirExists(t, SETUP_DIRECTORY)
+		})
+	}
+}
+
+func TestExecServices(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfg := simpleConfigV2()
+	cfg.Autopilots["checker"] = config.Autopilot{
+		Evaluate: config.Evaluate{
+			Run: `echo "{\"status\": \"GREEN\", \"reason\": \"$SERVICE_MOCK_SERVER_HOST\"}"`,
+		},
+	}
+	cfg.Services = []config.Service{{Name: "mock-server", Run: "touch ready; sleep 30", HealthCheck: "test -f ready"}}
+	cfgContent, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	check := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "GREEN", check.Evaluation.Status)
+	assert.Equal(t, "localhost", check.Evaluation.Reason)
+	assert.Equal(t, []resultv2.Service{{Name: "mock-server", Status: "STOPPED", LogFile: "service_mock-server.log"}}, result.Services)
+}
+
+func TestExecHttpRecorderReplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfg := simpleConfigV2()
+	cfg.Autopilots["checker"] = config.Autopilot{
+		Evaluate: config.Evaluate{
+			Run: `curl -sf http://records.example/status`,
+		},
+	}
+	cfgContent, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+	cassetteDir := filepath.Join(tmpDir, "cassettes")
+	require.NoError(t, httprecorder.WriteCassette(httprecorder.CassetteFile(cassetteDir, "1_1_1"), &httprecorder.Cassette{
+		Interactions: []httprecorder.Interaction{{
+			Request:  httprecorder.Request{Method: "GET", URL: "http://records.example/status"},
+			Response: httprecorder.Response{Status: 200, Body: `{"status": "GREEN", "reason": "replayed"}`},
+		}},
+	}))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+		HttpRecorder: &parameter.HttpRecorder{Mode: httprecorder.ModeReplay, CassetteDir: cassetteDir},
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	check := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "GREEN", check.Evaluation.Status)
+	assert.Equal(t, "replayed", check.Evaluation.Reason)
+}
+
+func TestExecHttpRecorderNetwork(t *testing.T) {
+	testCases := map[string]struct {
+		auditNetwork bool
+		network      *config.Network
+		wantErr      string
+	}{
+		"should replay without network settings": {},
+		"should reject network settings": {
+			network: &config.Network{Allow: []string{"records.example"}},
+			wantErr: "network.allow of autopilot 'checker' can not be enforced while the http recorder is active",
+		},
+		"should reject auditing the network": {
+			auditNetwork: true,
+			wantErr:      "the network can not be audited while the http recorder is active",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfg := simpleConfigV2()
+			cfg.Autopilots["checker"] = config.Autopilot{
+				Network: tc.network,
+				Evaluate: config.Evaluate{
+					Run: `echo '{"status": "GREEN", "reason": "replayed"}'`,
+				},
+			}
+			cfgContent, err := yaml.Marshal(cfg)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:   "qg-config.yaml",
+				InputFolder:  tmpDir,
+				VarsName:     ".vars",
+				SecretsName:  ".secrets",
+				OutputFolder: tmpDir,
+				CheckTimeout: 10 * 60 * time.Second,
+				AuditNetwork: tc.auditNetwork,
+				HttpRecorder: &parameter.HttpRecorder{Mode: httprecorder.ModeReplay, CassetteDir: filepath.Join(tmpDir, "cassettes")},
+			}, nil)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.NoFileExists(t, filepath.Join(tmpDir, "qg-result.yaml"))
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(tmpDir, "qg-result.yaml"))
+		})
+	}
+}
+
+func TestExecNetworkAllow(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer upstream.Close()
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfg := simpleConfigV2()
+	cfg.Autopilots["checker"] = config.Autopilot{
+		Network: &config.Network{Allow: []string{"127.0.0.1"}},
+		Steps: []config.Step{{
+			ID: "fetch",
+			// localhost is excluded from the proxy by NO_PROXY, so the proxy is enforced for the test
+			Run: fmt.Sprintf("curl -sf --noproxy '' %s/status\ncurl -s --noproxy '' http://denied.example/ || true", upstream.URL),
+		}},
+		Evaluate: config.Evaluate{
+			Run: `echo '{"status": "GREEN", "reason": "fetched"}'`,
+		},
+	}
+	cfgContent, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	check := result.Chapters["1"].Requirements["1"].Checks["1"]
+	assert.Equal(t, "ERROR", check.Evaluation.Status)
+	assert.Equal(t, "step 'fetch' tried to connect to 'denied.example:80', which is not allowed by network.allow of autopilot 'checker'", check.Evaluation.Reason)
+	port := upstream.Listener.Addr().(*net.TCPAddr).Port
+	assert.Equal(t, []resultv2.Destination{
+		{Host: "127.0.0.1", Port: port, Requests: 1},
+		{Host: "denied.example", Port: 80, Requests: 1, Denied: true},
+	}, check.Autopilots[0].Steps[0].Destinations)
+}
+
+func TestExecNetworkAllowWithProxyEnv(t *testing.T) {
+	var proxied []string
+	var mu sync.Mutex
+	// the proxy of the autopilot, e.g. a company proxy, receives the allowed requests from the egress proxy
+	companyProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, req.URL.String())
+		mu.Unlock()
+		fmt.Fprint(w, "ok")
+	}))
+	defer companyProxy.Close()
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfg := simpleConfigV2()
+	cfg.Autopilots["checker"] = config.Autopilot{
+		Network: &config.Network{Allow: []string{"allowed.example"}},
+		// curl only reads the lower case http_proxy
+		Env: map[string]string{
+			"http_proxy":  companyProxy.URL,
+			"HTTPS_PROXY": companyProxy.URL,
+			"NO_PROXY":    "internal.example",
+		},
+		Steps: []config.Step{{
+			ID:  "fetch",
+			Run: "curl -sf http://allowed.example/status\ncurl -s http://denied.example/ || true",
+		}},
+		Evaluate: config.Evaluate{
+			Run: `echo '{"status": "GREEN", "reason": "fetched"}'`,
+		},
+	}
+	cfgContent, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	for _, id := range []string{"1", "2"} {
+		check := result.Chapters["1"].Requirements["1"].Checks[id]
+		assert.Equal(t, "ERROR", check.Evaluation.Status)
+		assert.Equal(t, "step 'fetch' tried to connect to 'denied.example:80', which is not allowed by network.allow of autopilot 'checker'", check.Evaluation.Reason)
+		assert.Equal(t, []resultv2.Destination{
+			{Host: "allowed.example", Port: 80, Requests: 1},
+			{Host: "denied.example", Port: 80, Requests: 1, Denied: true},
+		}, check.Autopilots[0].Steps[0].Destinations)
+	}
+	assert.Equal(t, []string{"http://allowed.example/status", "http://allowed.example/status"}, proxied)
+}
+
+func TestExecAuditNetwork(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer upstream.Close()
+	port := upstream.Listener.Addr().(*net.TCPAddr).Port
+	testCases := map[string]struct {
+		auditNetwork     bool
+		wantLog          string
+		wantDestinations []resultv2.Destination
+	}{
+		"should not route the steps through the proxy by default": {
+			wantLog: `{"source":"stdout","text":"no_proxy=internal.example.com proxy="}`,
+		},
+		"should record the destinations of all steps if the network is audited": {
+			auditNetwork:     true,
+			wantLog:          `{"source":"stdout","text":"no_proxy=internal.example.com,localhost,127.0.0.1 proxy=set"}`,
+			wantDestinations: []resultv2.Destination{{Host: "127.0.0.1", Port: port, Requests: 1}},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+			cfg := simpleConfigV2()
+			cfg.Env = map[string]string{"NO_PROXY": "internal.example.com"}
+			cfg.Autopilots["checker"] = config.Autopilot{
+				Steps: []config.Step{{
+					ID: "fetch",
+					// localhost is excluded from the proxy by NO_PROXY, so the proxy is used explicitly
+					Run: fmt.Sprintf("echo \"no_proxy=$NO_PROXY proxy=${HTTP_PROXY:+set}\"\ncurl -sf --noproxy '' -o /dev/null %s/status", upstream.URL),
+				}},
+				Evaluate: config.Evaluate{
+					Run: `echo '{"status": "GREEN", "reason": "fetched"}'`,
+				},
+			}
+			cfgContent, err := yaml.Marshal(cfg)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+			err = Exec(parameter.ExecutionParameter{
+				ConfigName:   "qg-config.yaml",
+				InputFolder:  tmpDir,
+				VarsName:     ".vars",
+				SecretsName:  ".secrets",
+				OutputFolder: tmpDir,
+				CheckTimeout: 10 * 60 * time.Second,
+				AuditNetwork: tc.auditNetwork,
+			}, nil)
+			require.NoError(t, err)
+
+			resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+			require.NoError(t, err)
+			var result resultv2.Result
+			require.NoError(t, yaml.Unmarshal(resFile, &result))
+			check := result.Chapters["1"].Requirements["1"].Checks["1"]
+			assert.Equal(t, "GREEN", check.Evaluation.Status)
+			assert.Equal(t, []string{tc.wantLog}, check.Autopilots[0].Steps[0].Logs)
+			assert.Equal(t, tc.wantDestinations, check.Autopilots[0].Steps[0].Destinations)
+		})
+	}
+}
+
+func TestExecTimestamp(t *testing.T) {
+	authority, err := timestamp.NewLocalAuthority()
+	require.NoError(t, err)
+	tsa := httptest.NewServer(authority)
+	defer tsa.Close()
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	cfgContent, err := yaml.Marshal(simpleConfigV2())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qg-config.yaml"), cfgContent, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vars"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".secrets"), []byte(`{}`), 0644))
+
+	err = Exec(parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		InputFolder:  tmpDir,
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: tmpDir,
+		CheckTimeout: 10 * 60 * time.Second,
+		TsaURL:       tsa.URL,
+	}, nil)
+	require.NoError(t, err)
+
+	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
+	require.NoError(t, err)
+	var result resultv2.Result
+	require.NoError(t, yaml.Unmarshal(resFile, &result))
+	assert.Equal(t, evidence.TIMESTAMP_FILE, result.Header.Timestamp)
+
+	archive, err := evidence.Open(filepath.Join(tmpDir, EVIDENCE_FILE))
+	require.NoError(t, err)
+	defer archive.Close()
+	token, err := os.ReadFile(filepath.Join(tmpDir, evidence.TIMESTAMP_FILE))
+	require.NoError(t, err)
+	archived, err := archive.ReadFile(evidence.TIMESTAMP_FILE)
+	require.NoError(t, err)
+	assert.Equal(t, token, archived)
+	manifest, err := archive.ReadFile(evidence.MANIFEST_FILE)
+	require.NoError(t, err)
+	parsed, err := timestamp.Parse(token)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(authority.CA())
+	digest := sha256.Sum256(manifest)
+	assert.NoError(t, parsed.Verify(digest[:], roots))
+}
+
 func simpleConfigV1() *v1.Config {
 	return &v1.Config{
 		Metadata: v1.Metadata{Version: "v1"},
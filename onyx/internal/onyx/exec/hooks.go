@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	model "github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/orchestrator"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/service"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SETUP_ENV_VAR points to a file in which setup steps can export environment variables as KEY=value lines
+	SETUP_ENV_VAR = "SETUP_ENV"
+	// SETUP_SECRET_ENV_VAR points to a file in which setup steps can export environment variables which are masked in the logs
+	SETUP_SECRET_ENV_VAR = "SETUP_SECRET_ENV"
+	// SETUP_OUTPUT_DIR_VAR points to a directory which is shared by the setup steps, the checks, the finalizers and the teardown steps
+	SETUP_OUTPUT_DIR_VAR = "SETUP_OUTPUT_DIR"
+)
+
+var envKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// runSetup runs the setup steps in order and adds the exported variables to the environment of the plan.
+// Secret variables are also added to the secrets, so that they are masked in the logs.
+// If a step does not succeed, the remaining steps are skipped and the automated checks are not executed.
+func (e *exec) runSetup(o *orchestrator.Orchestrator, ep *model.ExecutionPlan, secrets map[string]string) ([]model.FinalizerRun, error) {
+	if len(ep.Setup) == 0 {
+		return nil, nil
+	}
+	outputDir := filepath.Join(e.setupDir, "outputs")
+	envDir := filepath.Join(e.setupDir, "env")
+	for _, dir := range []string{outputDir, envDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, errors.Wrapf(err, "failed to create setup directory '%s'", dir)
+		}
+	}
+	if ep.Env == nil {
+		ep.Env = make(map[string]string)
+	}
+	ep.Env[SETUP_OUTPUT_DIR_VAR] = outputDir
+
+	var runs []model.FinalizerRun
+	for _, setup := range ep.Setup {
+		e.logger.Infof("[ RUN SETUP %s ]", strings.ToUpper(setup.Name))
+		envFile := filepath.Join(envDir, setup.Name+".env")
+		secretEnvFile := filepath.Join(envDir, setup.Name+".secret.env")
+		for _, file := range []string{envFile, secretEnvFile} {
+			if err := os.WriteFile(file, nil, 0600); err != nil {
+				return nil, errors.Wrapf(err, "failed to create env file of setup '%s'", setup.Name)
+			}
+		}
+		env := make(map[string]string, len(ep.Env)+2)
+		for key, value := range ep.Env {
+			env[key] = value
+		}
+		env[SETUP_ENV_VAR] = envFile
+		env[SETUP_SECRET_ENV_VAR] = secretEnvFile
+
+		run := o.RunSetup(setup, env, secrets)
+		if run.Status == orchestrator.FinalizerSucceeded {
+			if err := exportSetupEnv(ep.Env, secrets, envFile, secretEnvFile); err != nil {
+				run.Status = orchestrator.FinalizerError
+				run.Reason = fmt.Sprintf("setup '%s' exported invalid environment variables: %s", setup.Name, err.Error())
+			}
+		}
+		runs = append(runs, run)
+		if run.Status != orchestrator.FinalizerSucceeded {
+			e.logger.UserErrorf("setup '%s' did not succeed, automated checks are not executed: %s", setup.Name, run.Reason)
+			failAutopilotChecks(ep, errors.Errorf("setup '%s' failed: %s", setup.Name, run.Reason))
+			break
+		}
+	}
+	return runs, nil
+}
+
+// startServices starts the background services and exposes them to the checks, finalizers and setup and teardown steps.
+// If a service does not become healthy, the automated checks are not executed.
+func (e *exec) startServices(services *service.Manager, ep *model.ExecutionPlan, secrets map[string]string) {
+	if len(ep.Services) == 0 {
+		return
+	}
+	e.logger.Info("[ START SERVICES ]")
+	exposed, err := services.Start(ep.Services, ep.Env, secrets)
+	if ep.Env == nil {
+		ep.Env = make(map[string]string)
+	}
+	for key, value := range exposed {
+		ep.Env[key] = value
+	}
+	if err != nil {
+		e.logger.UserErrorf("%s, automated checks are not executed", err.Error())
+		failAutopilotChecks(ep, err)
+	}
+}
+
+// stopServices stops the background services, their logs are part of the evidence
+func (e *exec) stopServices(services *service.Manager, ep *model.ExecutionPlan) []model.ServiceRun {
+	if len(ep.Services) == 0 {
+		return nil
+	}
+	e.logger.Info("[ STOP SERVICES ]")
+	return services.Stop()
+}
+
+// failAutopilotChecks prevents the execution of all automated checks, they end up in ERROR with the given reason
+func failAutopilotChecks(ep *model.ExecutionPlan, err error) {
+	for i := range ep.AutopilotChecks {
+		ep.AutopilotChecks[i].ValidationErrs = append(ep.AutopilotChecks[i].ValidationErrs, err)
+	}
+}
+
+// runTeardown runs all teardown steps, a failing step does not prevent the following ones from running.
+// The setup directory is removed afterwards.
+func (e *exec) runTeardown(o *orchestrator.Orchestrator, ep *model.ExecutionPlan, secrets map[string]string) []model.FinalizerRun {
+	defer os.RemoveAll(e.setupDir)
+	var runs []model.FinalizerRun
+	for _, teardown := range ep.Teardown {
+		e.logger.Infof("[ RUN TEARDOWN %s ]", strings.ToUpper(teardown.Name))
+		run := o.RunTeardown(teardown, ep.Env, secrets)
+		if run.Status != orchestrator.FinalizerSucceeded {
+			e.logger.Warnf("teardown '%s' did not succeed: %s", teardown.Name, run.Reason)
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// exportSetupEnv adds the variables of the env files written by a setup step to env, secret ones are also added to secrets
+func exportSetupEnv(env, secrets map[string]string, envFile, secretEnvFile string) error {
+	exported, err := readEnvFile(envFile)
+	if err != nil {
+		return err
+	}
+	exportedSecrets, err := readEnvFile(secretEnvFile)
+	if err != nil {
+		return err
+	}
+	for key, value := range exported {
+		env[key] = value
+	}
+	for key, value := range exportedSecrets {
+		env[key] = value
+		secrets[key] = value
+	}
+	return nil
+}
+
+// readEnvFile reads KEY=value lines, empty lines and lines starting with '#' are ignored
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open env file '%s'", path)
+	}
+	defer file.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found || !envKeyPattern.MatchString(key) {
+			return nil, errors.Errorf("invalid line %d in '%s', expected KEY=value", lineNumber, filepath.Base(path))
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read env file '%s'", path)
+	}
+	return env, nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvFile(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    map[string]string
+		wantErr string
+	}{
+		"should read variables": {
+			content: "FOO=bar\n\n# comment\nURL=https://example.com?a=b\nEMPTY=\n",
+			want:    map[string]string{"FOO": "bar", "URL": "https://example.com?a=b", "EMPTY": ""},
+		},
+		"should let later lines overwrite earlier ones": {
+			content: "FOO=bar\nFOO=baz\n",
+			want:    map[string]string{"FOO": "baz"},
+		},
+		"should fail on line without assignment": {
+			content: "FOO=bar\nbaz\n",
+			wantErr: "invalid line 2 in 'setup.env', expected KEY=value",
+		},
+		"should fail on invalid key": {
+			content: "MY-VAR=bar\n",
+			wantErr: "invalid line 1 in 'setup.env', expected KEY=value",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "setup.env")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := readEnvFile(path)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
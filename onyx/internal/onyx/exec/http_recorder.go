@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/httprecorder"
+	model "github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/schedule"
+	"github.com/pkg/errors"
+)
+
+// startHttpRecorder starts the proxy which records or replays the HTTP traffic of the automated checks.
+// Every check is configured to send its requests through the proxy, setup and teardown steps, services and finalizers are not.
+func (e *exec) startHttpRecorder(ep *model.ExecutionPlan, secrets map[string]string) (*httprecorder.Recorder, error) {
+	params := e.execParams.HttpRecorder
+	if params == nil {
+		return nil, nil
+	}
+	e.logger.Info("[ START HTTP RECORDER ]")
+	recorder, err := httprecorder.New(params.Mode, params.CassetteDir, secrets, e.logger)
+	if err != nil {
+		return nil, model.NewUserErr(err, "invalid http recorder")
+	}
+	if err := recorder.Start(); err != nil {
+		return nil, err
+	}
+	for i := range ep.AutopilotChecks {
+		check := &ep.AutopilotChecks[i]
+		env := recorder.Env(schedule.Key(*check))
+		// the maps are copied because checks of the same autopilot share them
+		check.Autopilot.Env = helper.MergeMaps(check.Autopilot.Env, env)
+		check.Autopilot.Evaluate.Env = helper.MergeMaps(check.Autopilot.Evaluate.Env, env)
+	}
+	return recorder, nil
+}
+
+// validateHttpRecorder checks that the http recorder is not used together with the egress proxy.
+// The checks send their traffic to the recorder, so their destinations could neither be recorded nor restricted.
+func (e *exec) validateHttpRecorder(ep *model.ExecutionPlan) error {
+	if e.execParams.HttpRecorder == nil {
+		return nil
+	}
+	if e.execParams.AuditNetwork {
+		return model.NewUserErr(errors.New("the network can not be audited while the http recorder is active"), "invalid http recorder")
+	}
+	for _, check := range ep.AutopilotChecks {
+		if check.Autopilot.Network != nil {
+			return model.NewUserErr(errors.Errorf("network.allow of autopilot '%s' can not be enforced while the http recorder is active", check.Autopilot.Name), "invalid http recorder")
+		}
+	}
+	return nil
+}
+
+// stopHttpRecorder stops the proxy, in record mode the cassettes are written
+func (e *exec) stopHttpRecorder(recorder *httprecorder.Recorder) error {
+	if recorder == nil {
+		return nil
+	}
+	e.logger.Info("[ STOP HTTP RECORDER ]")
+	if err := recorder.Stop(); err != nil {
+		return errors.Wrap(err, "error stopping http recorder")
+	}
+	return nil
+}
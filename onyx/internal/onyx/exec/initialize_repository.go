@@ -14,10 +14,13 @@ import (
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
 )
 
-func initializeRepository(repositories []configuration.Repository) ([]repository.Repository, error) {
+func initializeRepository(repositories []configuration.Repository, cache *repository.Cache) ([]repository.Repository, error) {
 	var parseErrs []error
 	var registryRepositories []repository.Repository
 	repositoryFactory := repository.NewRepositoryFactory()
+	if cache != nil {
+		repositoryFactory.WithCache(cache)
+	}
 	repositoryFactory.Register("curl", curl.NewRepository)
 	repositoryFactory.Register("azure-blob-storage", azblob.NewRepository)
 	for index := range repositories {
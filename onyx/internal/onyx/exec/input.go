@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"io"
+	"os"
+
+	"github.com/B-S-F/yaku/onyx/pkg/archive"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/tempdir"
+	"github.com/pkg/errors"
+)
+
+// STDIN_INPUT selects a tar stream on stdin as input
+const STDIN_INPUT = "-"
+
+var INPUT_DIRECTORY = tempdir.GetPath("input")
+
+// UnpackInput returns the folder to read the config files from.
+// Archives and tar streams on stdin are unpacked into a temporary folder first,
+// folders are returned as they are.
+func UnpackInput(input string, stdin io.Reader) (string, error) {
+	switch {
+	case input == STDIN_INPUT:
+		logger.Get().Info("unpacking input from stdin")
+		if err := os.MkdirAll(INPUT_DIRECTORY, 0755); err != nil {
+			return "", errors.Wrap(err, "error creating input directory")
+		}
+		if err := archive.ExtractTar(stdin, INPUT_DIRECTORY, archive.DefaultLimits()); err != nil {
+			return "", errors.Wrap(err, "error unpacking input from stdin")
+		}
+		return INPUT_DIRECTORY, nil
+	default:
+		return unpackArchive(input, INPUT_DIRECTORY)
+	}
+}
+
+// unpackArchive unpacks the input into dest if it is an archive file, other inputs are returned as they are
+func unpackArchive(input string, dest string) (string, error) {
+	if !archive.IsArchive(input) {
+		return input, nil
+	}
+	info, err := os.Stat(input)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading input '%s'", input)
+	}
+	if info.IsDir() {
+		return input, nil
+	}
+	logger.Get().Infof("unpacking input archive '%s'", input)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", errors.Wrap(err, "error creating input directory")
+	}
+	if err := archive.ExtractFile(input, dest, archive.DefaultLimits()); err != nil {
+		return "", errors.Wrapf(err, "error unpacking input archive '%s'", input)
+	}
+	return dest, nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackInput(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "qg-config.yaml", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("test"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	tarContent := buf.Bytes()
+
+	testCases := map[string]struct {
+		input   func(dir string) string
+		unpacks bool
+	}{
+		"should keep input folder": {
+			input: func(dir string) string { return dir },
+		},
+		"should unpack tar stream from stdin": {
+			input:   func(dir string) string { return STDIN_INPUT },
+			unpacks: true,
+		},
+		"should unpack archive file": {
+			input: func(dir string) string {
+				path := filepath.Join(dir, "bundle.tar")
+				require.NoError(t, os.WriteFile(path, tarContent, 0644))
+				return path
+			},
+			unpacks: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			INPUT_DIRECTORY = filepath.Join(dir, "input")
+			input := tc.input(dir)
+
+			folder, err := UnpackInput(input, bytes.NewReader(tarContent))
+
+			require.NoError(t, err)
+			if !tc.unpacks {
+				assert.Equal(t, input, folder)
+				return
+			}
+			assert.Equal(t, INPUT_DIRECTORY, folder)
+			content, err := os.ReadFile(filepath.Join(folder, "qg-config.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, "test", string(content))
+		})
+	}
+}
+
+func TestUnpackInputMissingArchive(t *testing.T) {
+	INPUT_DIRECTORY = filepath.Join(t.TempDir(), "input")
+
+	_, err := UnpackInput(filepath.Join(t.TempDir(), "missing.tar"), nil)
+
+	assert.ErrorContains(t, err, "error reading input")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
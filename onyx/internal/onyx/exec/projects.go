@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/parameter"
+	"github.com/B-S-F/yaku/onyx/pkg/reader"
+	"github.com/B-S-F/yaku/onyx/pkg/repository"
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/orchestrator"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/portfolio"
+	"github.com/pkg/errors"
+)
+
+const (
+	PORTFOLIO_RESULT_FILE = "qg-portfolio.yaml"
+	PORTFOLIO_REPORT_FILE = "qg-portfolio.md"
+)
+
+// Project is a single input folder of a multi-project run
+type Project struct {
+	// Name of the project, used as folder name of its outputs
+	Name string
+	// Input folder of the project
+	InputFolder string
+}
+
+type projectRun struct {
+	params  parameter.ExecutionParameter
+	exec    *exec
+	config  []byte
+	vars    map[string]string
+	secrets map[string]string
+	err     error
+}
+
+// ExecProjects runs all projects concurrently with the parameters of execParams, the notifications are sent for each project.
+// The projects share the installed apps and the limit of concurrently running autopilots.
+// The outputs of each project are written to a folder named after the project in the output folder,
+// next to a portfolio result and report combining all projects.
+// Archives as input folder of a project are unpacked into a temporary folder of the project.
+func ExecProjects(projects []Project, execParams parameter.ExecutionParameter, notifications []v2.Notification) error {
+	if err := validateProjects(projects); err != nil {
+		return err
+	}
+	logger.Get().Info("[ PREPARATION ]")
+
+	runs := make([]projectRun, len(projects))
+	allSecrets := make(map[string]string)
+	for i, project := range projects {
+		if project.InputFolder == STDIN_INPUT {
+			runs[i].err = errors.New("reading the input from stdin is not supported for projects")
+			continue
+		}
+		project.InputFolder, runs[i].err = unpackArchive(project.InputFolder, filepath.Join(INPUT_DIRECTORY, project.Name))
+		if runs[i].err != nil {
+			continue
+		}
+		runs[i].params = projectParameters(project, execParams)
+		runs[i].config, runs[i].vars, runs[i].secrets, runs[i].err = ReadFiles(runs[i].params, reader.New())
+		if runs[i].err != nil {
+			runs[i].err = errors.Wrap(runs[i].err, "error reading files")
+			continue
+		}
+		for name, value := range runs[i].secrets {
+			allSecrets[project.Name+"_"+name] = value
+		}
+	}
+
+	err := os.MkdirAll(execParams.OutputFolder, 0755)
+	if err != nil {
+		return errors.Wrap(err, "error creating output directory")
+	}
+	// messages logged outside of a project can contain the secrets of any project
+	logger.Set(logger.NewConsoleFileLogger(logger.Settings{
+		Secrets: allSecrets,
+		Files:   []string{filepath.Join(execParams.OutputFolder, "onyx.log")},
+	}))
+
+	cache := repository.NewCache()
+	limiter := orchestrator.NewLimiter(execParams.MaxConcurrency)
+	var wg sync.WaitGroup
+	for i, project := range projects {
+		if runs[i].err != nil {
+			logger.Get().UserErrorf("project '%s' failed: %s", project.Name, runs[i].err.Error())
+			continue
+		}
+		params := runs[i].params
+		e := newExec(params, notifications, filepath.Join(ROOT_WORK_DIRECTORY, project.Name), filepath.Join(APP_DIRECTORY, project.Name), filepath.Join(SETUP_DIRECTORY, project.Name))
+		e.logger = logger.NewConsoleFileLogger(logger.Settings{
+			Secrets: runs[i].secrets,
+			Files: []string{
+				filepath.Join(e.rootWorkDir, "onyx.log"),
+				filepath.Join(params.OutputFolder, "onyx.log"),
+			},
+		})
+		e.repositoryCache = cache
+		e.limiter = limiter
+		runs[i].exec = e
+
+		wg.Add(1)
+		go func(run *projectRun, name string) {
+			defer wg.Done()
+			logger.Get().Infof("[ PROJECT %s ]", name)
+			run.err = run.exec.run(run.config, run.vars, run.secrets)
+			if run.err != nil {
+				logger.Get().UserErrorf("project '%s' failed: %s", name, run.err.Error())
+			}
+		}(&runs[i], project.Name)
+	}
+	wg.Wait()
+
+	logger.Get().Info("[ PORTFOLIO ]")
+	var results []portfolio.Project
+	var runErrs []error
+	for i, project := range projects {
+		resultFile := filepath.Join(project.Name, RESULT_FILE)
+		results = append(results, portfolio.LoadProject(
+			project.Name,
+			project.InputFolder,
+			filepath.Join(execParams.OutputFolder, resultFile),
+			filepath.ToSlash(resultFile),
+			runs[i].err,
+		))
+		if runs[i].err != nil {
+			runErrs = append(runErrs, fmt.Errorf("project '%s': %w", project.Name, runs[i].err))
+		}
+	}
+	p := portfolio.New(results)
+	logger.Get().Infof("providing portfolio in '%s' and '%s'", PORTFOLIO_RESULT_FILE, PORTFOLIO_REPORT_FILE)
+	werr := helper.Join(
+		portfolio.WriteFile(p, filepath.Join(execParams.OutputFolder, PORTFOLIO_RESULT_FILE)),
+		portfolio.WriteReport(p, filepath.Join(execParams.OutputFolder, PORTFOLIO_REPORT_FILE)),
+	)
+	if werr != nil {
+		return errors.Wrap(werr, "error providing portfolio files")
+	}
+	if len(runErrs) > 0 {
+		return errors.Wrapf(helper.Join(runErrs...), "%d of %d projects failed", len(runErrs), len(projects))
+	}
+	return nil
+}
+
+// projectParameters derives the parameters of a single project from the parameters of the run
+func projectParameters(project Project, execParams parameter.ExecutionParameter) parameter.ExecutionParameter {
+	params := execParams
+	params.InputFolder = project.InputFolder
+	params.OutputFolder = filepath.Join(execParams.OutputFolder, project.Name)
+	if execParams.HistoryDir != "" {
+		// projects usually share the name of their quality gate and need their own history
+		params.HistoryDir = filepath.Join(execParams.HistoryDir, project.Name)
+	}
+	return params
+}
+
+func validateProjects(projects []Project) error {
+	if len(projects) == 0 {
+		return errors.New("no projects to run")
+	}
+	names := make(map[string]bool)
+	for _, project := range projects {
+		if project.Name == "" || project.Name == "." || project.Name == ".." || filepath.Base(project.Name) != project.Name {
+			return errors.Errorf("invalid project name '%s' for project '%s'", project.Name, project.InputFolder)
+		}
+		if names[project.Name] {
+			return errors.Errorf("project name '%s' is used more than once, please provide unique project names", project.Name)
+		}
+		names[project.Name] = true
+	}
+	return nil
+}
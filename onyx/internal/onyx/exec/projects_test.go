@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/parameter"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/portfolio"
+	resultv2 "github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestExecProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	outputDir := filepath.Join(tmpDir, "output")
+
+	writeProject := func(name string, content []byte) Project {
+		dir := filepath.Join(tmpDir, "input", name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		if content != nil {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), content, 0644))
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".vars"), nil, 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".secrets"), nil, 0644))
+		return Project{Name: name, InputFolder: dir}
+	}
+	qgConfig := simpleConfigV2()
+	a := qgConfig.Autopilots["checker"]
+	a.Evaluate.Run = "echo '{\"status\": \"GREEN\", \"reason\": \"Some reason\"}'"
+	qgConfig.Autopilots["checker"] = a
+	cfg, err := yaml.Marshal(qgConfig)
+	require.NoError(t, err)
+	projects := []Project{
+		writeProject("product-a", cfg),
+		writeProject("product-b", cfg),
+		writeProject("product-c", nil),
+	}
+
+	err = ExecProjects(projects, parameter.ExecutionParameter{
+		ConfigName:     "qg-config.yaml",
+		VarsName:       ".vars",
+		SecretsName:    ".secrets",
+		OutputFolder:   outputDir,
+		CheckTimeout:   10 * 60 * time.Second,
+		MaxConcurrency: 1,
+	}, nil)
+	require.ErrorContains(t, err, "1 of 3 projects failed")
+
+	for _, name := range []string{"product-a", "product-b"} {
+		assert.FileExists(t, filepath.Join(outputDir, name, RESULT_FILE))
+		assert.FileExists(t, filepath.Join(outputDir, name, EVIDENCE_FILE))
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, PORTFOLIO_RESULT_FILE))
+	require.NoError(t, err)
+	var p portfolio.Portfolio
+	require.NoError(t, yaml.Unmarshal(content, &p))
+	assert.Equal(t, "ERROR", p.OverallStatus)
+	assert.EqualValues(t, 3, p.Statistics.CountProjects)
+	assert.EqualValues(t, map[string]uint{"YELLOW": 2, "ERROR": 1}, p.Statistics.CountProjectsByStatus)
+	assert.EqualValues(t, 6, p.Statistics.CountChecks)
+	require.Len(t, p.Projects, 3)
+	assert.Equal(t, "product-a/qg-result.yaml", p.Projects[0].ResultFile)
+	assert.Equal(t, "test", p.Projects[0].QgName)
+	assert.Contains(t, p.Projects[2].Error, "error reading files")
+	assert.FileExists(t, filepath.Join(outputDir, PORTFOLIO_REPORT_FILE))
+}
+
+func TestExecProjectsArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	outputDir := filepath.Join(tmpDir, "output")
+
+	cfg, err := yaml.Marshal(simpleConfigV2())
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range map[string][]byte{"qg-config.yaml": cfg, ".vars": nil, ".secrets": nil} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	archivePath := filepath.Join(tmpDir, "product-a.tar")
+	require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0644))
+
+	err = ExecProjects([]Project{{Name: "product-a", InputFolder: archivePath}}, parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: outputDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(outputDir, "product-a", RESULT_FILE))
+	assert.FileExists(t, filepath.Join(tmpDir, "exec", "input", "product-a", "qg-config.yaml"))
+}
+
+func TestExecProjectsSetup(t *testing.T) {
+	tmpDir := t.TempDir()
+	OverrideDirectoriesForTest(filepath.Join(tmpDir, "exec"))
+	outputDir := filepath.Join(tmpDir, "output")
+
+	qgConfig := simpleConfigV2()
+	qgConfig.Autopilots["checker"] = config.Autopilot{
+		Evaluate: config.Evaluate{
+			Run: `sleep ${{ vars.DELAY }}
+echo "{\"status\": \"GREEN\", \"reason\": \"$(cat "$SETUP_OUTPUT_DIR/name.txt")\"}"`,
+		},
+	}
+	qgConfig.Setup = []config.Hook{{Name: "prepare", Run: `echo "${{ vars.NAME }}" > "$SETUP_OUTPUT_DIR/name.txt"`}}
+	cfg, err := yaml.Marshal(qgConfig)
+	require.NoError(t, err)
+	// the teardown of the fast project must not remove the setup outputs of the slow one
+	var projects []Project
+	for name, delay := range map[string]string{"fast": "0", "slow": "1"} {
+		dir := filepath.Join(tmpDir, "input", name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), cfg, 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".vars"), []byte(`{"NAME": "`+name+`", "DELAY": "`+delay+`"}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".secrets"), nil, 0644))
+		projects = append(projects, Project{Name: name, InputFolder: dir})
+	}
+
+	err = ExecProjects(projects, parameter.ExecutionParameter{
+		ConfigName:   "qg-config.yaml",
+		VarsName:     ".vars",
+		SecretsName:  ".secrets",
+		OutputFolder: outputDir,
+		CheckTimeout: 10 * 60 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	for _, project := range projects {
+		content, err := os.ReadFile(filepath.Join(outputDir, project.Name, RESULT_FILE))
+		require.NoError(t, err)
+		var result resultv2.Result
+		require.NoError(t, yaml.Unmarshal(content, &result))
+		evaluation := result.Chapters["1"].Requirements["1"].Checks["1"].Evaluation
+		assert.Equal(t, "GREEN", evaluation.Status, project.Name)
+		assert.Equal(t, project.Name, evaluation.Reason)
+	}
+}
+
+func TestValidateProjects(t *testing.T) {
+	testCases := map[string]struct {
+		projects []Project
+		wantErr  string
+	}{
+		"should accept unique names": {
+			projects: []Project{{Name: "a", InputFolder: "x/a"}, {Name: "b", InputFolder: "y/b"}},
+		},
+		"should reject missing projects": {
+			wantErr: "no projects to run",
+		},
+		"should reject duplicate names": {
+			projects: []Project{{Name: "a", InputFolder: "x/a"}, {Name: "a", InputFolder: "y/a"}},
+			wantErr:  "project name 'a' is used more than once",
+		},
+		"should reject names with path separators": {
+			projects: []Project{{Name: "../a", InputFolder: "x/a"}},
+			wantErr:  "invalid project name '../a'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateProjects(tc.projects)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -76,3 +76,8 @@ func runMigrate(currentVersion string, targetVersion string, content []byte) ([]
 	}
 	return content, nil
 }
+
+// Run migrates the config content from its current version to the target version
+func Run(currentVersion string, targetVersion string, content []byte) ([]byte, error) {
+	return runMigrate(currentVersion, targetVersion, content)
+}
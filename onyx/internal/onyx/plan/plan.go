@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/internal/onyx/common"
+	"github.com/B-S-F/yaku/onyx/internal/onyx/migrate"
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/reader"
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/overlay"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/schedule"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/transformer"
+	"github.com/pkg/errors"
+)
+
+type Parameters struct {
+	InputFolder string
+	ConfigName  string
+	// Name of the vars file in the input folder, the conditions of the checks are evaluated with its vars
+	VarsName string
+	// Directory of the run history to read the durations of previous runs from
+	HistoryDir string
+	// Result file of a previous run to read the durations from
+	PreviousResult string
+	// Predict the total run time
+	Estimate bool
+	// Number of checks run at the same time, unlimited if 0
+	Parallelism int
+	// Overlay files in the input folder which patch the config in the given order
+	Overlays []string
+}
+
+// Plan writes the order in which the automated checks of a config are started to out
+func Plan(params Parameters, out io.Writer) error {
+	configFile := filepath.Join(params.InputFolder, params.ConfigName)
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading config file %s", configFile)
+	}
+	if len(params.Overlays) > 0 {
+		files := make([]string, 0, len(params.Overlays))
+		for _, name := range params.Overlays {
+			files = append(files, filepath.Join(params.InputFolder, name))
+		}
+		var changes []overlay.Change
+		content, changes, err = overlay.ApplyFiles(content, files)
+		if err != nil {
+			return err
+		}
+		if err := writeChanges(changes, params.InputFolder, out); err != nil {
+			return err
+		}
+	}
+	vars, err := readVars(params)
+	if err != nil {
+		return err
+	}
+	ep, err := executionPlan(content, vars)
+	if err != nil {
+		return err
+	}
+	// checks whose conditions are not met are answered without running their autopilot
+	var notApplicable []model.AutopilotCheck
+	applicable := ep.AutopilotChecks[:0]
+	for _, check := range ep.AutopilotChecks {
+		if check.NotApplicable != nil {
+			notApplicable = append(notApplicable, check)
+			continue
+		}
+		applicable = append(applicable, check)
+	}
+	ep.AutopilotChecks = applicable
+
+	known, err := schedule.Known(params.HistoryDir, ep.Header.Name, params.PreviousResult)
+	if err != nil {
+		logger.Get().Warnf("failed to read durations of previous runs: %s", err.Error())
+	}
+	expected := schedule.Expected(ep.AutopilotChecks, known)
+	schedule.Order(ep.AutopilotChecks, expected)
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "#\tCHECK\tAUTOPILOT\tEXPECTED DURATION")
+	var durations []time.Duration
+	for i, check := range ep.AutopilotChecks {
+		duration, ok := expected[schedule.Key(check)]
+		expectedDuration := "unknown"
+		if ok {
+			expectedDuration = duration.String()
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", i+1, schedule.Key(check), check.Autopilot.Name, expectedDuration)
+		durations = append(durations, duration)
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.Wrap(err, "error writing plan")
+	}
+	if len(notApplicable) > 0 {
+		fmt.Fprintln(out, "\nNot started:")
+		writer = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, check := range notApplicable {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", schedule.Key(check), check.NotApplicable.Status, check.NotApplicable.Reason)
+		}
+		if err := writer.Flush(); err != nil {
+			return errors.Wrap(err, "error writing plan")
+		}
+	}
+
+	if params.Estimate {
+		parallelism := "unlimited parallelism"
+		if params.Parallelism > 0 {
+			parallelism = fmt.Sprintf("a parallelism of %d", params.Parallelism)
+		}
+		fmt.Fprintf(out, "\nEstimated run time with %s: %s\n", parallelism, schedule.Estimate(durations, params.Parallelism))
+		if unknown := len(ep.AutopilotChecks) - len(expected); unknown > 0 {
+			fmt.Fprintf(out, "%d of %d checks have no expected duration and are not included in the estimate\n", unknown, len(ep.AutopilotChecks))
+		}
+	}
+	return nil
+}
+
+// writeChanges writes which overlay changed which part of the config to out
+func writeChanges(changes []overlay.Change, inputFolder string, out io.Writer) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "OVERLAY\tOPERATION\tPATH")
+	for _, change := range changes {
+		name, err := filepath.Rel(inputFolder, change.Overlay)
+		if err != nil {
+			name = change.Overlay
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", name, change.Operation, change.Path)
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.Wrap(err, "error writing overlay changes")
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// readVars reads the vars file like exec does, no vars are used if it does not exist
+func readVars(params Parameters) (map[string]string, error) {
+	if params.VarsName == "" {
+		return nil, nil
+	}
+	varsFile := filepath.Join(params.InputFolder, params.VarsName)
+	if _, err := os.Stat(varsFile); os.IsNotExist(err) {
+		return nil, nil
+	}
+	vars, err := reader.New().ReadJsonMap(varsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading vars file %s", varsFile)
+	}
+	return vars, nil
+}
+
+// executionPlan creates the execution plan of a config, legacy configs are migrated to v2 first.
+// The applicability of the checks is evaluated with the vars, as in exec.
+func executionPlan(content []byte, vars map[string]string) (*model.ExecutionPlan, error) {
+	version, err := common.ReadConfigVersion(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading config version")
+	}
+	switch version {
+	case "v0", "v1":
+		content, err = migrate.Run(version, "v2", content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error migrating config from version '%s'", version)
+		}
+	case "v2":
+	default:
+		return nil, model.NewUserErr(errors.Errorf("version %s not supported", version), "invalid config file version")
+	}
+	cfg, err := v2.New(content)
+	if err != nil {
+		return nil, err
+	}
+	config, ok := cfg.(*v2.Config)
+	if !ok {
+		return nil, errors.Errorf("config is of unexpected type '%T'", cfg)
+	}
+	if err := v2.Validate(config); err != nil {
+		return nil, err
+	}
+	ep, err := config.CreateExecutionPlan()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create execution plan")
+	}
+	if err := transformer.NewApplicability(helper.MergeMaps(ep.DefaultVars, vars), time.Now()).Transform(ep); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
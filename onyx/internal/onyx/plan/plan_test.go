@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package plan
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `metadata:
+  version: v2
+header:
+  name: project
+  version: "1.0"
+autopilots:
+  checker:
+    run: echo done
+chapters:
+  "1":
+    title: chapter
+    requirements:
+      "1":
+        title: requirement
+        checks:
+          short:
+            title: short check
+            automation:
+              autopilot: checker
+          long:
+            title: long check
+            automation:
+              autopilot: checker
+          configured:
+            title: configured check
+            automation:
+              autopilot: checker
+              expectedDuration: 20m
+          new:
+            title: new check
+            automation:
+              autopilot: checker
+`
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(config), 0644))
+	historyDir := filepath.Join(dir, "history")
+	store := history.New(historyDir)
+	require.NoError(t, store.Append(history.Entry{Project: "project", Durations: map[string]float64{"1_1_short": 300, "1_1_long": 2400}}))
+
+	var out bytes.Buffer
+	err := Plan(Parameters{
+		InputFolder: dir,
+		ConfigName:  "qg-config.yaml",
+		HistoryDir:  historyDir,
+		Estimate:    true,
+		Parallelism: 2,
+	}, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, `#  CHECK           AUTOPILOT  EXPECTED DURATION
+1  1_1_long        checker    40m0s
+2  1_1_configured  checker    20m0s
+3  1_1_short       checker    5m0s
+4  1_1_new         checker    unknown
+
+Estimated run time with a parallelism of 2: 40m0s
+1 of 4 checks have no expected duration and are not included in the estimate
+`, out.String())
+}
+
+func TestPlanFailsForUnsupportedVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte("metadata:\n  version: v1337\n"), 0644))
+
+	err := Plan(Parameters{InputFolder: dir, ConfigName: "qg-config.yaml"}, &bytes.Buffer{})
+
+	assert.ErrorContains(t, err, "version v1337 not supported")
+}
+
+func TestPlanWithOverlays(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(config), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prod.yaml"), []byte(`chapters:
+  "1":
+    requirements:
+      "1":
+        checks:
+          short: null
+          long: null
+          new:
+            automation:
+              expectedDuration: 5m
+`), 0644))
+
+	var out bytes.Buffer
+	err := Plan(Parameters{
+		InputFolder: dir,
+		ConfigName:  "qg-config.yaml",
+		Overlays:    []string{"prod.yaml"},
+	}, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, `OVERLAY    OPERATION  PATH
+prod.yaml  remove     /chapters/1/requirements/1/checks/short
+prod.yaml  remove     /chapters/1/requirements/1/checks/long
+prod.yaml  add        /chapters/1/requirements/1/checks/new/automation/expectedDuration
+
+#  CHECK           AUTOPILOT  EXPECTED DURATION
+1  1_1_configured  checker    20m0s
+2  1_1_new         checker    5m0s
+`, out.String())
+}
+
+func TestPlanWithConditions(t *testing.T) {
+	conditions := config + `          ui:
+            title: ui check
+            when: vars.HAS_UI == 'true'
+            automation:
+              autopilot: checker
+              expectedDuration: 1h
+`
+	testCases := map[string]struct {
+		vars    string
+		want    string
+		wantErr string
+	}{
+		"should not start checks whose conditions are not met": {
+			vars: `{"HAS_UI": "false"}`,
+			want: `#  CHECK           AUTOPILOT  EXPECTED DURATION
+1  1_1_configured  checker    20m0s
+2  1_1_long        checker    unknown
+3  1_1_new         checker    unknown
+4  1_1_short       checker    unknown
+
+Not started:
+1_1_ui  NA  check 'ui' is not applicable, condition 'vars.HAS_UI == 'true'' is not met
+
+Estimated run time with unlimited parallelism: 20m0s
+3 of 4 checks have no expected duration and are not included in the estimate
+`,
+		},
+		"should start checks whose conditions are met": {
+			vars: `{"HAS_UI": "true"}`,
+			want: `#  CHECK           AUTOPILOT  EXPECTED DURATION
+1  1_1_ui          checker    1h0m0s
+2  1_1_configured  checker    20m0s
+3  1_1_long        checker    unknown
+4  1_1_new         checker    unknown
+5  1_1_short       checker    unknown
+
+Estimated run time with unlimited parallelism: 1h0m0s
+3 of 5 checks have no expected duration and are not included in the estimate
+`,
+		},
+		"should fail for conditions that can not be evaluated": {
+			vars:    `{"HAS_UI": "true"}`,
+			wantErr: "expression 'vars.HAS_UI' does not evaluate to a boolean",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			content := conditions
+			if tc.wantErr != "" {
+				// the condition is valid but does not evaluate to a boolean
+				content = strings.Replace(content, "vars.HAS_UI == 'true'", "vars.HAS_UI", 1)
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(content), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ".vars"), []byte(tc.vars), 0644))
+
+			var out bytes.Buffer
+			err := Plan(Parameters{
+				InputFolder: dir,
+				ConfigName:  "qg-config.yaml",
+				VarsName:    ".vars",
+				Estimate:    true,
+			}, &out)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, out.String())
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/timestamp"
+	"github.com/pkg/errors"
+)
+
+type Parameters struct {
+	// Evidence archive of the run
+	Archive string
+	// Result file which is compared with the result in the evidence, not compared if empty
+	Result string
+	// Timestamp token of the manifest, the token in the evidence is used if empty
+	Timestamp string
+	// PEM file with the certificates trusted to issue the certificate of the TSA, the system roots are used if empty
+	TsaCA string
+}
+
+// Verify checks that the evidence matches its manifest and that the manifest was timestamped by a trusted TSA.
+// Each verified part is reported to out, the first failing part ends the verification.
+func Verify(params Parameters, out io.Writer) error {
+	archive, err := evidence.Open(params.Archive)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	if !archive.HasManifest() {
+		return failed(out, errors.Errorf("evidence archive has no '%s'", evidence.MANIFEST_FILE))
+	}
+	entries, err := archive.Entries(evidence.Filter{})
+	if err != nil {
+		return err
+	}
+	if err := evidence.VerificationErr(entries, archive.Missing()); err != nil {
+		return failed(out, err)
+	}
+	verified, unlisted := 0, 0
+	for _, entry := range entries {
+		switch entry.Verification {
+		case evidence.Verified:
+			verified++
+		case evidence.Unlisted:
+			unlisted++
+		}
+	}
+	fmt.Fprintf(out, "evidence: %d files match '%s'\n", verified, evidence.MANIFEST_FILE)
+	if unlisted > 0 {
+		// unlisted files were added after the manifest was written and are not covered by the timestamp
+		fmt.Fprintf(out, "evidence: %d files are not listed in '%s' and not covered by the timestamp\n", unlisted, evidence.MANIFEST_FILE)
+	}
+
+	if params.Result != "" {
+		if err := verifyResult(archive, params.Result); err != nil {
+			return failed(out, err)
+		}
+		fmt.Fprintf(out, "result: '%s' matches '%s' in the evidence\n", params.Result, evidence.RESULT_FILE)
+	}
+
+	token, err := verifyTimestamp(archive, params)
+	if err != nil {
+		return failed(out, err)
+	}
+	fmt.Fprintf(out, "timestamp: '%s' %s\n", evidence.MANIFEST_FILE, token.String())
+	return nil
+}
+
+func verifyResult(archive *evidence.Archive, resultFile string) error {
+	result, err := os.ReadFile(resultFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read result file '%s'", resultFile)
+	}
+	archived, err := archive.ReadFile(evidence.RESULT_FILE)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(result, archived) {
+		return errors.Errorf("result file '%s' differs from '%s' in the evidence", resultFile, evidence.RESULT_FILE)
+	}
+	return nil
+}
+
+func verifyTimestamp(archive *evidence.Archive, params Parameters) (*timestamp.Token, error) {
+	var content []byte
+	var err error
+	if params.Timestamp != "" {
+		content, err = os.ReadFile(params.Timestamp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read timestamp '%s'", params.Timestamp)
+		}
+	} else {
+		content, err = archive.ReadFile(evidence.TIMESTAMP_FILE)
+		if err != nil {
+			return nil, errors.Errorf("evidence archive has no '%s', the run was not timestamped", evidence.TIMESTAMP_FILE)
+		}
+	}
+	var roots *x509.CertPool
+	if params.TsaCA != "" {
+		pem, err := os.ReadFile(params.TsaCA)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read TSA certificates '%s'", params.TsaCA)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("'%s' contains no PEM encoded certificates", params.TsaCA)
+		}
+	}
+	manifest, err := archive.ReadFile(evidence.MANIFEST_FILE)
+	if err != nil {
+		return nil, err
+	}
+	token, err := timestamp.Parse(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid timestamp")
+	}
+	digest := sha256.Sum256(manifest)
+	if err := token.Verify(digest[:], roots); err != nil {
+		return nil, errors.Wrapf(err, "timestamp does not prove '%s'", evidence.MANIFEST_FILE)
+	}
+	return token, nil
+}
+
+// failed reports the error to out, as only the reason of user errors is logged
+func failed(out io.Writer, err error) error {
+	fmt.Fprintln(out, err.Error())
+	return model.NewUserErr(err, "result verification failed")
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package result
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/evidence"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/timestamp"
+	"github.com/B-S-F/yaku/onyx/pkg/zip"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createEvidence creates a timestamped evidence archive and returns its path and the PEM file of the trusted CA
+func createEvidence(t *testing.T, modify func(dir string)) (string, string) {
+	authority, err := timestamp.NewLocalAuthority()
+	require.NoError(t, err)
+	server := httptest.NewServer(authority)
+	defer server.Close()
+
+	dir := t.TempDir()
+	workDir := filepath.Join(dir, "evidence")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "checker"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, evidence.RESULT_FILE), []byte("overallStatus: GREEN\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "checker", "data.json"), []byte(`{"ok": true}`), 0644))
+	require.NoError(t, evidence.WriteManifest(workDir))
+	_, err = evidence.WriteTimestamp(workDir, server.URL)
+	require.NoError(t, err)
+	if modify != nil {
+		modify(workDir)
+	}
+	archive := filepath.Join(dir, "evidence.zip")
+	zipper := zip.New(afero.NewOsFs())
+	require.NoError(t, zipper.Directory(workDir, archive))
+
+	ca := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: authority.CA().Raw}), 0644))
+	return archive, ca
+}
+
+func TestVerify(t *testing.T) {
+	archive, ca := createEvidence(t, nil)
+	result := filepath.Join(t.TempDir(), evidence.RESULT_FILE)
+	require.NoError(t, os.WriteFile(result, []byte("overallStatus: GREEN\n"), 0644))
+
+	var out bytes.Buffer
+	err := Verify(Parameters{Archive: archive, Result: result, TsaCA: ca}, &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "evidence: 2 files match 'evidence-manifest.json'\n")
+	assert.Contains(t, out.String(), "result: '"+result+"' matches 'qg-result.yaml' in the evidence\n")
+	assert.Regexp(t, `timestamp: 'evidence-manifest.json' issued at \S+ by 'CN=onyx local TSA' with serial number 1\n`, out.String())
+}
+
+func TestVerifyFails(t *testing.T) {
+	otherResult := filepath.Join(t.TempDir(), evidence.RESULT_FILE)
+	require.NoError(t, os.WriteFile(otherResult, []byte("overallStatus: RED\n"), 0644))
+	otherAuthority, err := timestamp.NewLocalAuthority()
+	require.NoError(t, err)
+	otherCA := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(otherCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherAuthority.CA().Raw}), 0644))
+
+	testCases := map[string]struct {
+		modify    func(dir string)
+		result    string
+		otherCA   bool
+		wantedOut string
+	}{
+		"modified evidence": {
+			modify: func(dir string) {
+				_ = os.WriteFile(filepath.Join(dir, "checker", "data.json"), []byte(`{"ok": false}`), 0644)
+			},
+			wantedOut: "checker/data.json",
+		},
+		"modified manifest": {
+			modify: func(dir string) {
+				manifest, _ := os.ReadFile(filepath.Join(dir, evidence.MANIFEST_FILE))
+				_ = os.WriteFile(filepath.Join(dir, evidence.MANIFEST_FILE), append(manifest, '\n'), 0644)
+			},
+			wantedOut: "timestamp does not prove 'evidence-manifest.json': timestamp was issued for another digest",
+		},
+		"missing timestamp": {
+			modify: func(dir string) {
+				_ = os.Remove(filepath.Join(dir, evidence.TIMESTAMP_FILE))
+			},
+			wantedOut: "evidence archive has no 'evidence-manifest.tsr', the run was not timestamped",
+		},
+		"other result": {
+			result:    otherResult,
+			wantedOut: "result file '" + otherResult + "' differs from 'qg-result.yaml' in the evidence",
+		},
+		"untrusted TSA": {
+			otherCA:   true,
+			wantedOut: "timestamp does not prove 'evidence-manifest.json': certificate of TSA 'CN=onyx local TSA' is not trusted",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			archive, ca := createEvidence(t, tc.modify)
+			if tc.otherCA {
+				ca = otherCA
+			}
+			var out bytes.Buffer
+			err := Verify(Parameters{Archive: archive, Result: tc.result, TsaCA: ca}, &out)
+			var userErr model.UserError
+			require.ErrorAs(t, err, &userErr)
+			assert.Equal(t, "result verification failed", userErr.Reason())
+			assert.Contains(t, out.String(), tc.wantedOut)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/runner"
+	"github.com/pkg/errors"
+)
+
+// placeholderPattern matches ${{ vars.X }}, ${{ secrets.X }} and ${{ env.X }} placeholders
+var placeholderPattern = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// validateScripts parses all scripts of the config and returns an error with the line and column for every shell syntax error.
+// Placeholders are replaced by a word of the same length before parsing, so that the positions stay the same.
+func validateScripts(config *v2.Config) []error {
+	var errs []error
+	check := func(script, location string) {
+		script = placeholderPattern.ReplaceAllStringFunc(script, func(placeholder string) string {
+			return strings.Repeat("x", len(placeholder))
+		})
+		if _, err := runner.ParseScript(script, ""); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid script of %s", location))
+		}
+	}
+
+	names := make([]string, 0, len(config.Autopilots))
+	for name := range config.Autopilots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		autopilot := config.Autopilots[name]
+		for i, step := range autopilot.Steps {
+			title := step.Title
+			if title == "" {
+				title = fmt.Sprint(i)
+			}
+			check(step.Run, fmt.Sprintf("step '%s' of autopilot '%s'", title, name))
+		}
+		check(autopilot.Evaluate.Run, fmt.Sprintf("evaluate of autopilot '%s'", name))
+	}
+	for _, service := range config.Services {
+		check(service.Run, fmt.Sprintf("service '%s'", service.Name))
+		check(service.HealthCheck, fmt.Sprintf("health check of service '%s'", service.Name))
+	}
+	for _, setup := range config.Setup {
+		check(setup.Run, fmt.Sprintf("setup '%s'", setup.Name))
+	}
+	for _, teardown := range config.Teardown {
+		check(teardown.Run, fmt.Sprintf("teardown '%s'", teardown.Name))
+	}
+	if config.Finalize != nil {
+		check(config.Finalize.Run, "finalize")
+	}
+	for _, finalizer := range config.Finalizers {
+		check(finalizer.Run, fmt.Sprintf("finalizer '%s'", finalizer.Name))
+	}
+	return errs
+}
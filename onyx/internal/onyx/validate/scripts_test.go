@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package validate
+
+import (
+	"testing"
+
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScripts(t *testing.T) {
+	tests := map[string]struct {
+		input *v2.Config
+		want  []string
+	}{
+		"valid-scripts": {
+			input: &v2.Config{
+				Autopilots: map[string]v2.Autopilot{
+					"checker": {
+						Steps:    []v2.Step{{Title: "fetch", Run: "if [ -n \"${{ vars.URL }}\" ]; then\n  curl ${{ vars.URL }}\nfi"}},
+						Evaluate: v2.Evaluate{Run: "for f in *.json; do\n  cat \"$f\"\ndone"},
+					},
+				},
+				Finalize: &v2.Finalize{Run: "html-finalizer"},
+			},
+		},
+		"syntax-errors-of-all-scripts": {
+			input: &v2.Config{
+				Autopilots: map[string]v2.Autopilot{
+					"b-checker": {
+						Steps:    []v2.Step{{Title: "fetch", Run: "echo ok"}, {Run: "echo \"${{ vars.URL }}"}},
+						Evaluate: v2.Evaluate{Run: "while true; do\n  sleep 1"},
+					},
+					"a-checker": {
+						Evaluate: v2.Evaluate{Run: "echo ok\necho $("},
+					},
+				},
+				Services:   []v2.Service{{Name: "db", Run: "postgres &&", HealthCheck: "pg_isready"}},
+				Setup:      []v2.Hook{{Name: "login", Run: "if true; then login"}},
+				Teardown:   []v2.Hook{{Name: "logout", Run: "logout |"}},
+				Finalize:   &v2.Finalize{Run: "case $x in"},
+				Finalizers: []v2.Finalizer{{Name: "report", Run: "report )"}},
+			},
+			want: []string{
+				"invalid script of evaluate of autopilot 'a-checker': 2:6: reached EOF without matching ( with )",
+				"invalid script of step '1' of autopilot 'b-checker': 1:6: reached EOF without closing quote \"",
+				"invalid script of evaluate of autopilot 'b-checker': 1:1: while statement must end with \"done\"",
+				"invalid script of service 'db': 1:10: && must be followed by a statement",
+				"invalid script of setup 'login': 1:1: if statement must end with \"fi\"",
+				"invalid script of teardown 'logout': 1:8: | must be followed by a statement",
+				"invalid script of finalize: 1:1: case statement must end with \"esac\"",
+				"invalid script of finalizer 'report': 1:8: a command can only contain words and redirects; encountered )",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, err := range validateScripts(tc.input) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
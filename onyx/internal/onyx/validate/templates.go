@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/transformer"
+	"github.com/pkg/errors"
+)
+
+// validateTemplates parses the templated config files of all steps and returns an error with the file and line for every template syntax error.
+// Config files which can't be read are skipped, a run only warns about them.
+func validateTemplates(config *v2.Config, inputFolder string) []error {
+	var errs []error
+	names := make([]string, 0, len(config.Autopilots))
+	for name := range config.Autopilots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for i, step := range config.Autopilots[name].Steps {
+			title := step.Title
+			if title == "" {
+				title = fmt.Sprint(i)
+			}
+			for _, file := range step.Config {
+				if !transformer.IsConfigTemplate(file.Name, step.ConfigTemplate) {
+					continue
+				}
+				var content string
+				if file.IsInline() {
+					content = *file.Content
+				} else {
+					data, err := os.ReadFile(filepath.Join(inputFolder, file.Name))
+					if err != nil {
+						continue
+					}
+					content = string(data)
+				}
+				if _, err := transformer.ParseConfigTemplate(file.Name, content); err != nil {
+					errs = append(errs, errors.Wrapf(err, "invalid config template of step '%s' of autopilot '%s'", title, name))
+				}
+			}
+		}
+	}
+	return errs
+}
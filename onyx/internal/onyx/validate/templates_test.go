@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	inline := func(content string) *string { return &content }
+	tests := map[string]struct {
+		input *v2.Config
+		want  []string
+	}{
+		"valid-templates": {
+			input: &v2.Config{
+				Autopilots: map[string]v2.Autopilot{
+					"checker": {
+						Steps: []v2.Step{{Title: "fetch", Config: []v2.ConfigFile{
+							{Name: "repos.yaml.tmpl"},
+							{Name: "filter.json", Content: inline("{{ .vars.FILTER | toJson }}")},
+						}, ConfigTemplate: true}},
+					},
+				},
+			},
+		},
+		"syntax-errors-of-templated-files": {
+			input: &v2.Config{
+				Autopilots: map[string]v2.Autopilot{
+					"b-checker": {
+						Steps: []v2.Step{
+							{Title: "fetch", Config: []v2.ConfigFile{{Name: "broken.yaml.tmpl"}}},
+							{Config: []v2.ConfigFile{{Name: "filter.json", Content: inline("{\n  \"status\": {{ unknown }}\n}")}}, ConfigTemplate: true},
+						},
+					},
+					"a-checker": {
+						Steps: []v2.Step{{Title: "fetch", Config: []v2.ConfigFile{
+							{Name: "plain.yaml", Content: inline("{{ not a template")},
+							{Name: "missing.yaml.tmpl"},
+							{Name: "list.yaml.tmpl", Content: inline("items:\n{{- range .vars.ITEMS }}\n  - {{ . }}\n")},
+						}}},
+					},
+				},
+			},
+			want: []string{
+				"invalid config template of step 'fetch' of autopilot 'a-checker': template: list.yaml.tmpl:4: unexpected EOF",
+				"invalid config template of step 'fetch' of autopilot 'b-checker': template: broken.yaml.tmpl:3: unexpected \"}\" in operand",
+				"invalid config template of step '1' of autopilot 'b-checker': template: filter.json:2: function \"unknown\" not defined",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "repos.yaml.tmpl"), []byte("{{ range split \",\" .vars.REPOS }}- {{ . }}\n{{ end }}"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml.tmpl"), []byte("repos:\n  - a\n  - {{ .vars.REPO }\n"), 0644))
+
+			var got []string
+			for _, err := range validateTemplates(tc.input, dir) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/B-S-F/yaku/onyx/internal/onyx/common"
+	"github.com/B-S-F/yaku/onyx/internal/onyx/migrate"
+	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/overlay"
+	"github.com/pkg/errors"
+)
+
+type Parameters struct {
+	InputFolder string
+	ConfigName  string
+	// Overlay files in the input folder which patch the config in the given order
+	Overlays []string
+}
+
+// Validate checks a config without running it and writes the problems it finds to out.
+// Besides the checks done before every run, the scripts are parsed to find shell syntax errors
+// and the templated config files to find template syntax errors.
+func Validate(params Parameters, out io.Writer) error {
+	configFile := filepath.Join(params.InputFolder, params.ConfigName)
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading config file %s", configFile)
+	}
+	if len(params.Overlays) > 0 {
+		files := make([]string, 0, len(params.Overlays))
+		for _, name := range params.Overlays {
+			files = append(files, filepath.Join(params.InputFolder, name))
+		}
+		content, _, err = overlay.ApplyFiles(content, files)
+		if err != nil {
+			return err
+		}
+	}
+	config, err := readConfig(content)
+	if err != nil {
+		return err
+	}
+	if err := v2.Validate(config); err != nil {
+		fmt.Fprintln(out, err.Error())
+		return err
+	}
+	if errs := validateScripts(config); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(out, err.Error())
+		}
+		return model.NewUserErr(errors.Errorf("found %d shell syntax errors", len(errs)), "config validation failed: scripts have shell syntax errors")
+	}
+	if errs := validateTemplates(config, params.InputFolder); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(out, err.Error())
+		}
+		return model.NewUserErr(errors.Errorf("found %d template syntax errors", len(errs)), "config validation failed: config templates have syntax errors")
+	}
+	fmt.Fprintf(out, "%s is valid\n", params.ConfigName)
+	return nil
+}
+
+// readConfig reads a config, legacy configs are migrated to v2 first
+func readConfig(content []byte) (*v2.Config, error) {
+	version, err := common.ReadConfigVersion(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading config version")
+	}
+	switch version {
+	case "v0", "v1":
+		content, err = migrate.Run(version, "v2", content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error migrating config from version '%s'", version)
+		}
+	case "v2":
+	default:
+		return nil, model.NewUserErr(errors.Errorf("version %s not supported", version), "invalid config file version")
+	}
+	cfg, err := v2.New(content)
+	if err != nil {
+		return nil, err
+	}
+	config, ok := cfg.(*v2.Config)
+	if !ok {
+		return nil, errors.Errorf("config is of unexpected type '%T'", cfg)
+	}
+	return config, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package validate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `metadata:
+  version: v2
+header:
+  name: project
+  version: "1.0"
+autopilots:
+  checker:
+    steps:
+      - title: fetch
+        run: |
+          curl -o data.json "${{ vars.URL }}"
+    evaluate:
+      run: |
+        if jq -e '.ok' data.json; then
+          echo '{"status": "GREEN"}'
+        fi
+chapters:
+  "1":
+    title: chapter
+    requirements:
+      "1":
+        title: requirement
+        checks:
+          "1":
+            title: check
+            automation:
+              autopilot: checker
+`
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(config), 0644))
+
+	var out bytes.Buffer
+	err := Validate(Parameters{InputFolder: dir, ConfigName: "qg-config.yaml"}, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "qg-config.yaml is valid\n", out.String())
+}
+
+func TestValidateReportsShellSyntaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(config), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte(`autopilots:
+  checker:
+    evaluate:
+      run: |
+        if jq -e '.ok' data.json; then
+          echo '{"status": "GREEN"}'
+`), 0644))
+
+	var out bytes.Buffer
+	err := Validate(Parameters{
+		InputFolder: dir,
+		ConfigName:  "qg-config.yaml",
+		Overlays:    []string{"broken.yaml"},
+	}, &out)
+
+	assert.EqualError(t, err, "config validation failed: scripts have shell syntax errors: found 1 shell syntax errors")
+	assert.Equal(t, "invalid script of evaluate of autopilot 'checker': 1:1: if statement must end with \"fi\"\n", out.String())
+}
+
+func TestValidateReportsTemplateSyntaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte(config), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "repos.yaml.tmpl"), []byte("repos:\n{{- range .vars.REPOS }}\n  - {{ . }\n{{- end }}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templated.yaml"), []byte(`autopilots:
+  checker:
+    steps:
+      - title: fetch
+        config:
+          - repos.yaml.tmpl
+        run: echo fetch
+`), 0644))
+
+	var out bytes.Buffer
+	err := Validate(Parameters{
+		InputFolder: dir,
+		ConfigName:  "qg-config.yaml",
+		Overlays:    []string{"templated.yaml"},
+	}, &out)
+
+	assert.EqualError(t, err, "config validation failed: config templates have syntax errors: found 1 template syntax errors")
+	assert.Equal(t, "invalid config template of step 'fetch' of autopilot 'checker': template: repos.yaml.tmpl:3: unexpected \"}\" in operand\n", out.String())
+}
+
+func TestValidateFailsForUnsupportedVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-config.yaml"), []byte("metadata:\n  version: v1337\n"), 0644))
+
+	err := Validate(Parameters{InputFolder: dir, ConfigName: "qg-config.yaml"}, &bytes.Buffer{})
+
+	assert.ErrorContains(t, err, "version v1337 not supported")
+}
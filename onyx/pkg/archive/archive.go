@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package archive unpacks config bundles provided as tar, tar.gz or zip archive.
+// Entries which would be written outside of the destination folder, links and
+// archives exceeding the configured limits are rejected.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultMaxSize  = 512 * 1024 * 1024
+	DefaultMaxFiles = 10000
+)
+
+// Limits restrict the unpacked content of an archive
+type Limits struct {
+	// Maximum number of bytes of all unpacked files
+	MaxSize int64
+	// Maximum number of files and folders
+	MaxFiles int
+}
+
+func DefaultLimits() Limits {
+	return Limits{MaxSize: DefaultMaxSize, MaxFiles: DefaultMaxFiles}
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// extensions of the supported archives, longer extensions first
+var extensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// IsArchive reports whether path names an archive supported by ExtractFile
+func IsArchive(path string) bool {
+	return TrimExtension(path) != path
+}
+
+// TrimExtension returns path without the extension of a supported archive
+func TrimExtension(path string) string {
+	lower := strings.ToLower(path)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext) {
+			return path[:len(path)-len(ext)]
+		}
+	}
+	return path
+}
+
+// ExtractFile unpacks the tar, tar.gz or zip archive at path into dest
+func ExtractFile(path, dest string, limits Limits) error {
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			return errors.Wrapf(err, "error opening archive '%s'", path)
+		}
+		defer reader.Close()
+		return extractZip(&reader.Reader, dest, limits)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "error opening archive '%s'", path)
+	}
+	defer file.Close()
+	return ExtractTar(file, dest, limits)
+}
+
+// ExtractTar unpacks a tar stream into dest, the stream may be gzip compressed
+func ExtractTar(r io.Reader, dest string, limits Limits) error {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return errors.Wrap(err, "error reading archive")
+	}
+	var stream io.Reader = buffered
+	if bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return errors.Wrap(err, "error decompressing archive")
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	e := newExtractor(dest, limits)
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "error reading archive")
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = e.dir(header.Name)
+		case tar.TypeReg:
+			err = e.file(header.Name, header.FileInfo().Mode(), tr)
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			err = fmt.Errorf("unsupported entry '%s': only files and folders are allowed", header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(r *zip.Reader, dest string, limits Limits) error {
+	e := newExtractor(dest, limits)
+	for _, f := range r.File {
+		var err error
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = e.dir(f.Name)
+		case mode.IsRegular():
+			err = e.zipFile(f)
+		default:
+			err = fmt.Errorf("unsupported entry '%s': only files and folders are allowed", f.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type extractor struct {
+	dest   string
+	limits Limits
+	size   int64
+	files  int
+}
+
+func newExtractor(dest string, limits Limits) *extractor {
+	return &extractor{dest: filepath.Clean(dest), limits: limits}
+}
+
+// target returns the path of an entry inside the destination, entries escaping it are rejected
+func (e *extractor) target(name string) (string, error) {
+	e.files++
+	if e.limits.MaxFiles > 0 && e.files > e.limits.MaxFiles {
+		return "", fmt.Errorf("archive contains more than %d entries", e.limits.MaxFiles)
+	}
+	normalized := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(normalized, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("invalid entry '%s': absolute paths are not allowed", name)
+	}
+	target := filepath.Join(e.dest, filepath.FromSlash(normalized))
+	if target != e.dest && !strings.HasPrefix(target, e.dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid entry '%s': path is outside of the destination", name)
+	}
+	return target, nil
+}
+
+func (e *extractor) dir(name string) error {
+	target, err := e.target(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0755)
+}
+
+func (e *extractor) zipFile(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "error reading entry '%s'", f.Name)
+	}
+	defer rc.Close()
+	return e.file(f.Name, f.Mode(), rc)
+}
+
+func (e *extractor) file(name string, mode os.FileMode, r io.Reader) error {
+	target, err := e.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.Wrapf(err, "error creating folder for entry '%s'", name)
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file for entry '%s'", name)
+	}
+	defer out.Close()
+
+	// the declared size of an entry can not be trusted, so the copied bytes are counted
+	var src io.Reader = r
+	if e.limits.MaxSize > 0 {
+		src = io.LimitReader(r, e.limits.MaxSize-e.size+1)
+	}
+	n, err := io.Copy(out, src)
+	e.size += n
+	if err != nil {
+		return errors.Wrapf(err, "error unpacking entry '%s'", name)
+	}
+	if e.limits.MaxSize > 0 && e.size > e.limits.MaxSize {
+		return fmt.Errorf("archive exceeds the maximum unpacked size of %d bytes", e.limits.MaxSize)
+	}
+	return nil
+}
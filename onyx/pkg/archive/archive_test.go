@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	name     string
+	content  string
+	typeflag byte
+	linkname string
+}
+
+func tarArchive(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		typeflag := e.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.content)), Typeflag: typeflag, Linkname: e.linkname})
+		require.NoError(t, err)
+		if typeflag == tar.TypeReg {
+			_, err = tw.Write([]byte(e.content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractTar(t *testing.T) {
+	valid := []entry{
+		{name: "configs/", typeflag: tar.TypeDir},
+		{name: "qg-config.yaml", content: "metadata:\n  version: v2\n"},
+		{name: "configs/extra.yaml", content: "extra"},
+	}
+	testCases := map[string]struct {
+		archive func(t *testing.T) []byte
+		limits  Limits
+		wantErr string
+	}{
+		"should extract tar": {
+			archive: func(t *testing.T) []byte { return tarArchive(t, valid) },
+			limits:  DefaultLimits(),
+		},
+		"should extract gzip compressed tar": {
+			archive: func(t *testing.T) []byte { return gzipped(t, tarArchive(t, valid)) },
+			limits:  DefaultLimits(),
+		},
+		"should reject path traversal": {
+			archive: func(t *testing.T) []byte {
+				return tarArchive(t, []entry{{name: "../evil.sh", content: "rm -rf /"}})
+			},
+			limits:  DefaultLimits(),
+			wantErr: "invalid entry '../evil.sh': path is outside of the destination",
+		},
+		"should reject absolute paths": {
+			archive: func(t *testing.T) []byte {
+				return tarArchive(t, []entry{{name: "/etc/passwd", content: "root"}})
+			},
+			limits:  DefaultLimits(),
+			wantErr: "invalid entry '/etc/passwd': absolute paths are not allowed",
+		},
+		"should reject symlinks": {
+			archive: func(t *testing.T) []byte {
+				return tarArchive(t, []entry{{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc"}})
+			},
+			limits:  DefaultLimits(),
+			wantErr: "unsupported entry 'link'",
+		},
+		"should reject archives exceeding the size limit": {
+			archive: func(t *testing.T) []byte { return tarArchive(t, valid) },
+			limits:  Limits{MaxSize: 10},
+			wantErr: "archive exceeds the maximum unpacked size of 10 bytes",
+		},
+		"should reject archives exceeding the file limit": {
+			archive: func(t *testing.T) []byte { return tarArchive(t, valid) },
+			limits:  Limits{MaxFiles: 2},
+			wantErr: "archive contains more than 2 entries",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dest := t.TempDir()
+
+			err := ExtractTar(bytes.NewReader(tc.archive(t)), dest, tc.limits)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.NoFileExists(t, filepath.Join(filepath.Dir(dest), "evil.sh"))
+				return
+			}
+			require.NoError(t, err)
+			content, err := os.ReadFile(filepath.Join(dest, "configs", "extra.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, "extra", string(content))
+			assert.FileExists(t, filepath.Join(dest, "qg-config.yaml"))
+		})
+	}
+}
+
+func TestExtractFile(t *testing.T) {
+	t.Run("should extract zip", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bundle.zip")
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.Create("qg-config.yaml")
+		require.NoError(t, err)
+		_, err = w.Write([]byte("metadata:\n  version: v2\n"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		dest := filepath.Join(dir, "out")
+		err = ExtractFile(path, dest, DefaultLimits())
+
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dest, "qg-config.yaml"))
+	})
+
+	t.Run("should reject path traversal in zip", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bundle.zip")
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		_, err := zw.Create("../../evil.sh")
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		err = ExtractFile(path, filepath.Join(dir, "out"), DefaultLimits())
+
+		assert.ErrorContains(t, err, "path is outside of the destination")
+	})
+
+	t.Run("should extract tar.gz", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bundle.tar.gz")
+		require.NoError(t, os.WriteFile(path, gzipped(t, tarArchive(t, []entry{{name: "qg-config.yaml", content: "x"}})), 0644))
+
+		err := ExtractFile(path, filepath.Join(dir, "out"), DefaultLimits())
+
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "out", "qg-config.yaml"))
+	})
+}
+
+func TestIsArchive(t *testing.T) {
+	assert.True(t, IsArchive("bundle.tar.gz"))
+	assert.True(t, IsArchive("bundle.TGZ"))
+	assert.True(t, IsArchive("bundle.zip"))
+	assert.True(t, IsArchive("bundle.tar"))
+	assert.False(t, IsArchive("configs"))
+	assert.False(t, IsArchive("-"))
+}
+
+func TestTrimExtension(t *testing.T) {
+	assert.Equal(t, "bundle", TrimExtension("bundle.tar.gz"))
+	assert.Equal(t, "path/bundle", TrimExtension("path/bundle.TGZ"))
+	assert.Equal(t, "bundle", TrimExtension("bundle.zip"))
+	assert.Equal(t, "configs", TrimExtension("configs"))
+}
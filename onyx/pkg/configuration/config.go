@@ -44,8 +44,9 @@ type Requirement struct {
 }
 
 type Manual struct {
-	Status string
-	Reason string
+	Status  string
+	Reason  string
+	Expires string
 }
 
 type Check struct {
@@ -6,9 +6,11 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/B-S-F/yaku/onyx/pkg/configuration"
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/replacer"
 	v2 "github.com/B-S-F/yaku/onyx/pkg/v2/config"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -226,7 +228,7 @@ func (c *Config) Migrate() ([]byte, error) {
 			Apps: autopilot.Apps,
 			Evaluate: v2.Evaluate{
 				Env:    autopilot.Env,
-				Config: autopilot.Config,
+				Config: v2.ConfigFilesFromNames(autopilot.Config),
 				Run:    autopilot.Run,
 			},
 		}
@@ -255,7 +257,7 @@ func (c *Config) Migrate() ([]byte, error) {
 			}
 			for checkKey, v1check := range v1req.Checks {
 				check := v2.Check{
-					Title: v1check.Title,
+					Title: migrateCheckTitle(v1check.Title, v1check.Automation.Env),
 				}
 				if v1check.Manual != (Manual{}) {
 					check.Manual = &v2.Manual{
@@ -271,12 +273,12 @@ func (c *Config) Migrate() ([]byte, error) {
 					if len(v1check.Automation.Config) > 0 {
 						if autopilot, ok := newConfig.Autopilots[v1check.Automation.Autopilot]; ok {
 							if autopilot.Evaluate.Config == nil {
-								autopilot.Evaluate.Config = make([]string, 0)
+								autopilot.Evaluate.Config = make([]v2.ConfigFile, 0)
 							}
 							// if config string is not already in the list, add it
 							for _, config := range v1check.Automation.Config {
-								if !contains(autopilot.Evaluate.Config, config) {
-									autopilot.Evaluate.Config = append(autopilot.Evaluate.Config, config)
+								if !containsConfigFile(autopilot.Evaluate.Config, config) {
+									autopilot.Evaluate.Config = append(autopilot.Evaluate.Config, v2.ConfigFile{Name: config})
 								} else {
 									logger.Warnf("config %s already exists in autopilot %s, ignoring it", config, v1check.Automation.Autopilot)
 								}
@@ -297,6 +299,24 @@ func (c *Config) Migrate() ([]byte, error) {
 	return yaml.Marshal(newConfig)
 }
 
+// migrateCheckTitle replaces the check environment variables in the title of a check.
+// They were replaced in titles of v1 configs, but v2 configs only replace global environment variables in titles.
+func migrateCheckTitle(title string, env map[string]string) string {
+	r := replacer.NewReplacerImpl([]replacer.Pattern{
+		replacer.NewPattern("env", replacer.PatternStart, replacer.PatternEnd),
+		replacer.NewPattern("envs", replacer.PatternStart, replacer.PatternEnd),
+	})
+	for _, match := range r.ListMatches(title) {
+		value, err := r.String(match, env)
+		if err != nil {
+			// not a check environment variable, it is replaced when the migrated config is run
+			continue
+		}
+		title = strings.ReplaceAll(title, match, value)
+	}
+	return title
+}
+
 func (c *Config) Parse() (*configuration.ExecutionPlan, error) {
 	// TODO: error handling for missing config values
 	logger := logger.Get()
@@ -420,9 +440,9 @@ func (c *Config) Parse() (*configuration.ExecutionPlan, error) {
 	return &plan, nil
 }
 
-func contains(s []string, e string) bool {
+func containsConfigFile(s []v2.ConfigFile, name string) bool {
 	for _, a := range s {
-		if a == e {
+		if a.Name == name {
 			return true
 		}
 	}
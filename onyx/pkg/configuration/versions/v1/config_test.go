@@ -765,3 +765,32 @@ repository repo2 referenced in app repo2::app3@latest not found`
 
 	assert.Equal(t, expectedValidationErr, parsed.Items[0].ValidationErr)
 }
+
+func TestMigrateCheckTitle(t *testing.T) {
+	testCases := map[string]struct {
+		title string
+		env   map[string]string
+		want  string
+	}{
+		"should replace check env variables": {
+			title: "Check pdf ${{ env.PDF_TO_CHECK }}",
+			env:   map[string]string{"PDF_TO_CHECK": "test.pdf"},
+			want:  "Check pdf test.pdf",
+		},
+		"should replace deprecated check env variables": {
+			title: "Check pdf ${{ envs.PDF_TO_CHECK }}",
+			env:   map[string]string{"PDF_TO_CHECK": "test.pdf"},
+			want:  "Check pdf test.pdf",
+		},
+		"should keep other variables": {
+			title: "Check ${{ env.GLOBAL }} of ${{ vars.PROJECT }}",
+			env:   map[string]string{"PDF_TO_CHECK": "test.pdf"},
+			want:  "Check ${{ env.GLOBAL }} of ${{ vars.PROJECT }}",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, migrateCheckTitle(tc.title, tc.env))
+		})
+	}
+}
@@ -96,18 +96,32 @@ func CreateSymlinks(src string, dst string, names []string) error {
 }
 
 type HashFields struct {
-	Chapter       string
-	Requirement   string
-	Check         string
+	Chapter     string
+	Requirement string
+	Check       string
+	// ID is the stable identifier of a result provided by the autopilot, criterion and justification are ignored if it is set
+	ID            string
 	Criterion     string
 	Justification string
 }
 
+// GenerateCheckResultIdHash identifies a result of a check by its ID, or by its criterion and justification if it has no ID
 func GenerateCheckResultIdHash(hashFields HashFields) string {
-	input := hashFields.Chapter + hashFields.Requirement + hashFields.Check + hashFields.Criterion + hashFields.Justification
-
-	re := regexp.MustCompile(`\s+`)
-	input = re.ReplaceAllString(input, "")
+	var input string
+	if hashFields.ID != "" {
+		// the fields are length-prefixed and keep their whitespaces, so that different IDs never get the same hash,
+		// the leading NUL separates them from the texts, which start with the chapter
+		var builder strings.Builder
+		builder.WriteByte(0)
+		for _, field := range []string{hashFields.Chapter, hashFields.Requirement, hashFields.Check, hashFields.ID} {
+			fmt.Fprintf(&builder, "%d:%s", len(field), field)
+		}
+		input = builder.String()
+	} else {
+		input = hashFields.Chapter + hashFields.Requirement + hashFields.Check + hashFields.Criterion + hashFields.Justification
+		re := regexp.MustCompile(`\s+`)
+		input = re.ReplaceAllString(input, "")
+	}
 
 	hash := sha256.New()
 	hash.Write([]byte(input))
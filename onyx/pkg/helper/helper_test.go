@@ -500,6 +500,30 @@ func TestGenerateCheckResultIdHash(t *testing.T) {
 				Justification: "The criterion was not met",
 			},
 			want: "9319a093d48e7488ef34cd74ccfe5e2f23a00b32eede2ba30d39676f2029a528",
+		},
+		{
+			name: "should only hash the id if it is set",
+			fields: HashFields{
+				Chapter:       "1",
+				Requirement:   "1",
+				Check:         "1",
+				ID:            "CVE-2024-1234",
+				Criterion:     "This is a test criterion",
+				Justification: "The criterion was not met",
+			},
+			want: "eb51cf73d5cddc9acd5fd1b60b73df32a7d3e646f0fa882907b2d09506586355",
+		},
+		{
+			name: "should ignore changes of criterion and justification if the id is set",
+			fields: HashFields{
+				Chapter:       "1",
+				Requirement:   "1",
+				Check:         "1",
+				ID:            "CVE-2024-1234",
+				Criterion:     "This is a reworded criterion",
+				Justification: "The criterion is still not met",
+			},
+			want: "eb51cf73d5cddc9acd5fd1b60b73df32a7d3e646f0fa882907b2d09506586355",
 		}}
 
 	for _, tt := range cases {
@@ -509,3 +533,31 @@ func TestGenerateCheckResultIdHash(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateCheckResultIdHashCollisions(t *testing.T) {
+	cases := []struct {
+		name string
+		a    HashFields
+		b    HashFields
+	}{{
+		name: "should not strip whitespaces of ids",
+		a:    HashFields{Chapter: "1", Requirement: "1", Check: "1", ID: "CVE 2024 1"},
+		b:    HashFields{Chapter: "1", Requirement: "1", Check: "1", ID: "CVE20241"},
+	},
+		{
+			name: "should separate the fields of ids",
+			a:    HashFields{Chapter: "1", Requirement: "1", Check: "11", ID: "1"},
+			b:    HashFields{Chapter: "1", Requirement: "11", Check: "1", ID: "1"},
+		},
+		{
+			name: "should separate ids from texts",
+			a:    HashFields{Chapter: "1", Requirement: "1", Check: "1", ID: "X"},
+			b:    HashFields{Chapter: "1", Requirement: "1", Check: "1", Criterion: "id:X"},
+		}}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, GenerateCheckResultIdHash(tt.a), GenerateCheckResultIdHash(tt.b))
+		})
+	}
+}
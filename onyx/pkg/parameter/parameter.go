@@ -18,6 +18,35 @@ type ExecutionParameter struct {
 	VarsName        string
 	SecretsName     string
 	CheckIdentifier string
+	// Directory of the run history, no history is kept if empty
+	HistoryDir string
+	// Result file of a previous run to read the durations of the checks from
+	PreviousResult string
+	// Format of the result file, defaults to v1 for v0 and v1 configs and to v2 otherwise
+	ResultVersion string
+	// How logs are provided in the result, one of 'full', 'reference' or 'none', defaults to 'full'
+	ResultLogs string
+	// Overlay files in the input folder which patch the config in the given order
+	Overlays []string
+	// Maximum number of autopilots and finalizers run at the same time, unlimited if not positive
+	MaxConcurrency int
+	// Shell which runs the scripts, either 'bash' or 'embedded', defaults to 'bash'
+	Shell string
+	// Routes the traffic of all automated checks through the egress proxy, otherwise only autopilots with network settings are routed
+	AuditNetwork bool
+	// Records or replays the HTTP traffic of the automated checks, nothing is recorded if nil
+	HttpRecorder *HttpRecorder
+	// URL of the RFC 3161 time-stamping authority which timestamps the evidence manifest, nothing is timestamped if empty
+	TsaURL string
+	// Flags set on the command line, recorded in the provenance of the run
+	Flags map[string]string
+}
+
+type HttpRecorder struct {
+	// Either 'record' or 'replay'
+	Mode string
+	// Directory with a cassette file per check
+	CassetteDir string
 }
 
 type CheckIdentifier struct {
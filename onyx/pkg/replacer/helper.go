@@ -13,6 +13,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+var PatternStart = `\${{`
+var PatternEnd = `}}`
+var PatternVariableType = []string{"vars", "secrets", "env"}
+var DeprecatedVariableType = []string{"var", "secret", "envs"}
+
 type Replacer interface {
 	Env(m *map[string]string, envs []map[string]string) error
 	Map(m *map[string]string, env map[string]string) error
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/B-S-F/yaku/onyx/pkg/repository/app"
+)
+
+// Cache shares installed apps between repositories of the same name, type and configuration,
+// e.g. between the projects of a multi-project run.
+// Each app is installed at most once, even if it is requested concurrently.
+type Cache struct {
+	mu   sync.Mutex
+	apps map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	once sync.Once
+	app  app.App
+	err  error
+}
+
+func NewCache() *Cache {
+	return &Cache{apps: make(map[string]*cacheEntry)}
+}
+
+func (c *Cache) entry(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.apps[key]
+	if !ok {
+		entry = &cacheEntry{}
+		c.apps[key] = entry
+	}
+	return entry
+}
+
+// cacheKey identifies a repository by its name, type and configuration,
+// the configuration can contain secrets and is therefore only stored as a hash.
+func cacheKey(name string, typeName string, config map[string]interface{}) (string, error) {
+	content, err := json.Marshal(map[string]interface{}{
+		"name":   name,
+		"type":   typeName,
+		"config": config,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating cache key for repository %s: %w", name, err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])[:16], nil
+}
+
+type cachedRepository struct {
+	Repository
+	key   string
+	cache *Cache
+}
+
+func (r *cachedRepository) InstallApp(reference *app.Reference) (app.App, error) {
+	entry := r.cache.entry(r.key + "/" + reference.String())
+	entry.once.Do(func() {
+		entry.app, entry.err = r.Repository.InstallApp(reference)
+	})
+	return entry.app, entry.err
+}
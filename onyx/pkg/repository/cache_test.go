@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingRepository struct {
+	name             string
+	installationPath string
+	installs         *atomic.Int32
+}
+
+func (c *countingRepository) InstallApp(reference *app.Reference) (app.App, error) {
+	c.installs.Add(1)
+	return app.NewBinaryApp(c.name, reference.Name, reference.Version, "", c.installationPath), nil
+}
+
+func (c *countingRepository) Name() string {
+	return c.name
+}
+
+func TestRepositoryFactoryWithCache(t *testing.T) {
+	installs := &atomic.Int32{}
+	newFactory := func(cache *Cache) *RepositoryFactory {
+		factory := NewRepositoryFactory().WithCache(cache)
+		factory.Register("counting", func(name string, installationPath string, config map[string]interface{}) (Repository, error) {
+			return &countingRepository{name: name, installationPath: installationPath, installs: installs}, nil
+		})
+		return factory
+	}
+	reference := &app.Reference{Repository: "repo", Name: "app", Version: "1.0.0"}
+
+	t.Run("should install app only once for repositories with the same config", func(t *testing.T) {
+		installs.Store(0)
+		cache := NewCache()
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				repo, err := newFactory(cache).New("repo", "counting", map[string]interface{}{"url": "https://example.com"})
+				require.NoError(t, err)
+				_, err = repo.InstallApp(reference)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.EqualValues(t, 1, installs.Load())
+	})
+
+	t.Run("should separate repositories with different configs", func(t *testing.T) {
+		installs.Store(0)
+		cache := NewCache()
+		repo1, err := newFactory(cache).New("repo", "counting", map[string]interface{}{"url": "https://example.com/1"})
+		require.NoError(t, err)
+		repo2, err := newFactory(cache).New("repo", "counting", map[string]interface{}{"url": "https://example.com/2"})
+		require.NoError(t, err)
+
+		app1, err := repo1.InstallApp(reference)
+		require.NoError(t, err)
+		app2, err := repo2.InstallApp(reference)
+		require.NoError(t, err)
+
+		assert.EqualValues(t, 2, installs.Load())
+		assert.NotEqual(t, app1.ExecutablePath(), app2.ExecutablePath())
+	})
+}
@@ -109,3 +109,18 @@ func (r *Registry) Get(reference *app.Reference) (app.App, error) {
 func (r *Registry) Stats() string {
 	return fmt.Sprintf("Number of apps: %d", len(r.repositoryApps))
 }
+
+// Apps returns all installed apps, each app only once
+func (r *Registry) Apps() []app.App {
+	seen := make(map[string]bool)
+	var apps []app.App
+	for _, installed := range r.repositoryApps {
+		key := installed.Reference().String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		apps = append(apps, installed)
+	}
+	return apps
+}
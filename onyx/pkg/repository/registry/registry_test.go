@@ -126,3 +126,19 @@ func TestStats(t *testing.T) {
 	assert.NotEmpty(t, stats)
 	assert.Equal(t, stats, "Number of apps: 2")
 }
+
+func TestApps(t *testing.T) {
+	installed := app.NewBinaryApp("MockRepo1", "MockApp", "1.0.0", "abcdef", "/path/to/executable")
+	other := app.NewBinaryApp("MockRepo1", "OtherApp", "2.0.0", "123456", "/path/to/other")
+	registry := Registry{
+		repositoryApps: map[string]app.App{
+			"MockRepo1::MockApp@1.0.0":  installed,
+			"MockApp@1.0.0":             installed,
+			"MockRepo1::OtherApp@2.0.0": other,
+		},
+	}
+
+	apps := registry.Apps()
+
+	assert.ElementsMatch(t, []app.App{installed, other}, apps)
+}
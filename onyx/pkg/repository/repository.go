@@ -6,6 +6,7 @@ package repository
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/B-S-F/yaku/onyx/pkg/repository/app"
 	"github.com/B-S-F/yaku/onyx/pkg/tempdir"
@@ -24,13 +25,33 @@ type Repository interface {
 
 type RepositoryFactory struct {
 	toRepository map[string]func(name string, installationPath string, config map[string]interface{}) (Repository, error)
+	cache        *Cache
 }
 
 func (r *RepositoryFactory) New(name string, typeName string, config map[string]interface{}) (Repository, error) {
-	if toRepository, ok := r.toRepository[typeName]; ok {
+	toRepository, ok := r.toRepository[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported repository type: %s", typeName)
+	}
+	if r.cache == nil {
 		return toRepository(name, REPOSITORY_DIRECTORY, config)
 	}
-	return nil, fmt.Errorf("unsupported repository type: %s", typeName)
+	// repositories with the same name but a different configuration must not share their installation path
+	key, err := cacheKey(name, typeName, config)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := toRepository(name, filepath.Join(REPOSITORY_DIRECTORY, key), config)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedRepository{Repository: repository, key: key, cache: r.cache}, nil
+}
+
+// WithCache lets all repositories created by the factory share the installed apps of the cache.
+func (r *RepositoryFactory) WithCache(cache *Cache) *RepositoryFactory {
+	r.cache = cache
+	return r
 }
 
 func (r *RepositoryFactory) Register(typeName string, conversion func(name string, installationPath string, config map[string]interface{}) (Repository, error)) {
@@ -27,8 +27,40 @@ type Config struct {
 	Repositories []Repository `yaml:"repositories" json:"repositories" jsonschema:"optional"`
 	// Autopilot configurations
 	Autopilots map[string]Autopilot `yaml:"autopilots" json:"autopilots" jsonschema:"optional"`
+	// Background services which are available during the whole run
+	// Example
+	// 	- name: mock-server
+	// 	  run: mock-server --port 8080
+	// 	  port: 8080
+	Services []Service `yaml:"services,omitempty" json:"services,omitempty" jsonschema:"optional"`
+	// Setup steps which are executed once before all checks, they can export environment variables for the checks
+	// Example
+	// 	- name: login
+	// 	  run: |
+	// 	    echo "API_TOKEN=$(fetch-token)" >> "$SETUP_SECRET_ENV"
+	Setup []Hook `yaml:"setup,omitempty" json:"setup,omitempty" jsonschema:"optional"`
+	// Teardown steps which are always executed once after all checks and finalizers, even if the run failed
+	// Example
+	// 	- name: logout
+	// 	  run: revoke-token "$API_TOKEN"
+	Teardown []Hook `yaml:"teardown,omitempty" json:"teardown,omitempty" jsonschema:"optional"`
 	// Finalize configuration
 	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize,omitempty" jsonschema:"optional"`
+	// Named finalizers which are executed in the given order after the finalize configuration
+	// Example
+	// 	- name: create-tickets
+	// 	  if: status == 'RED'
+	// 	  run: ticket-creator --result ${result_path}/qg-result.yaml
+	Finalizers []Finalizer `yaml:"finalizers,omitempty" json:"finalizers,omitempty" jsonschema:"optional"`
+	// Notifications which are sent after the run
+	// Example
+	// 	- name: team-channel
+	// 	  type: teams
+	// 	  url: ${{ secrets.TEAMS_WEBHOOK_URL }}
+	// 	  on:
+	// 	    status: [RED]
+	// 	    statusChanged: true
+	Notifications []Notification `yaml:"notifications,omitempty" json:"notifications,omitempty" jsonschema:"optional"`
 	// Chapters of the project
 	Chapters map[string]Chapter `yaml:"chapters" json:"chapters" jsonschema:"required"`
 }
@@ -83,6 +115,17 @@ type Autopilot struct {
 	// 	FOO: bar
 	// 	BAZ: qux
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Files with the content of a secret, available to all steps and the evaluation
+	// Example
+	// 	- name: kubeconfig
+	// 	  secret: KUBECONFIG
+	SecretFiles []SecretFile `yaml:"secretFiles,omitempty" json:"secretFiles,omitempty" jsonschema:"optional"`
+	// Restricts the hosts the steps of the autopilot can connect to
+	// Example
+	// 	allow:
+	// 	  - api.github.com
+	// 	  - "*.sharepoint.com"
+	Network *Network `yaml:"network,omitempty" json:"network,omitempty" jsonschema:"optional"`
 	// Steps to be executed by the autopilot
 	// Example
 	// 	- title: "step-1"
@@ -117,27 +160,65 @@ type Step struct {
 	// 	FOO: bar
 	// 	BAZ: qux
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
-	// Configuration files needed by the autopilot
+	// Configuration files needed by the autopilot, either the name of a file in the input folder or a file with name and content
 	// Example
 	// 	- my-config.yaml
-	// 	- my-other-config.yaml
-	Config []string `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
+	// 	- name: filter.json
+	// 	  content: '{"status": "GREEN"}'
+	Config []ConfigFile `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
+	// Render all configuration files of the step as Go templates.
+	// Configuration files ending with '.tmpl' are always rendered.
+	// Example true
+	ConfigTemplate bool `yaml:"configTemplate,omitempty" json:"configTemplate,omitempty" jsonschema:"optional"`
+	// Files with the content of a secret, only available to this step
+	// Example
+	// 	- name: service-account.json
+	// 	  secret: GCLOUD_SERVICE_ACCOUNT
+	SecretFiles []SecretFile `yaml:"secretFiles,omitempty" json:"secretFiles,omitempty" jsonschema:"optional"`
 	// Action to be executed
 	// Example "sharepoint-fetcher --config-file=..._1.yaml --output-dir=..."
 	Run string `yaml:"run" json:"run" jsonschema:"required"`
 }
 
+// A file with the content of a secret, e.g., a kubeconfig or an ssh key.
+// It is written outside of the work directory, its path is provided in the environment variable 'SECRET_FILE_<NAME>'
+// and it is removed after the step.
+type SecretFile struct {
+	// Name of the file, it also determines the name of the environment variable
+	// Example "kubeconfig"
+	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Name of the secret providing the content of the file
+	// Example "KUBECONFIG"
+	Secret string `yaml:"secret" json:"secret" jsonschema:"required"`
+	// File mode in octal notation, only the owner can have access, defaults to "0600"
+	// Example "0400"
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty" jsonschema:"optional"`
+}
+
+// Network settings of an autopilot.
+// The traffic of the steps is routed through the egress proxy of onyx, which records and restricts the destinations.
+type Network struct {
+	// Hosts the steps are allowed to connect to, all other destinations are rejected.
+	// An entry is a host name, an IP address or a wildcard matching all subdomains, optionally followed by a port.
+	// Example
+	// 	- api.github.com
+	// 	- "*.sharepoint.com"
+	// 	- "10.0.0.1:8443"
+	Allow []string `yaml:"allow" json:"allow" jsonschema:"required"`
+}
+
 type Evaluate struct {
 	// Environment variables to be set before executing the script
 	// Example
 	// 	RESULT_FILE_1: ...
 	// 	RESULT_FILE_2: ...
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
-	// Configuration files needed by the evaluator
+	// Configuration files needed by the evaluator, either the name of a file in the input folder or a file with name and content
 	// Example
 	// 	- my-config.yaml
-	// 	- my-other-config.yaml
-	Config []string `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
+	// 	- name: filter.json
+	// 	  content: '{"status": "GREEN"}'
+	Config []ConfigFile `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
 	// Action to be executed
 	// Example
 	// 	# do evaluation of SharePoint metadata here
@@ -163,6 +244,149 @@ type Finalize struct {
 	Run string `yaml:"run" json:"run" jsonschema:"required"`
 }
 
+type Finalizer struct {
+	// Unique name of the finalizer
+	// Example "create-tickets"
+	Name string `yaml:"name" json:"name" jsonschema:"required,pattern=^[a-zA-Z0-9_-]+$"`
+	// Condition on the overall, chapter or requirement status of the result.
+	// The finalizer is skipped if the condition evaluates to false.
+	// Example "status == 'RED'"
+	// Example "chapters.1.status in ['RED', 'YELLOW']"
+	If string `yaml:"if,omitempty" json:"if,omitempty" jsonschema:"optional"`
+	// A list of apps that the finalizer is able to use
+	// Example
+	// 	- my-app@1.0.0
+	Apps []string `yaml:"apps,omitempty" json:"apps,omitempty" jsonschema:"optional"`
+	// Environment variables to be set before executing the script
+	// Example
+	// 	FOO: bar
+	// 	BAZ: qux
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Configuration files needed by the finalizer
+	// Example
+	// 	- my-config.yaml
+	Config []string `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
+	// Timeout of the finalizer, defaults to the check timeout
+	// Example "10m"
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"optional"`
+	// Action to be executed
+	// Example
+	// 	# create tickets for all findings here
+	Run string `yaml:"run" json:"run" jsonschema:"required,minLength=1"`
+}
+
+type Hook struct {
+	// Unique name of the step
+	// Example "login"
+	Name string `yaml:"name" json:"name" jsonschema:"required,pattern=^[a-zA-Z0-9_-]+$"`
+	// A list of apps that the step is able to use
+	// Example
+	// 	- my-app@1.0.0
+	Apps []string `yaml:"apps,omitempty" json:"apps,omitempty" jsonschema:"optional"`
+	// Environment variables to be set before executing the script
+	// Example
+	// 	FOO: bar
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Configuration files needed by the step
+	// Example
+	// 	- my-config.yaml
+	Config []string `yaml:"config,omitempty" json:"config,omitempty" jsonschema:"optional"`
+	// Timeout of the step, defaults to the check timeout
+	// Example "5m"
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"optional"`
+	// Action to be executed
+	// Example
+	// 	echo "API_TOKEN=$(fetch-token)" >> "$SETUP_SECRET_ENV"
+	Run string `yaml:"run" json:"run" jsonschema:"required,minLength=1"`
+}
+
+type Service struct {
+	// Unique name of the service, checks can reach it with the SERVICE_<NAME>_HOST and SERVICE_<NAME>_PORT environment variables
+	// Example "mock-server"
+	Name string `yaml:"name" json:"name" jsonschema:"required,pattern=^[a-zA-Z0-9_-]+$"`
+	// Environment variables of the service
+	// Example
+	// 	LOG_LEVEL: debug
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Port the service listens on, the service is healthy as soon as the port accepts connections
+	// Example 8080
+	Port int `yaml:"port,omitempty" json:"port,omitempty" jsonschema:"optional,minimum=1,maximum=65535"`
+	// Command which checks the health of the service, the service is healthy as soon as it exits with exit code 0
+	// Example "curl -sf http://localhost:8080/health"
+	HealthCheck string `yaml:"healthCheck,omitempty" json:"healthCheck,omitempty" jsonschema:"optional"`
+	// Time to wait for the service to become healthy, defaults to 30s
+	// Example "1m"
+	StartupTimeout string `yaml:"startupTimeout,omitempty" json:"startupTimeout,omitempty" jsonschema:"optional"`
+	// Whether the service is restarted if it exits during the run, defaults to never
+	// Example "on-failure"
+	Restart string `yaml:"restart,omitempty" json:"restart,omitempty" jsonschema:"optional,enum=never,enum=on-failure,enum=always"`
+	// Maximum number of restarts, defaults to 3
+	// Example 5
+	MaxRestarts *int `yaml:"maxRestarts,omitempty" json:"maxRestarts,omitempty" jsonschema:"optional,minimum=0"`
+	// Command which starts the service, it must keep running in the foreground
+	// Example "mock-server --port 8080"
+	Run string `yaml:"run" json:"run" jsonschema:"required,minLength=1"`
+}
+
+type Notification struct {
+	// Unique name of the notification
+	// Example "team-channel"
+	Name string `yaml:"name" json:"name" jsonschema:"required,pattern=^[a-zA-Z0-9_-]+$"`
+	// Type of the notification
+	// Example "slack"
+	Type string `yaml:"type" json:"type" jsonschema:"required,enum=smtp,enum=webhook,enum=teams,enum=slack"`
+	// Conditions under which the notification is sent, it is sent after every run if omitted
+	On *NotificationTrigger `yaml:"on,omitempty" json:"on,omitempty" jsonschema:"optional"`
+	// URL of the webhook, required for the types webhook, teams and slack
+	// Example "${{ secrets.SLACK_WEBHOOK_URL }}"
+	URL string `yaml:"url,omitempty" json:"url,omitempty" jsonschema:"optional"`
+	// Additional HTTP headers sent to the webhook
+	// Example
+	// 	Authorization: Bearer ${{ secrets.WEBHOOK_TOKEN }}
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"optional"`
+	// SMTP settings, required for the type smtp
+	SMTP *SMTP `yaml:"smtp,omitempty" json:"smtp,omitempty" jsonschema:"optional"`
+}
+
+// Contains the conditions of a notification, the notification is sent if any of them is met
+type NotificationTrigger struct {
+	// Overall statuses which trigger the notification
+	// Example
+	// 	- RED
+	// 	- ERROR
+	Status []string `yaml:"status,omitempty" json:"status,omitempty" jsonschema:"optional"`
+	// Trigger the notification if the overall status differs from the previous run
+	StatusChanged bool `yaml:"statusChanged,omitempty" json:"statusChanged,omitempty" jsonschema:"optional"`
+	// Trigger the notification if a manual answer expires within the given period
+	// Example "14d"
+	ManualAnswersExpiring string `yaml:"manualAnswersExpiring,omitempty" json:"manualAnswersExpiring,omitempty" jsonschema:"optional"`
+}
+
+type SMTP struct {
+	// Host of the SMTP server
+	// Example "smtp.example.com"
+	Host string `yaml:"host" json:"host" jsonschema:"required"`
+	// Port of the SMTP server
+	// Example 587
+	Port int `yaml:"port,omitempty" json:"port,omitempty" jsonschema:"optional"`
+	// Username for the authentication, no authentication is done if omitted
+	// Example "${{ secrets.SMTP_USER }}"
+	Username string `yaml:"username,omitempty" json:"username,omitempty" jsonschema:"optional"`
+	// Password for the authentication
+	// Example "${{ secrets.SMTP_PASSWORD }}"
+	Password string `yaml:"password,omitempty" json:"password,omitempty" jsonschema:"optional"`
+	// Sender address
+	// Example "qg@example.com"
+	From string `yaml:"from" json:"from" jsonschema:"required"`
+	// Recipient addresses
+	// Example
+	// 	- team@example.com
+	To []string `yaml:"to" json:"to" jsonschema:"required,minItems=1"`
+	// Subject of the email, defaults to a summary of the run
+	// Example "Quality gate of ${{ vars.PROJECT }}"
+	Subject string `yaml:"subject,omitempty" json:"subject,omitempty" jsonschema:"optional"`
+}
+
 // Contains a configuration to answer a chapter
 type Chapter struct {
 	// Requirements to answer the chapter
@@ -181,6 +405,15 @@ type Chapter struct {
 	// Example Text: >
 	// 	This is my chapter
 	Text string `yaml:"text" json:"text" jsonschema:"optional"`
+	// Condition on the vars which must be true for the chapter to be applicable, otherwise it is NA
+	// Example "vars.HAS_UI == 'true'"
+	When string `yaml:"when,omitempty" json:"when,omitempty" jsonschema:"optional"`
+	// First day on which the chapter is applicable, it is SKIPPED before
+	// Example "2024-07-01"
+	ValidFrom string `yaml:"validFrom,omitempty" json:"validFrom,omitempty" jsonschema:"optional,format=date"`
+	// Last day on which the chapter is applicable, it is SKIPPED afterwards
+	// Example "2025-06-30"
+	ValidUntil string `yaml:"validUntil,omitempty" json:"validUntil,omitempty" jsonschema:"optional,format=date"`
 }
 
 // Contains a configuration to answer a requirement
@@ -203,6 +436,15 @@ type Requirement struct {
 	// 	      FOO: bar
 	// 	      BAZ: qux
 	Checks map[string]Check `yaml:"checks" json:"checks" jsonschema:"required"`
+	// Condition on the vars which must be true for the requirement to be applicable, otherwise it is NA
+	// Example "vars.HAS_UI == 'true'"
+	When string `yaml:"when,omitempty" json:"when,omitempty" jsonschema:"optional"`
+	// First day on which the requirement is applicable, it is SKIPPED before
+	// Example "2024-07-01"
+	ValidFrom string `yaml:"validFrom,omitempty" json:"validFrom,omitempty" jsonschema:"optional,format=date"`
+	// Last day on which the requirement is applicable, it is SKIPPED afterwards
+	// Example "2025-06-30"
+	ValidUntil string `yaml:"validUntil,omitempty" json:"validUntil,omitempty" jsonschema:"optional,format=date"`
 }
 
 // Contains configuration to execute a check either manually or automated
@@ -223,6 +465,21 @@ type Check struct {
 	//   env:
 	//     FOO: bar
 	Automation *Automation `yaml:"automation,omitempty" json:"automation,omitempty" jsonschema:"anyof_required=automation"`
+	// Labels to group checks across chapters, the result contains statistics per label
+	// Example ["security", "release-blocker"]
+	Labels []string `yaml:"labels,omitempty" json:"labels,omitempty" jsonschema:"optional"`
+	// Former IDs of the check in the same requirement, e.g. before it was renamed, to keep the history of its results
+	// Example ["1", "sbom-check"]
+	PreviousIds []string `yaml:"previousIds,omitempty" json:"previousIds,omitempty" jsonschema:"optional"`
+	// Condition on the vars which must be true for the check to be applicable, otherwise it is NA
+	// Example "vars.HAS_UI == 'true'"
+	When string `yaml:"when,omitempty" json:"when,omitempty" jsonschema:"optional"`
+	// First day on which the check is applicable, it is SKIPPED before
+	// Example "2024-07-01"
+	ValidFrom string `yaml:"validFrom,omitempty" json:"validFrom,omitempty" jsonschema:"optional,format=date"`
+	// Last day on which the check is applicable, it is SKIPPED afterwards
+	// Example "2025-06-30"
+	ValidUntil string `yaml:"validUntil,omitempty" json:"validUntil,omitempty" jsonschema:"optional,format=date"`
 }
 
 // Contains a hard coded answer for a check that cannot be or is still not automated
@@ -233,6 +490,9 @@ type Manual struct {
 	// Manual reason
 	// Example "This is my reason"
 	Reason string `yaml:"reason" json:"reason" jsonschema:"required"`
+	// Date after which the manual answer has to be reviewed again
+	// Example "2024-12-31"
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty" jsonschema:"optional,format=date"`
 }
 
 // Defined the automation of executing a check
@@ -245,6 +505,10 @@ type Automation struct {
 	// Reference to the autopilot defined in the autopilots section
 	// Example "my-autopilot"
 	Autopilot string `yaml:"autopilot" json:"autopilot" jsonschema:"required"`
+	// Expected duration of the check, used to start long running checks first.
+	// If not set, the duration is taken from previous runs.
+	// Example "40m"
+	ExpectedDuration string `yaml:"expectedDuration,omitempty" json:"expectedDuration,omitempty" jsonschema:"optional"`
 }
 
 func New(content []byte) (interface{}, error) {
@@ -351,6 +615,46 @@ func (c *Config) CreateExecutionPlan() (*model.ExecutionPlan, error) {
 		ep.Finalize = finalize
 	}
 
+	for _, service := range c.Services {
+		s, err := createService(service)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create service '%s'", service.Name)
+		}
+		ep.Services = append(ep.Services, s)
+	}
+
+	for _, hook := range c.Setup {
+		h, err := createHook(hook, repositoryNames)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create setup '%s'", hook.Name)
+		}
+		ep.Setup = append(ep.Setup, h)
+	}
+
+	for _, hook := range c.Teardown {
+		h, err := createHook(hook, repositoryNames)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create teardown '%s'", hook.Name)
+		}
+		ep.Teardown = append(ep.Teardown, h)
+	}
+
+	for _, finalizer := range c.Finalizers {
+		f, err := createFinalizer(finalizer, repositoryNames)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create finalizer '%s'", finalizer.Name)
+		}
+		ep.Finalizers = append(ep.Finalizers, f)
+	}
+
+	for _, notification := range c.Notifications {
+		n, err := createNotification(notification)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create notification '%s'", notification.Name)
+		}
+		ep.Notifications = append(ep.Notifications, n)
+	}
+
 	return &ep, nil
 }
 
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/invopop/jsonschema"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is a configuration file of a step or evaluator.
+// It is either the name of a file in the input folder or a file defined inline with its content.
+// Example "my-config.yaml"
+type ConfigFile struct {
+	Name string
+	// Content of an inline file, nil for files in the input folder
+	Content *string
+}
+
+// inlineConfigFile is the representation of an inline config file in the config
+type inlineConfigFile struct {
+	Name    string `yaml:"name" json:"name"`
+	Content string `yaml:"content" json:"content"`
+}
+
+// ConfigFilesFromNames returns references to the files with the given names
+func ConfigFilesFromNames(names []string) []ConfigFile {
+	if names == nil {
+		return nil
+	}
+	files := make([]ConfigFile, 0, len(names))
+	for _, name := range names {
+		files = append(files, ConfigFile{Name: name})
+	}
+	return files
+}
+
+// IsInline returns true if the file is defined inline
+func (c ConfigFile) IsInline() bool {
+	return c.Content != nil
+}
+
+func (c *ConfigFile) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = ConfigFile{}
+		return node.Decode(&c.Name)
+	}
+	var inline inlineConfigFile
+	if err := node.Decode(&inline); err != nil {
+		return errors.Wrap(err, "config file must either be a file name or have a name and content")
+	}
+	*c = ConfigFile{Name: inline.Name, Content: &inline.Content}
+	return nil
+}
+
+func (c ConfigFile) MarshalYAML() (interface{}, error) {
+	if !c.IsInline() {
+		return c.Name, nil
+	}
+	return inlineConfigFile{Name: c.Name, Content: *c.Content}, nil
+}
+
+func (c ConfigFile) MarshalJSON() ([]byte, error) {
+	if !c.IsInline() {
+		return json.Marshal(c.Name)
+	}
+	return json.Marshal(inlineConfigFile{Name: c.Name, Content: *c.Content})
+}
+
+// JSONSchema allows a file name as well as an inline file with name and content
+func (ConfigFile) JSONSchema() *jsonschema.Schema {
+	inline := jsonschema.NewProperties()
+	inline.Set("name", &jsonschema.Schema{Type: "string", Description: "Name of the file in the work directory", Examples: []interface{}{"filter.json"}})
+	inline.Set("content", &jsonschema.Schema{Type: "string", Description: "Content of the file", Examples: []interface{}{"{\"status\": \"GREEN\"}"}})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string", Description: "Name of a file in the input folder"},
+			{
+				Type:                 "object",
+				Properties:           inline,
+				Required:             []string{"name", "content"},
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigFile(t *testing.T) {
+	content := `config:
+  - cfg.yaml
+  - name: filter.json
+    content: '{"status": "${{ vars.STATUS }}"}'
+`
+	var step Step
+	require.NoError(t, yaml.Unmarshal([]byte(content), &step))
+
+	inlineContent := `{"status": "${{ vars.STATUS }}"}`
+	assert.Equal(t, []ConfigFile{{Name: "cfg.yaml"}, {Name: "filter.json", Content: &inlineContent}}, step.Config)
+
+	t.Run("should marshal both kinds", func(t *testing.T) {
+		marshalled, err := yaml.Marshal(step.Config)
+		require.NoError(t, err)
+		assert.Equal(t, "- cfg.yaml\n- name: filter.json\n  content: '{\"status\": \"${{ vars.STATUS }}\"}'\n", string(marshalled))
+	})
+
+	t.Run("should map inline files apart from files to load", func(t *testing.T) {
+		configs, inline := mapConfigFiles(step.Config)
+		assert.Equal(t, map[string]string{"cfg.yaml": ""}, configs)
+		assert.Equal(t, []model.InlineConfig{{Name: "filter.json", Content: inlineContent}}, inline)
+	})
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		err := yaml.Unmarshal([]byte("config:\n  - [a, b]\n"), &step)
+		assert.ErrorContains(t, err, "config file must either be a file name or have a name and content")
+	})
+}
@@ -7,6 +7,7 @@ package config
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/B-S-F/yaku/onyx/pkg/configuration"
 	model "github.com/B-S-F/yaku/onyx/pkg/v2/model"
@@ -52,6 +53,77 @@ func TestConfig_CreateExecutionPlan(t *testing.T) {
 				return ep
 			}},
 		},
+		"should-create-execPlan-with-finalizers": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				cfg.Finalizers = []Finalizer{
+					{Name: "create-tickets", If: "status == 'RED'", Apps: []string{"ticket-creator@1.0.0"}, Env: map[string]string{"PROJECT": "qg"}, Config: []string{"tickets.yaml"}, Timeout: "2m", Run: "ticket-creator"},
+					{Name: "publish", Run: "publish"},
+				}
+				return cfg
+			},
+			want: want{execPlan: func() *model.ExecutionPlan {
+				ep := simpleExecPlan()
+				ep.Finalizers = []model.Finalize{
+					{
+						Name:          "create-tickets",
+						If:            "status == 'RED'",
+						AppReferences: []*configuration.AppReference{{Name: "ticket-creator", Version: "1.0.0"}},
+						Env:           map[string]string{"PROJECT": "qg"},
+						Configs:       map[string]string{"tickets.yaml": ""},
+						Timeout:       2 * time.Minute,
+						Run:           "ticket-creator",
+					},
+					{Name: "publish", Run: "publish"},
+				}
+				return ep
+			}},
+		},
+		"should-fail-when-finalizer-references-unknown-repository": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				cfg.Finalizers = []Finalizer{{Name: "publish", Apps: []string{"unknown::publisher@1.0.0"}, Run: "publish"}}
+				return cfg
+			},
+			want: want{err: errors.New("failed to create finalizer 'publish': repository 'unknown' referenced in app 'unknown::publisher@1.0.0' was not found"), execPlan: func() *model.ExecutionPlan { return nil }},
+		},
+		"should-create-execPlan-with-services": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				maxRestarts := 5
+				cfg.Services = []Service{
+					{Name: "mock-server", Env: map[string]string{"MODE": "mock"}, Port: 8080, StartupTimeout: "1m", Restart: "on-failure", MaxRestarts: &maxRestarts, Run: "mock-server"},
+					{Name: "db", HealthCheck: "pg_isready", Run: "postgres"},
+				}
+				return cfg
+			},
+			want: want{execPlan: func() *model.ExecutionPlan {
+				ep := simpleExecPlan()
+				ep.Services = []model.Service{
+					{Name: "mock-server", Env: map[string]string{"MODE": "mock"}, Port: 8080, StartupTimeout: time.Minute, Restart: "on-failure", MaxRestarts: 5, Run: "mock-server"},
+					{Name: "db", HealthCheck: "pg_isready", StartupTimeout: 30 * time.Second, Restart: "never", MaxRestarts: 3, Run: "postgres"},
+				}
+				return ep
+			}},
+		},
+		"should-create-execPlan-with-notifications": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				cfg.Notifications = []Notification{
+					{Name: "team", Type: "slack", URL: "https://hooks.slack.com/services/T0", On: &NotificationTrigger{Status: []string{"RED"}, StatusChanged: true, ManualAnswersExpiring: "14d"}},
+					{Name: "mail", Type: "smtp", SMTP: &SMTP{Host: "localhost", Port: 25, From: "qg@example.com", To: []string{"team@example.com"}}},
+				}
+				return cfg
+			},
+			want: want{execPlan: func() *model.ExecutionPlan {
+				ep := simpleExecPlan()
+				ep.Notifications = []model.Notification{
+					{Name: "team", Type: "slack", URL: "https://hooks.slack.com/services/T0", On: &model.NotificationTrigger{Status: []string{"RED"}, StatusChanged: true, ManualAnswersExpiring: 14 * 24 * time.Hour}},
+					{Name: "mail", Type: "smtp", SMTP: &model.SMTP{Host: "localhost", Port: 25, From: "qg@example.com", To: []string{"team@example.com"}}},
+				}
+				return ep
+			}},
+		},
 
 		"should-create-execPlan-when-repositories-is-nil": {
 			input: func() *Config {
@@ -384,7 +456,7 @@ func simpleConfig() *Config {
 						Title:  "fetch1",
 						ID:     "fetch1",
 						Env:    map[string]string{"key": "value"},
-						Config: []string{"cfg.yaml"},
+						Config: []ConfigFile{{Name: "cfg.yaml"}},
 						Run:    "sharepoint-fetcher --config-file=..._1.yaml --output-dir=...",
 					},
 					{
@@ -400,7 +472,7 @@ func simpleConfig() *Config {
 				},
 				Evaluate: Evaluate{
 					Env:    map[string]string{"result_file1": "result.json"},
-					Config: []string{"cfg.yaml"},
+					Config: []ConfigFile{{Name: "cfg.yaml"}},
 					Run: `do-some-fancy-low-code-evaluation-here \
 --config-file ... \
 --output-dir ... \
@@ -585,7 +657,12 @@ func assertExecPlan(t *testing.T, want, got *model.ExecutionPlan) {
 	assert.Equal(t, want.DefaultVars, got.DefaultVars)
 	assert.Equal(t, want.Env, got.Env)
 	assert.Equal(t, want.Repositories, got.Repositories)
+	assert.Equal(t, want.Services, got.Services)
+	assert.Equal(t, want.Setup, got.Setup)
+	assert.Equal(t, want.Teardown, got.Teardown)
 	assert.Equal(t, want.Finalize, got.Finalize)
+	assert.Equal(t, want.Finalizers, got.Finalizers)
+	assert.Equal(t, want.Notifications, got.Notifications)
 
 	// assert autopilot checks manually because the order of the steps level of autopilotCheck does matter but the the order of steps inside a step level does not matter
 	assert.Equal(t, len(want.AutopilotChecks), len(got.AutopilotChecks))
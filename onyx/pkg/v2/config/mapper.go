@@ -6,9 +6,12 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/B-S-F/yaku/onyx/pkg/configuration"
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
@@ -36,9 +39,32 @@ func createItem(
 			Id:    checkIndex,
 			Title: check.Title,
 		},
+		Labels:      append([]string(nil), check.Labels...),
+		PreviousIds: append([]string(nil), check.PreviousIds...),
+		Conditions:  createConditions(chapIndex, chapter, reqIndex, requirement, checkIndex, check),
 	}
 }
 
+// createConditions collects the applicability conditions of the chapter, requirement and check in this order
+func createConditions(
+	chapIndex string, chapter Chapter,
+	reqIndex string, requirement Requirement,
+	checkIndex string, check Check,
+) []model.Condition {
+	candidates := []model.Condition{
+		{Level: fmt.Sprintf("chapter '%s'", chapIndex), When: chapter.When, ValidFrom: chapter.ValidFrom, ValidUntil: chapter.ValidUntil},
+		{Level: fmt.Sprintf("requirement '%s'", reqIndex), When: requirement.When, ValidFrom: requirement.ValidFrom, ValidUntil: requirement.ValidUntil},
+		{Level: fmt.Sprintf("check '%s'", checkIndex), When: check.When, ValidFrom: check.ValidFrom, ValidUntil: check.ValidUntil},
+	}
+	var conditions []model.Condition
+	for _, condition := range candidates {
+		if condition.When != "" || condition.ValidFrom != "" || condition.ValidUntil != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
 func createManualCheck(
 	chapIndex string, chapter Chapter,
 	reqIndex string, requirement Requirement,
@@ -47,8 +73,9 @@ func createManualCheck(
 	return model.ManualCheck{
 		Item: createItem(chapIndex, chapter, reqIndex, requirement, checkIndex, check),
 		Manual: configuration.Manual{
-			Status: check.Manual.Status,
-			Reason: check.Manual.Reason,
+			Status:  check.Manual.Status,
+			Reason:  check.Manual.Reason,
+			Expires: check.Manual.Expires,
 		},
 	}
 }
@@ -101,6 +128,11 @@ func createAutopilotCheck(
 		return model.AutopilotCheck{}, errors.Wrap(err, "failed to deep copy 'autopilot.Env'")
 	}
 
+	autopilotSecretFiles, err := mapSecretFiles(autopilot.SecretFiles)
+	if err != nil {
+		return model.AutopilotCheck{}, errors.Wrap(err, "failed to map 'autopilot.SecretFiles'")
+	}
+
 	// map Evaluate
 	evaluate := model.Evaluate{
 		Run: autopilot.Evaluate.Run,
@@ -112,10 +144,7 @@ func createAutopilotCheck(
 	}
 
 	if autopilot.Evaluate.Config != nil {
-		evaluate.Configs = make(map[string]string)
-		for _, cfg := range autopilot.Evaluate.Config {
-			evaluate.Configs[cfg] = ""
-		}
+		evaluate.Configs, evaluate.InlineConfigs = mapConfigFiles(autopilot.Evaluate.Config)
 	}
 
 	// map Steps
@@ -146,9 +175,15 @@ func createAutopilotCheck(
 
 	// map Autopilot
 	autopilotItem.Autopilot = model.Autopilot{
-		Name:     check.Automation.Autopilot,
-		Env:      autopilotEnv,
-		Evaluate: evaluate,
+		Name:        check.Automation.Autopilot,
+		Env:         autopilotEnv,
+		Evaluate:    evaluate,
+		SecretFiles: autopilotSecretFiles,
+	}
+	if autopilot.Network != nil {
+		autopilotItem.Autopilot.Network = &model.Network{
+			Allow: append([]string{}, autopilot.Network.Allow...),
+		}
 	}
 
 	if !hasCycle {
@@ -160,9 +195,164 @@ func createAutopilotCheck(
 		return model.AutopilotCheck{}, errors.Wrap(err, "failed to deep copy 'check.Automation.Env'")
 	}
 
+	if check.Automation.ExpectedDuration != "" {
+		autopilotItem.ExpectedDuration, err = time.ParseDuration(check.Automation.ExpectedDuration)
+		if err != nil {
+			return model.AutopilotCheck{}, errors.Wrapf(err, "invalid expected duration '%s'", check.Automation.ExpectedDuration)
+		}
+	}
+
 	return autopilotItem, nil
 }
 
+const (
+	defaultServiceStartupTimeout = 30 * time.Second
+	defaultServiceRestart        = "never"
+	defaultServiceMaxRestarts    = 3
+)
+
+func createService(service Service) (model.Service, error) {
+	s := model.Service{
+		Name:           service.Name,
+		Port:           service.Port,
+		HealthCheck:    service.HealthCheck,
+		StartupTimeout: defaultServiceStartupTimeout,
+		Restart:        defaultServiceRestart,
+		MaxRestarts:    defaultServiceMaxRestarts,
+		Run:            service.Run,
+	}
+
+	var err error
+	s.Env, err = deepCopyMap(service.Env)
+	if err != nil {
+		return model.Service{}, errors.Wrap(err, "failed to deep copy 'service.Env'")
+	}
+
+	if service.StartupTimeout != "" {
+		s.StartupTimeout, err = time.ParseDuration(service.StartupTimeout)
+		if err != nil {
+			return model.Service{}, errors.Wrapf(err, "invalid startup timeout '%s'", service.StartupTimeout)
+		}
+	}
+	if service.Restart != "" {
+		s.Restart = service.Restart
+	}
+	if service.MaxRestarts != nil {
+		s.MaxRestarts = *service.MaxRestarts
+	}
+	return s, nil
+}
+
+// createHook maps a setup or teardown step, it is executed like a finalizer without a condition
+func createHook(hook Hook, repositoryNames map[string]bool) (model.Finalize, error) {
+	return createFinalizer(Finalizer{
+		Name:    hook.Name,
+		Apps:    hook.Apps,
+		Env:     hook.Env,
+		Config:  hook.Config,
+		Timeout: hook.Timeout,
+		Run:     hook.Run,
+	}, repositoryNames)
+}
+
+func createFinalizer(finalizer Finalizer, repositoryNames map[string]bool) (model.Finalize, error) {
+	f := model.Finalize{
+		Name: finalizer.Name,
+		If:   finalizer.If,
+		Run:  finalizer.Run,
+	}
+
+	var err error
+	f.Env, err = deepCopyMap(finalizer.Env)
+	if err != nil {
+		return model.Finalize{}, errors.Wrap(err, "failed to deep copy 'finalizer.Env'")
+	}
+
+	if len(finalizer.Config) > 0 {
+		f.Configs = make(map[string]string)
+		for _, cfg := range finalizer.Config {
+			f.Configs[cfg] = ""
+		}
+	}
+
+	if finalizer.Timeout != "" {
+		f.Timeout, err = time.ParseDuration(finalizer.Timeout)
+		if err != nil {
+			return model.Finalize{}, errors.Wrapf(err, "invalid timeout '%s'", finalizer.Timeout)
+		}
+	}
+
+	for _, app := range finalizer.Apps {
+		appRef, err := configuration.NewAppReference(app)
+		if err != nil {
+			return model.Finalize{}, fmt.Errorf("app reference '%s' is invalid: %w", app, err)
+		}
+		if appRef.Repository != "" && !repositoryNames[appRef.Repository] {
+			return model.Finalize{}, errors.Errorf("repository '%s' referenced in app '%s' was not found", appRef.Repository, app)
+		}
+		f.AppReferences = append(f.AppReferences, appRef)
+	}
+
+	return f, nil
+}
+
+func createNotification(notification Notification) (model.Notification, error) {
+	n := model.Notification{
+		Name: notification.Name,
+		Type: notification.Type,
+		URL:  notification.URL,
+	}
+
+	var err error
+	n.Headers, err = deepCopyMap(notification.Headers)
+	if err != nil {
+		return model.Notification{}, errors.Wrap(err, "failed to deep copy 'notification.Headers'")
+	}
+
+	if notification.On != nil {
+		n.On = &model.NotificationTrigger{
+			Status:        append([]string(nil), notification.On.Status...),
+			StatusChanged: notification.On.StatusChanged,
+		}
+		if notification.On.ManualAnswersExpiring != "" {
+			n.On.ManualAnswersExpiring, err = parsePeriod(notification.On.ManualAnswersExpiring)
+			if err != nil {
+				return model.Notification{}, err
+			}
+		}
+	}
+
+	if notification.SMTP != nil {
+		n.SMTP = &model.SMTP{
+			Host:     notification.SMTP.Host,
+			Port:     notification.SMTP.Port,
+			Username: notification.SMTP.Username,
+			Password: notification.SMTP.Password,
+			From:     notification.SMTP.From,
+			To:       append([]string(nil), notification.SMTP.To...),
+			Subject:  notification.SMTP.Subject,
+		}
+	}
+
+	return n, nil
+}
+
+// parsePeriod parses a duration which additionally supports days, e.g. '14d'
+func parsePeriod(period string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(period, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, errors.Errorf("invalid period '%s'", period)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d < 0 {
+		return 0, errors.Errorf("invalid period '%s'", period)
+	}
+	return d, nil
+}
+
 func convertStepToDomain(step Step, stepIndex int, stepIDs map[string]bool) (model.Step, error) {
 	domainStep := model.Step{
 		Title: step.Title,
@@ -189,10 +379,13 @@ func convertStepToDomain(step Step, stepIndex int, stepIDs map[string]bool) (mod
 	}
 
 	if step.Config != nil {
-		domainStep.Configs = make(map[string]string)
-		for _, cfg := range step.Config {
-			domainStep.Configs[cfg] = ""
-		}
+		domainStep.Configs, domainStep.InlineConfigs = mapConfigFiles(step.Config)
+	}
+	domainStep.ConfigTemplate = step.ConfigTemplate
+
+	domainStep.SecretFiles, err = mapSecretFiles(step.SecretFiles)
+	if err != nil {
+		return model.Step{}, errors.Wrap(err, "failed to map 'step.SecretFiles'")
 	}
 
 	// validation and ensuring uniqueness of step.ID should happen at earlier stage
@@ -208,6 +401,48 @@ func convertStepToDomain(step Step, stepIndex int, stepIDs map[string]bool) (mod
 	return domainStep, nil
 }
 
+// mapConfigFiles returns the names of the files to load and the inline files with their content
+func mapConfigFiles(files []ConfigFile) (map[string]string, []model.InlineConfig) {
+	configs := make(map[string]string)
+	var inline []model.InlineConfig
+	for _, file := range files {
+		if !file.IsInline() {
+			configs[file.Name] = ""
+			continue
+		}
+		inline = append(inline, model.InlineConfig{Name: file.Name, Content: *file.Content})
+	}
+	return configs, inline
+}
+
+// mapSecretFiles parses the file modes of the secret files, files without mode are only accessible by the owner
+func mapSecretFiles(files []SecretFile) ([]model.SecretFile, error) {
+	if files == nil {
+		return nil, nil
+	}
+	secretFiles := make([]model.SecretFile, 0, len(files))
+	for _, file := range files {
+		mode, err := parseFileMode(file.Mode)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid mode of secret file '%s'", file.Name)
+		}
+		secretFiles = append(secretFiles, model.SecretFile{Name: file.Name, Secret: file.Secret, Mode: mode})
+	}
+	return secretFiles, nil
+}
+
+// parseFileMode parses an octal file mode, an empty mode defaults to 0600
+func parseFileMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0600, nil
+	}
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, errors.Errorf("'%s' is not an octal file mode", mode)
+	}
+	return os.FileMode(value), nil
+}
+
 func generateUniqueStepID(stepTitle string, stepIndex int, stepIDs map[string]bool) (string, error) {
 	var uniqueID string
 
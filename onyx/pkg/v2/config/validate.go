@@ -6,9 +6,13 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/egress"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/expression"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
 	"github.com/pkg/errors"
 )
@@ -28,6 +32,34 @@ func Validate(config interface{}) error {
 				}
 			}
 		}
+		// validate config files
+		for name, autopilot := range cfg.Autopilots {
+			for _, step := range autopilot.Steps {
+				if err := validateConfigFiles(step.Config); err != nil {
+					return model.NewUserErr(errors.Wrapf(err, "invalid config of step '%s' of autopilot '%s'", step.Title, name), "config validation failed")
+				}
+			}
+			if err := validateConfigFiles(autopilot.Evaluate.Config); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid config of evaluate of autopilot '%s'", name), "config validation failed")
+			}
+		}
+		// validate secret files
+		for name, autopilot := range cfg.Autopilots {
+			if err := validateSecretFiles(autopilot.SecretFiles, nil); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid secret files of autopilot '%s'", name), "config validation failed")
+			}
+			for _, step := range autopilot.Steps {
+				if err := validateSecretFiles(step.SecretFiles, autopilot.SecretFiles); err != nil {
+					return model.NewUserErr(errors.Wrapf(err, "invalid secret files of step '%s' of autopilot '%s'", step.Title, name), "config validation failed")
+				}
+			}
+		}
+		// validate network
+		for name, autopilot := range cfg.Autopilots {
+			if err := validateNetwork(autopilot.Network); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid network of autopilot '%s'", name), "config validation failed")
+			}
+		}
 		// validate depends
 		for _, autopilot := range cfg.Autopilots {
 			for _, step := range autopilot.Steps {
@@ -47,13 +79,54 @@ func Validate(config interface{}) error {
 			}
 			repositoryNames[repo.Name] = true
 		}
+		// validate services
+		if err := validateServices(cfg.Services); err != nil {
+			return err
+		}
+		// validate setup and teardown
+		if err := validateHooks("setup", cfg.Setup); err != nil {
+			return err
+		}
+		if err := validateHooks("teardown", cfg.Teardown); err != nil {
+			return err
+		}
+		// validate finalizers
+		if err := validateFinalizers(cfg.Finalizers); err != nil {
+			return err
+		}
+		// validate notifications
+		if err := validateNotifications(cfg.Notifications); err != nil {
+			return err
+		}
 		// validate checks
-		for _, chap := range cfg.Chapters {
-			for _, req := range chap.Requirements {
+		for chapID, chap := range cfg.Chapters {
+			if err := validateApplicability(chap.When, chap.ValidFrom, chap.ValidUntil); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid chapter '%s'", chapID), "config validation failed")
+			}
+			for reqID, req := range chap.Requirements {
+				if err := validateApplicability(req.When, req.ValidFrom, req.ValidUntil); err != nil {
+					return model.NewUserErr(errors.Wrapf(err, "invalid requirement '%s' of chapter '%s'", reqID, chapID), "config validation failed")
+				}
 				for checkID, check := range req.Checks {
+					if err := validateApplicability(check.When, check.ValidFrom, check.ValidUntil); err != nil {
+						return model.NewUserErr(errors.Wrapf(err, "invalid check '%s'", checkID), "config validation failed")
+					}
 					if check.isAutomation() && check.isManual() {
 						return model.NewUserErr(errors.Errorf("invalid check '%s': checks can't have both manual and automated checks", checkID), "config validation failed")
 					}
+					if check.isManual() && check.Manual.Expires != "" {
+						if _, err := time.Parse(time.DateOnly, check.Manual.Expires); err != nil {
+							return model.NewUserErr(errors.Errorf("invalid check '%s': expiry date '%s' must have the format YYYY-MM-DD", checkID, check.Manual.Expires), "config validation failed")
+						}
+					}
+					if check.isAutomation() && check.Automation.ExpectedDuration != "" {
+						if d, err := time.ParseDuration(check.Automation.ExpectedDuration); err != nil || d < 0 {
+							return model.NewUserErr(errors.Errorf("invalid check '%s': expected duration '%s' must be a positive duration like '40m'", checkID, check.Automation.ExpectedDuration), "config validation failed")
+						}
+					}
+					if err := validatePreviousIds(checkID, check.PreviousIds, req.Checks); err != nil {
+						return model.NewUserErr(errors.Wrapf(err, "invalid check '%s'", checkID), "config validation failed")
+					}
 				}
 			}
 		}
@@ -80,3 +153,246 @@ func validateID(id string, existingIDs map[string]bool) error {
 
 	return nil
 }
+
+// validateConfigFiles checks that config files have unique names and that inline files stay in the work directory.
+func validateConfigFiles(files []ConfigFile) error {
+	names := make(map[string]bool)
+	for _, file := range files {
+		if file.Name == "" {
+			return errors.New("config file name must not be empty")
+		}
+		if names[file.Name] {
+			return errors.Errorf("config file '%s' is defined more than once", file.Name)
+		}
+		names[file.Name] = true
+		if file.IsInline() && !filepath.IsLocal(file.Name) {
+			return errors.Errorf("inline config file '%s' must have a relative name within the work directory", file.Name)
+		}
+	}
+	return nil
+}
+
+// validateSecretFiles checks that secret files have plain and unique names, reference a secret and are only accessible by the owner.
+// Files of a step may replace inherited files of the autopilot with the same name, but must not shadow their environment variables.
+func validateSecretFiles(files []SecretFile, inherited []SecretFile) error {
+	envNames := make(map[string]string)
+	for _, file := range inherited {
+		envNames[model.SecretFileEnv(file.Name)] = file.Name
+	}
+	names := make(map[string]bool)
+	for _, file := range files {
+		if file.Name == "" || file.Name == "." || file.Name == ".." || filepath.Base(file.Name) != file.Name {
+			return errors.Errorf("secret file name '%s' must be a plain file name", file.Name)
+		}
+		if names[file.Name] {
+			return errors.Errorf("secret file '%s' is defined more than once", file.Name)
+		}
+		names[file.Name] = true
+		envName := model.SecretFileEnv(file.Name)
+		if other, ok := envNames[envName]; ok && other != file.Name {
+			return errors.Errorf("secret files '%s' and '%s' have the same environment variable '%s'", other, file.Name, envName)
+		}
+		envNames[envName] = file.Name
+		if file.Secret == "" {
+			return errors.Errorf("secret file '%s' must reference a secret", file.Name)
+		}
+		mode, err := parseFileMode(file.Mode)
+		if err != nil {
+			return errors.Wrapf(err, "invalid mode of secret file '%s'", file.Name)
+		}
+		if mode&^0700 != 0 {
+			return errors.Errorf("secret file '%s' must only be accessible by its owner, but has mode '%s'", file.Name, file.Mode)
+		}
+	}
+	return nil
+}
+
+// validateNetwork checks that all entries of the allowlist are valid hosts or wildcards.
+func validateNetwork(network *Network) error {
+	if network == nil {
+		return nil
+	}
+	for _, pattern := range network.Allow {
+		if err := egress.ValidatePattern(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePreviousIds checks that each previous ID refers to exactly one check of the requirement.
+// Otherwise, the history of the results of two checks would be mixed up.
+func validatePreviousIds(checkID string, previousIds []string, checks map[string]Check) error {
+	seen := make(map[string]bool)
+	for _, id := range previousIds {
+		if id == "" {
+			return errors.New("previous ids must not be empty")
+		}
+		if seen[id] {
+			return errors.Errorf("previous id '%s' is listed twice", id)
+		}
+		seen[id] = true
+		if _, exists := checks[id]; exists {
+			return errors.Errorf("previous id '%s' is the id of a current check", id)
+		}
+		for otherID, other := range checks {
+			// a conflict is reported by the check with the greater ID only, to get the same error for each validation
+			if otherID >= checkID {
+				continue
+			}
+			for _, otherPrevious := range other.PreviousIds {
+				if otherPrevious == id {
+					return errors.Errorf("previous id '%s' is also a previous id of check '%s'", id, otherID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateApplicability checks that the condition can be parsed and that the validity period consists of ordered dates.
+func validateApplicability(when, validFrom, validUntil string) error {
+	if when != "" {
+		if _, err := expression.Parse(when); err != nil {
+			return errors.Wrap(err, "invalid condition 'when'")
+		}
+	}
+	var from, until time.Time
+	var err error
+	if validFrom != "" {
+		if from, err = time.Parse(time.DateOnly, validFrom); err != nil {
+			return errors.Errorf("date '%s' of 'validFrom' must have the format YYYY-MM-DD", validFrom)
+		}
+	}
+	if validUntil != "" {
+		if until, err = time.Parse(time.DateOnly, validUntil); err != nil {
+			return errors.Errorf("date '%s' of 'validUntil' must have the format YYYY-MM-DD", validUntil)
+		}
+	}
+	if validFrom != "" && validUntil != "" && until.Before(from) {
+		return errors.Errorf("'validUntil' %s must not be before 'validFrom' %s", validUntil, validFrom)
+	}
+	return nil
+}
+
+// validateFinalizers checks that finalizer names are valid and unique and that their conditions and timeouts can be parsed.
+func validateFinalizers(finalizers []Finalizer) error {
+	isValidNamePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	names := make(map[string]bool)
+	for i, finalizer := range finalizers {
+		if !isValidNamePattern.MatchString(finalizer.Name) {
+			return model.NewUserErr(fmt.Errorf("invalid finalizer name '%s' at position %d: only alphanumeric characters, dashes, and underscores are allowed", finalizer.Name, i), "config validation failed")
+		}
+		if names[finalizer.Name] {
+			return model.NewUserErr(fmt.Errorf("invalid finalizer name '%s': name must be unique", finalizer.Name), "config validation failed")
+		}
+		names[finalizer.Name] = true
+		if finalizer.If != "" {
+			if _, err := expression.Parse(finalizer.If); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid condition of finalizer '%s'", finalizer.Name), "config validation failed")
+			}
+		}
+		if finalizer.Timeout != "" {
+			if _, err := time.ParseDuration(finalizer.Timeout); err != nil {
+				return model.NewUserErr(fmt.Errorf("invalid timeout '%s' of finalizer '%s'", finalizer.Timeout, finalizer.Name), "config validation failed")
+			}
+		}
+	}
+	return nil
+}
+
+// validateServices checks that service names are valid and unique and that their settings can be parsed.
+func validateServices(services []Service) error {
+	isValidNamePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	allowedRestarts := map[string]bool{"": true, "never": true, "on-failure": true, "always": true}
+	names := make(map[string]bool)
+	for i, service := range services {
+		if !isValidNamePattern.MatchString(service.Name) {
+			return model.NewUserErr(fmt.Errorf("invalid service name '%s' at position %d: only alphanumeric characters, dashes, and underscores are allowed", service.Name, i), "config validation failed")
+		}
+		// names are exposed as SERVICE_<NAME>_* environment variables, so they must differ after normalization
+		envName := model.ServiceEnvPrefix(service.Name)
+		if names[envName] {
+			return model.NewUserErr(fmt.Errorf("invalid service name '%s': name must be unique", service.Name), "config validation failed")
+		}
+		names[envName] = true
+		if service.Port < 0 || service.Port > 65535 {
+			return model.NewUserErr(fmt.Errorf("invalid port %d of service '%s'", service.Port, service.Name), "config validation failed")
+		}
+		if service.StartupTimeout != "" {
+			if _, err := time.ParseDuration(service.StartupTimeout); err != nil {
+				return model.NewUserErr(fmt.Errorf("invalid startup timeout '%s' of service '%s'", service.StartupTimeout, service.Name), "config validation failed")
+			}
+		}
+		if !allowedRestarts[service.Restart] {
+			return model.NewUserErr(fmt.Errorf("invalid restart policy '%s' of service '%s': must be one of never, on-failure, always", service.Restart, service.Name), "config validation failed")
+		}
+		if service.MaxRestarts != nil && *service.MaxRestarts < 0 {
+			return model.NewUserErr(fmt.Errorf("invalid maxRestarts %d of service '%s'", *service.MaxRestarts, service.Name), "config validation failed")
+		}
+	}
+	return nil
+}
+
+// validateHooks checks that the names of the setup or teardown steps are valid and unique and that their timeouts can be parsed.
+func validateHooks(kind string, hooks []Hook) error {
+	isValidNamePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	names := make(map[string]bool)
+	for i, hook := range hooks {
+		if !isValidNamePattern.MatchString(hook.Name) {
+			return model.NewUserErr(fmt.Errorf("invalid %s name '%s' at position %d: only alphanumeric characters, dashes, and underscores are allowed", kind, hook.Name, i), "config validation failed")
+		}
+		if names[hook.Name] {
+			return model.NewUserErr(fmt.Errorf("invalid %s name '%s': name must be unique", kind, hook.Name), "config validation failed")
+		}
+		names[hook.Name] = true
+		if hook.Timeout != "" {
+			if _, err := time.ParseDuration(hook.Timeout); err != nil {
+				return model.NewUserErr(fmt.Errorf("invalid timeout '%s' of %s '%s'", hook.Timeout, kind, hook.Name), "config validation failed")
+			}
+		}
+	}
+	return nil
+}
+
+// validateNotifications checks that notification names are unique and that every notification has the settings its type requires.
+func validateNotifications(notifications []Notification) error {
+	isValidNamePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	allowedStatus := map[string]bool{"GREEN": true, "YELLOW": true, "RED": true, "ERROR": true, "NA": true, "SKIPPED": true, "UNANSWERED": true}
+	names := make(map[string]bool)
+	for i, notification := range notifications {
+		if !isValidNamePattern.MatchString(notification.Name) {
+			return model.NewUserErr(fmt.Errorf("invalid notification name '%s' at position %d: only alphanumeric characters, dashes, and underscores are allowed", notification.Name, i), "config validation failed")
+		}
+		if names[notification.Name] {
+			return model.NewUserErr(fmt.Errorf("invalid notification name '%s': name must be unique", notification.Name), "config validation failed")
+		}
+		names[notification.Name] = true
+		switch notification.Type {
+		case "webhook", "teams", "slack":
+			if notification.URL == "" {
+				return model.NewUserErr(fmt.Errorf("notification '%s' of type '%s' requires a 'url'", notification.Name, notification.Type), "config validation failed")
+			}
+		case "smtp":
+			if notification.SMTP == nil || notification.SMTP.Host == "" || notification.SMTP.From == "" || len(notification.SMTP.To) == 0 {
+				return model.NewUserErr(fmt.Errorf("notification '%s' of type 'smtp' requires 'smtp.host', 'smtp.from' and 'smtp.to'", notification.Name), "config validation failed")
+			}
+		default:
+			return model.NewUserErr(fmt.Errorf("notification '%s' has unsupported type '%s'", notification.Name, notification.Type), "config validation failed")
+		}
+		if notification.On == nil {
+			continue
+		}
+		for _, status := range notification.On.Status {
+			if !allowedStatus[status] {
+				return model.NewUserErr(fmt.Errorf("notification '%s' has invalid status '%s' in 'on.status'", notification.Name, status), "config validation failed")
+			}
+		}
+		if notification.On.ManualAnswersExpiring != "" {
+			if _, err := parsePeriod(notification.On.ManualAnswersExpiring); err != nil {
+				return model.NewUserErr(errors.Wrapf(err, "invalid 'on.manualAnswersExpiring' of notification '%s'", notification.Name), "config validation failed")
+			}
+		}
+	}
+	return nil
+}
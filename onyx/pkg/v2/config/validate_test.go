@@ -51,6 +51,84 @@ func TestCustomValidationForV2(t *testing.T) {
 			},
 			want: nil,
 		},
+		"valid-inline-config": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {Steps: []Step{{Config: []ConfigFile{{Name: "cfg.yaml"}, {Name: "filters/filter.json", Content: new(string)}}}}},
+				},
+			},
+			want: nil,
+		},
+		"valid-secret-files": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {
+						SecretFiles: []SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG"}},
+						Steps:       []Step{{SecretFiles: []SecretFile{{Name: "kubeconfig", Secret: "OTHER_KUBECONFIG", Mode: "0400"}}}},
+					},
+				},
+			},
+			want: nil,
+		},
+		"invalid-secret-file-path": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {SecretFiles: []SecretFile{{Name: "../kubeconfig", Secret: "KUBECONFIG"}}},
+				},
+			},
+			want: errors.New("config validation failed: invalid secret files of autopilot 'autopilots': secret file name '../kubeconfig' must be a plain file name"),
+		},
+		"invalid-secret-file-env": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {
+						SecretFiles: []SecretFile{{Name: "key.json", Secret: "KEY"}},
+						Steps:       []Step{{Title: "fetch", SecretFiles: []SecretFile{{Name: "key-json", Secret: "KEY"}}}},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid secret files of step 'fetch' of autopilot 'autopilots': secret files 'key.json' and 'key-json' have the same environment variable 'SECRET_FILE_KEY_JSON'"),
+		},
+		"invalid-secret-file-mode": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {SecretFiles: []SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG", Mode: "0644"}}},
+				},
+			},
+			want: errors.New("config validation failed: invalid secret files of autopilot 'autopilots': secret file 'kubeconfig' must only be accessible by its owner, but has mode '0644'"),
+		},
+		"valid-network": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {Network: &Network{Allow: []string{"api.github.com", "*.sharepoint.com", "10.0.0.1:8443"}}},
+				},
+			},
+			want: nil,
+		},
+		"invalid-network-host": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {Network: &Network{Allow: []string{"api.github.com/repos"}}},
+				},
+			},
+			want: errors.New("config validation failed: invalid network of autopilot 'autopilots': invalid host 'api.github.com/repos', expected a host name, an IP address or a wildcard like '*.example.com'"),
+		},
+		"invalid-duplicate-config": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {Steps: []Step{{Title: "fetch", Config: []ConfigFile{{Name: "cfg.yaml"}, {Name: "cfg.yaml", Content: new(string)}}}}},
+				},
+			},
+			want: errors.New("config validation failed: invalid config of step 'fetch' of autopilot 'autopilots': config file 'cfg.yaml' is defined more than once"),
+		},
+		"invalid-inline-config-outside-of-work-dir": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"autopilots": {Evaluate: Evaluate{Config: []ConfigFile{{Name: "../filter.json", Content: new(string)}}}},
+				},
+			},
+			want: errors.New("config validation failed: invalid config of evaluate of autopilot 'autopilots': inline config file '../filter.json' must have a relative name within the work directory"),
+		},
 		"invalid-depends": {
 			input: &Config{
 				Autopilots: map[string]Autopilot{
@@ -86,6 +164,201 @@ func TestCustomValidationForV2(t *testing.T) {
 			},
 			want: errors.New("config validation failed: invalid check 'check1': checks can't have both manual and automated checks"),
 		},
+		"invalid-finalizer-name": {
+			input: &Config{
+				Finalizers: []Finalizer{{Name: "my finalizer", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid finalizer name 'my finalizer' at position 0: only alphanumeric characters, dashes, and underscores are allowed"),
+		},
+		"duplicate-finalizer-name": {
+			input: &Config{
+				Finalizers: []Finalizer{{Name: "notify", Run: "echo"}, {Name: "notify", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid finalizer name 'notify': name must be unique"),
+		},
+		"invalid-finalizer-condition": {
+			input: &Config{
+				Finalizers: []Finalizer{{Name: "notify", If: "status = 'RED'", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid condition of finalizer 'notify': invalid expression 'status = 'RED'': unexpected character '=' at position 7"),
+		},
+		"invalid-finalizer-timeout": {
+			input: &Config{
+				Finalizers: []Finalizer{{Name: "notify", Timeout: "ten minutes", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid timeout 'ten minutes' of finalizer 'notify'"),
+		},
+		"valid-finalizers": {
+			input: &Config{
+				Finalizers: []Finalizer{
+					{Name: "create-tickets", If: "status == 'RED'", Timeout: "5m", Run: "echo"},
+					{Name: "publish", If: "chapters.1.status in ['GREEN', 'YELLOW']", Run: "echo"},
+				},
+			},
+			want: nil,
+		},
+		"duplicate-service-env-name": {
+			input: &Config{
+				Services: []Service{{Name: "mock-server", Run: "echo"}, {Name: "mock_server", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid service name 'mock_server': name must be unique"),
+		},
+		"invalid-service-restart-policy": {
+			input: &Config{
+				Services: []Service{{Name: "db", Restart: "sometimes", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid restart policy 'sometimes' of service 'db': must be one of never, on-failure, always"),
+		},
+		"invalid-service-startup-timeout": {
+			input: &Config{
+				Services: []Service{{Name: "db", StartupTimeout: "a while", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid startup timeout 'a while' of service 'db'"),
+		},
+		"duplicate-setup-name": {
+			input: &Config{
+				Setup: []Hook{{Name: "login", Run: "echo"}, {Name: "login", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid setup name 'login': name must be unique"),
+		},
+		"invalid-teardown-timeout": {
+			input: &Config{
+				Teardown: []Hook{{Name: "logout", Timeout: "soon", Run: "echo"}},
+			},
+			want: errors.New("config validation failed: invalid timeout 'soon' of teardown 'logout'"),
+		},
+		"valid-setup-and-teardown": {
+			input: &Config{
+				Setup:    []Hook{{Name: "login", Timeout: "1m", Run: "echo"}},
+				Teardown: []Hook{{Name: "login", Run: "echo"}},
+			},
+			want: nil,
+		},
+		"notification-without-url": {
+			input: &Config{
+				Notifications: []Notification{{Name: "team", Type: "slack"}},
+			},
+			want: errors.New("config validation failed: notification 'team' of type 'slack' requires a 'url'"),
+		},
+		"notification-without-smtp-settings": {
+			input: &Config{
+				Notifications: []Notification{{Name: "mail", Type: "smtp", SMTP: &SMTP{Host: "localhost"}}},
+			},
+			want: errors.New("config validation failed: notification 'mail' of type 'smtp' requires 'smtp.host', 'smtp.from' and 'smtp.to'"),
+		},
+		"notification-with-unsupported-type": {
+			input: &Config{
+				Notifications: []Notification{{Name: "team", Type: "pager"}},
+			},
+			want: errors.New("config validation failed: notification 'team' has unsupported type 'pager'"),
+		},
+		"notification-with-invalid-trigger": {
+			input: &Config{
+				Notifications: []Notification{{Name: "team", Type: "webhook", URL: "http://localhost", On: &NotificationTrigger{Status: []string{"BLUE"}}}},
+			},
+			want: errors.New("config validation failed: notification 'team' has invalid status 'BLUE' in 'on.status'"),
+		},
+		"notification-with-invalid-period": {
+			input: &Config{
+				Notifications: []Notification{{Name: "team", Type: "webhook", URL: "http://localhost", On: &NotificationTrigger{ManualAnswersExpiring: "two weeks"}}},
+			},
+			want: errors.New("config validation failed: invalid 'on.manualAnswersExpiring' of notification 'team': invalid period 'two weeks'"),
+		},
+		"valid-notifications": {
+			input: &Config{
+				Notifications: []Notification{
+					{Name: "mail", Type: "smtp", SMTP: &SMTP{Host: "localhost", From: "qg@example.com", To: []string{"team@example.com"}}},
+					{Name: "team", Type: "teams", URL: "https://example.com/hook", On: &NotificationTrigger{Status: []string{"RED"}, StatusChanged: true, ManualAnswersExpiring: "14d"}},
+				},
+			},
+			want: nil,
+		},
+		"invalid-manual-expiry": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {
+						Requirements: map[string]Requirement{
+							"requirement1": {
+								Checks: map[string]Check{
+									"check1": {Manual: &Manual{Status: "GREEN", Reason: "reviewed", Expires: "31.12.2024"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid check 'check1': expiry date '31.12.2024' must have the format YYYY-MM-DD"),
+		},
+		"invalid-chapter-condition": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {When: "vars.HAS_UI =="},
+				},
+			},
+			want: errors.New("config validation failed: invalid chapter 'chapter1': invalid condition 'when': invalid expression 'vars.HAS_UI ==': unexpected end of expression"),
+		},
+		"invalid-requirement-valid-from": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {
+						Requirements: map[string]Requirement{
+							"requirement1": {ValidFrom: "01.07.2024"},
+						},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid requirement 'requirement1' of chapter 'chapter1': date '01.07.2024' of 'validFrom' must have the format YYYY-MM-DD"),
+		},
+		"invalid-check-validity-period": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {
+						Requirements: map[string]Requirement{
+							"requirement1": {
+								Checks: map[string]Check{
+									"check1": {ValidFrom: "2025-01-01", ValidUntil: "2024-12-31", Manual: &Manual{Status: "GREEN", Reason: "reviewed"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid check 'check1': 'validUntil' 2024-12-31 must not be before 'validFrom' 2025-01-01"),
+		},
+		"invalid-check-previous-id-of-current-check": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {
+						Requirements: map[string]Requirement{
+							"requirement1": {
+								Checks: map[string]Check{
+									"check1": {PreviousIds: []string{"check2"}, Manual: &Manual{Status: "GREEN", Reason: "reviewed"}},
+									"check2": {Manual: &Manual{Status: "GREEN", Reason: "reviewed"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid check 'check1': previous id 'check2' is the id of a current check"),
+		},
+		"invalid-check-previous-id-of-two-checks": {
+			input: &Config{
+				Chapters: map[string]Chapter{
+					"chapter1": {
+						Requirements: map[string]Requirement{
+							"requirement1": {
+								Checks: map[string]Check{
+									"check1": {PreviousIds: []string{"old"}, Manual: &Manual{Status: "GREEN", Reason: "reviewed"}},
+									"check2": {PreviousIds: []string{"old"}, Manual: &Manual{Status: "GREEN", Reason: "reviewed"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: errors.New("config validation failed: invalid check 'check2': previous id 'old' is also a previous id of check 'check1'"),
+		},
 		"valid-check": {
 			input: &Config{
 				Chapters: map[string]Chapter{
@@ -100,6 +373,10 @@ func TestCustomValidationForV2(t *testing.T) {
 											},
 											Autopilot: "autopilot1",
 										},
+										When:        "vars.HAS_UI == 'true'",
+										ValidFrom:   "2024-07-01",
+										ValidUntil:  "2024-07-01",
+										PreviousIds: []string{"check0", "old-check"},
 									},
 								},
 							},
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package egress
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var hostPattern = regexp.MustCompile(`^(\*\.)?[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?(\.[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?)*$`)
+
+// ValidatePattern checks an entry of an allowlist.
+// An entry is a host name, an IP address or a wildcard like '*.example.com', optionally followed by a port.
+func ValidatePattern(pattern string) error {
+	host, port, err := splitPattern(pattern)
+	if err != nil {
+		return err
+	}
+	if port < 0 || port > 65535 {
+		return errors.Errorf("invalid port in '%s'", pattern)
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if !hostPattern.MatchString(host) {
+		return errors.Errorf("invalid host '%s', expected a host name, an IP address or a wildcard like '*.example.com'", pattern)
+	}
+	return nil
+}
+
+// Allowed returns whether a destination matches an entry of the allowlist.
+// A wildcard matches all subdomains but not the domain itself, an entry without port matches all ports.
+func Allowed(allow []string, host string, port int) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range allow {
+		allowedHost, allowedPort, err := splitPattern(pattern)
+		if err != nil {
+			continue
+		}
+		if allowedPort != 0 && allowedPort != port {
+			continue
+		}
+		if domain, found := strings.CutPrefix(allowedHost, "*."); found {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(allowedHost); allowedIP != nil {
+			if allowedIP.Equal(net.ParseIP(host)) {
+				return true
+			}
+			continue
+		}
+		if allowedHost == host {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPattern returns the lower case host and the port of an entry, the port is 0 if the entry has none
+func splitPattern(pattern string) (string, int, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return "", 0, errors.New("empty host")
+	}
+	host, portString, err := net.SplitHostPort(pattern)
+	if err != nil {
+		// entries without port, including IPv6 addresses in brackets or without
+		return strings.Trim(pattern, "[]"), 0, nil
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil {
+		return "", 0, errors.Errorf("invalid port in '%s'", pattern)
+	}
+	return host, port, nil
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package egress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	allow := []string{"api.github.com", "*.sharepoint.com", "10.0.0.1:8443", "[::1]"}
+	testCases := map[string]struct {
+		host    string
+		port    int
+		allowed bool
+	}{
+		"exact host":                {host: "api.github.com", port: 443, allowed: true},
+		"exact host other case":     {host: "API.GitHub.com", port: 80, allowed: true},
+		"other host":                {host: "github.com", port: 443, allowed: false},
+		"subdomain of wildcard":     {host: "my.sharepoint.com", port: 443, allowed: true},
+		"nested subdomain":          {host: "a.b.sharepoint.com", port: 443, allowed: true},
+		"domain of wildcard":        {host: "sharepoint.com", port: 443, allowed: false},
+		"suffix without dot":        {host: "evilsharepoint.com", port: 443, allowed: false},
+		"ip with allowed port":      {host: "10.0.0.1", port: 8443, allowed: true},
+		"ip with other port":        {host: "10.0.0.1", port: 443, allowed: false},
+		"ipv6 address":              {host: "::1", port: 443, allowed: true},
+		"ipv6 address other format": {host: "0:0:0:0:0:0:0:1", port: 443, allowed: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.allowed, Allowed(allow, tc.host, tc.port))
+		})
+	}
+	assert.False(t, Allowed(nil, "api.github.com", 443))
+}
+
+func TestValidatePattern(t *testing.T) {
+	testCases := map[string]struct {
+		pattern string
+		err     string
+	}{
+		"host":              {pattern: "api.github.com"},
+		"host with port":    {pattern: "api.github.com:443"},
+		"wildcard":          {pattern: "*.sharepoint.com"},
+		"ipv4 address":      {pattern: "10.0.0.1"},
+		"ipv6 address":      {pattern: "[::1]:8443"},
+		"empty":             {pattern: "", err: "empty host"},
+		"url":               {pattern: "https://api.github.com", err: "invalid port in 'https://api.github.com'"},
+		"path":              {pattern: "api.github.com/repos", err: "invalid host 'api.github.com/repos', expected a host name, an IP address or a wildcard like '*.example.com'"},
+		"inner wildcard":    {pattern: "api.*.com", err: "invalid host 'api.*.com', expected a host name, an IP address or a wildcard like '*.example.com'"},
+		"port out of range": {pattern: "api.github.com:70000", err: "invalid port in 'api.github.com:70000'"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePattern(tc.pattern)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
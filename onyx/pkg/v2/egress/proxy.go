@@ -0,0 +1,368 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package egress
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const dialTimeout = 30 * time.Second
+
+// hopHeaders only apply to a single connection and are not forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Proxy is a local HTTP proxy which forwards the traffic of the steps and records the destinations they connect to.
+// If the network of an autopilot is restricted, destinations which are not allowed are rejected.
+// HTTPS connections are tunneled without being intercepted, so that only the host and port of the destination are known.
+type Proxy struct {
+	logger    logger.Logger
+	transport *http.Transport
+	listener  net.Listener
+	server    *http.Server
+
+	mu      sync.Mutex
+	clients map[string]*client
+	tunnels map[net.Conn]struct{}
+}
+
+// client is a step which uses the proxy, it is identified by a random token used as user name in the proxy URL
+type client struct {
+	check     string
+	step      string
+	autopilot string
+	network   *model.Network
+	// upstream returns the proxy of the step for a destination, nil if it is connected directly
+	upstream     func(*url.URL) (*url.URL, error)
+	destinations map[string]*model.Destination
+}
+
+// clientKey is the key of the client in the context of a forwarded request
+type clientKey struct{}
+
+func New(logger logger.Logger) *Proxy {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the responses are passed to the steps as they are
+	transport.DisableCompression = true
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return req.Context().Value(clientKey{}).(*client).upstream(req.URL)
+	}
+	return &Proxy{
+		logger:    logger,
+		transport: transport,
+		clients:   make(map[string]*client),
+		tunnels:   make(map[net.Conn]struct{}),
+	}
+}
+
+// Start starts the proxy on a random port of localhost
+func (p *Proxy) Start() error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "failed to start egress proxy")
+	}
+	p.listener = listener
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Errorf("egress proxy stopped: %s", err.Error())
+		}
+	}()
+	p.logger.Infof("egress proxy is listening on %s", listener.Addr().String())
+	return nil
+}
+
+// Register adds a step of a check and returns the environment variables which route its traffic through the proxy.
+// If network is nil, all destinations are allowed. The traffic is forwarded to the proxies configured in upstream,
+// the hosts in its NoProxy are still reached without the proxy, as well as localhost.
+func (p *Proxy) Register(check, step, autopilot string, network *model.Network, upstream httpproxy.Config) (map[string]string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, errors.Wrap(err, "failed to generate token for egress proxy")
+	}
+	user := hex.EncodeToString(token)
+	p.mu.Lock()
+	p.clients[user] = &client{
+		check:        check,
+		step:         step,
+		autopilot:    autopilot,
+		network:      network,
+		upstream:     upstream.ProxyFunc(),
+		destinations: make(map[string]*model.Destination),
+	}
+	p.mu.Unlock()
+
+	proxy := (&url.URL{Scheme: "http", User: url.User(user), Host: p.listener.Addr().String()}).String()
+	bypass := "localhost,127.0.0.1"
+	if upstream.NoProxy != "" {
+		bypass = upstream.NoProxy + "," + bypass
+	}
+	return map[string]string{
+		"HTTP_PROXY":  proxy,
+		"HTTPS_PROXY": proxy,
+		"http_proxy":  proxy,
+		"https_proxy": proxy,
+		"NO_PROXY":    bypass,
+		"no_proxy":    bypass,
+	}, nil
+}
+
+// Destinations returns the destinations a step connected to, sorted by host and port
+func (p *Proxy) Destinations(check, step string) []model.Destination {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var destinations []model.Destination
+	for _, c := range p.clients {
+		if c.check != check || c.step != step {
+			continue
+		}
+		for _, destination := range c.destinations {
+			destinations = append(destinations, *destination)
+		}
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		if destinations[i].Host != destinations[j].Host {
+			return destinations[i].Host < destinations[j].Host
+		}
+		return destinations[i].Port < destinations[j].Port
+	})
+	return destinations
+}
+
+// Stop stops the proxy and closes all open tunnels
+func (p *Proxy) Stop() {
+	if p.server != nil {
+		_ = p.server.Close()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for conn := range p.tunnels {
+		conn.Close()
+	}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	c := p.client(req.Header.Get("Proxy-Authorization"))
+	if c == nil {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="onyx"`)
+		http.Error(w, "onyx egress proxy: unknown client, use the proxy configured in HTTP_PROXY and HTTPS_PROXY", http.StatusProxyAuthRequired)
+		return
+	}
+	target := req.Host
+	if req.Method != http.MethodConnect {
+		if !req.URL.IsAbs() {
+			http.Error(w, "onyx egress proxy only accepts proxy requests", http.StatusBadRequest)
+			return
+		}
+		target = req.URL.Host
+	}
+	host, port := splitHostPort(target, req.Method == http.MethodConnect || req.URL.Scheme == "https")
+	if !p.admit(c, host, port) {
+		msg := fmt.Sprintf("onyx egress proxy: '%s' is not allowed by network.allow of autopilot '%s'", net.JoinHostPort(host, strconv.Itoa(port)), c.autopilot)
+		http.Error(w, msg, http.StatusForbidden)
+		return
+	}
+	if req.Method == http.MethodConnect {
+		p.tunnel(w, c, net.JoinHostPort(host, strconv.Itoa(port)))
+		return
+	}
+	p.forward(w, c, req)
+}
+
+// client returns the step identified by the user name of the basic proxy authorization
+func (p *Proxy) client(authorization string) *client {
+	encoded, found := strings.CutPrefix(authorization, "Basic ")
+	if !found {
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	user, _, _ := strings.Cut(string(decoded), ":")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clients[user]
+}
+
+// admit records the destination of a request and returns whether it is allowed
+func (p *Proxy) admit(c *client, host string, port int) bool {
+	allowed := c.network == nil || Allowed(c.network.Allow, host, port)
+	p.mu.Lock()
+	key := net.JoinHostPort(host, strconv.Itoa(port))
+	destination, ok := c.destinations[key]
+	if !ok {
+		destination = &model.Destination{Host: host, Port: port}
+		c.destinations[key] = destination
+	}
+	destination.Requests++
+	destination.Denied = destination.Denied || !allowed
+	p.mu.Unlock()
+	if !allowed {
+		p.logger.Warnf("egress proxy: rejected connection of step '%s' of check '%s' to '%s'", c.step, c.check, key)
+	}
+	return allowed
+}
+
+// forward sends a plain HTTP request to its destination and streams the response back
+func (p *Proxy) forward(w http.ResponseWriter, c *client, req *http.Request) {
+	out := req.Clone(context.WithValue(req.Context(), clientKey{}, c))
+	out.RequestURI = ""
+	for _, key := range hopHeaders {
+		out.Header.Del(key)
+	}
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("onyx egress proxy: %s", err.Error()), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for _, key := range hopHeaders {
+		resp.Header.Del(key)
+	}
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel connects the client with the destination and copies the data in both directions
+func (p *Proxy) tunnel(w http.ResponseWriter, c *client, target string) {
+	upstream, err := p.dial(c, target)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("onyx egress proxy: %s", err.Error()), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "connection can not be tunneled", http.StatusInternalServerError)
+		return
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	p.mu.Lock()
+	p.tunnels[conn] = struct{}{}
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.tunnels, conn)
+		p.mu.Unlock()
+		conn.Close()
+		upstream.Close()
+	}()
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, buffered.Reader)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	// the tunnel is closed as soon as one side closes its connection
+	<-done
+}
+
+// dial connects to the destination, through the upstream proxy of the step if there is one
+func (p *Proxy) dial(c *client, target string) (net.Conn, error) {
+	proxyURL, err := c.upstream(&url.URL{Scheme: "https", Host: target})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy configuration")
+	}
+	if proxyURL == nil {
+		return net.DialTimeout("tcp", target, dialTimeout)
+	}
+	conn, err := net.DialTimeout("tcp", proxyURL.Host, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		connect.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := connect.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, connect)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, errors.Errorf("proxy '%s' refused to connect to '%s': %s", proxyURL.Host, target, resp.Status)
+	}
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn reads the data which was already buffered while reading the response of the upstream proxy first
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// splitHostPort returns the lower case host and the port of a target, the default port is used if the target has none
+func splitHostPort(target string, tls bool) (string, int) {
+	host, portString, err := net.SplitHostPort(target)
+	if err != nil {
+		host = strings.Trim(target, "[]")
+		portString = "80"
+		if tls {
+			portString = "443"
+		}
+	}
+	port, _ := strconv.Atoi(portString)
+	return strings.ToLower(host), port
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package egress
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http/httpproxy"
+)
+
+func newUpstream(t *testing.T, tls bool) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "hello from %s", req.URL.Path)
+	})
+	var server *httptest.Server
+	if tls {
+		server = httptest.NewTLSServer(handler)
+	} else {
+		server = httptest.NewServer(handler)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func startProxy(t *testing.T) *Proxy {
+	proxy := New(logger.Get())
+	require.NoError(t, proxy.Start())
+	t.Cleanup(proxy.Stop)
+	return proxy
+}
+
+// proxyClient sends requests through the proxy in the same way as a step configured with the environment of the proxy
+func proxyClient(t *testing.T, env map[string]string, upstream *httptest.Server) *http.Client {
+	proxyURL, err := url.Parse(env["HTTPS_PROXY"])
+	require.NoError(t, err)
+	transport := upstream.Client().Transport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return &http.Client{Transport: transport}
+}
+
+func get(t *testing.T, client *http.Client, target string) (int, string) {
+	resp, err := client.Get(target)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(content)
+}
+
+func hostPort(t *testing.T, server *httptest.Server) (string, int) {
+	host, portString, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portString)
+	require.NoError(t, err)
+	return host, port
+}
+
+func TestProxyRecordsDestinations(t *testing.T) {
+	for name, useTLS := range map[string]bool{"http": false, "https": true} {
+		t.Run(name, func(t *testing.T) {
+			upstream := newUpstream(t, useTLS)
+			host, port := hostPort(t, upstream)
+			proxy := startProxy(t)
+			env, err := proxy.Register("1_1_check", "fetch", "my-autopilot", nil, httpproxy.Config{})
+			require.NoError(t, err)
+
+			c := proxyClient(t, env, upstream)
+			status, body := get(t, c, upstream.URL+"/items")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, "hello from /items", body)
+			c.CloseIdleConnections()
+			_, _ = get(t, c, upstream.URL+"/other")
+
+			assert.Equal(t, []model.Destination{{Host: host, Port: port, Requests: 2}}, proxy.Destinations("1_1_check", "fetch"))
+			assert.Empty(t, proxy.Destinations("1_1_check", "other"))
+			assert.Empty(t, proxy.Destinations("1_1_other", "fetch"))
+		})
+	}
+}
+
+func TestProxyRejectsDestinationsNotAllowed(t *testing.T) {
+	for name, useTLS := range map[string]bool{"http": false, "https": true} {
+		t.Run(name, func(t *testing.T) {
+			upstream := newUpstream(t, useTLS)
+			host, port := hostPort(t, upstream)
+			proxy := startProxy(t)
+			allowedEnv, err := proxy.Register("1_1_check", "allowed", "my-autopilot", &model.Network{Allow: []string{host}}, httpproxy.Config{})
+			require.NoError(t, err)
+			deniedEnv, err := proxy.Register("1_1_check", "denied", "my-autopilot", &model.Network{Allow: []string{"example.com"}}, httpproxy.Config{})
+			require.NoError(t, err)
+
+			status, _ := get(t, proxyClient(t, allowedEnv, upstream), upstream.URL)
+			assert.Equal(t, http.StatusOK, status)
+			if useTLS {
+				_, err := proxyClient(t, deniedEnv, upstream).Get(upstream.URL)
+				assert.ErrorContains(t, err, "Forbidden")
+			} else {
+				status, body := get(t, proxyClient(t, deniedEnv, upstream), upstream.URL)
+				assert.Equal(t, http.StatusForbidden, status)
+				assert.Equal(t, fmt.Sprintf("onyx egress proxy: '%s:%d' is not allowed by network.allow of autopilot 'my-autopilot'\n", host, port), body)
+			}
+
+			assert.Equal(t, []model.Destination{{Host: host, Port: port, Requests: 1}}, proxy.Destinations("1_1_check", "allowed"))
+			assert.Equal(t, []model.Destination{{Host: host, Port: port, Requests: 1, Denied: true}}, proxy.Destinations("1_1_check", "denied"))
+		})
+	}
+}
+
+func TestProxyRejectsUnknownClients(t *testing.T) {
+	upstream := newUpstream(t, false)
+	proxy := startProxy(t)
+	env, err := proxy.Register("1_1_check", "fetch", "my-autopilot", nil, httpproxy.Config{})
+	require.NoError(t, err)
+	proxyURL, err := url.Parse(env["HTTP_PROXY"])
+	require.NoError(t, err)
+	proxyURL.User = url.User("unknown")
+
+	status, _ := get(t, &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}, upstream.URL)
+	assert.Equal(t, http.StatusProxyAuthRequired, status)
+	assert.Empty(t, proxy.Destinations("1_1_check", "fetch"))
+}
+
+func TestProxyNoProxy(t *testing.T) {
+	testCases := map[string]struct {
+		noProxy string
+		want    string
+	}{
+		"should bypass localhost": {
+			want: "localhost,127.0.0.1",
+		},
+		"should keep the hosts of the user": {
+			noProxy: "internal.example.com,.corp",
+			want:    "internal.example.com,.corp,localhost,127.0.0.1",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			proxy := startProxy(t)
+			env, err := proxy.Register("1_1_check", "fetch", "my-autopilot", nil, httpproxy.Config{NoProxy: tc.noProxy})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, env["NO_PROXY"])
+			assert.Equal(t, tc.want, env["no_proxy"])
+		})
+	}
+}
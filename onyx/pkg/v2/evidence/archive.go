@@ -0,0 +1,348 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Verification is the outcome of comparing a file with the manifest
+type Verification string
+
+const (
+	// Verified files have the hash listed in the manifest
+	Verified Verification = "verified"
+	// Modified files have a different hash than listed in the manifest
+	Modified Verification = "modified"
+	// Unlisted files are not part of the manifest
+	Unlisted Verification = "unlisted"
+	// Unverified files are in an archive without manifest or are the manifest itself, its timestamp or the log
+	Unverified Verification = "unverified"
+)
+
+// Archive is an opened evidence archive
+type Archive struct {
+	reader   *zip.ReadCloser
+	files    map[string]*zip.File
+	manifest *Manifest
+	statuses map[string]string
+}
+
+// Entry is a file in an evidence archive
+type Entry struct {
+	// Slash separated path in the archive
+	Path string
+	// Directory of the check the file belongs to, e.g., "1_2_3", empty for files of the run
+	Check string
+	// Status of the check according to the result
+	Status string
+	// ID of the step the file belongs to, empty for files of the evaluation
+	Step         string
+	Size         uint64
+	Verification Verification
+}
+
+// Filter selects entries of an archive, empty fields match all entries
+type Filter struct {
+	// Check ID in the format <chapter>_<requirement>_<check>
+	Check string
+	// Step ID
+	Step string
+	// Glob matched against the path and the name of a file
+	Glob string
+}
+
+// Open opens the evidence archive at path and reads its manifest and the statuses of the checks
+func Open(archivePath string) (*Archive, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, model.NewUserErr(errors.Wrapf(err, "failed to open evidence archive '%s'", archivePath), "invalid evidence archive")
+	}
+	a := &Archive{reader: reader, files: map[string]*zip.File{}, statuses: map[string]string{}}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		a.files[strings.ReplaceAll(file.Name, "\\", "/")] = file
+	}
+	if err := a.readManifest(); err != nil {
+		reader.Close()
+		return nil, err
+	}
+	if err := a.readStatuses(); err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Archive) Close() error {
+	return a.reader.Close()
+}
+
+// HasManifest returns true if the archive contains a manifest
+func (a *Archive) HasManifest() bool {
+	return a.manifest != nil
+}
+
+// Statuses returns the statuses of the checks by their directory in the archive
+func (a *Archive) Statuses() map[string]string {
+	return a.statuses
+}
+
+// Missing returns the files listed in the manifest which are not in the archive
+func (a *Archive) Missing() []string {
+	if a.manifest == nil {
+		return nil
+	}
+	var missing []string
+	for name := range a.manifest.Files {
+		if _, ok := a.files[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Entries returns the files matching the filter sorted by path, their content is verified against the manifest
+func (a *Archive) Entries(filter Filter) ([]Entry, error) {
+	var entries []Entry
+	for _, name := range a.sortedNames() {
+		entry := a.entry(name)
+		if !filter.matches(entry) {
+			continue
+		}
+		verification, err := a.verify(name)
+		if err != nil {
+			return nil, err
+		}
+		entry.Verification = verification
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadFile returns the content of a file, it fails if the file was modified
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	file, ok := a.files[name]
+	if !ok {
+		return nil, model.NewUserErr(errors.Errorf("file '%s' not found in evidence archive", name), "file not found")
+	}
+	content, err := readZipFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if a.verification(name, content) == Modified {
+		return nil, model.NewUserErr(errors.Errorf("file '%s' does not match the hash in the manifest", name), "evidence verification failed")
+	}
+	return content, nil
+}
+
+// Extract writes the files matching the filter to dest.
+// Nothing is written if any of the files was modified.
+func (a *Archive) Extract(filter Filter, dest string) ([]Entry, error) {
+	entries, err := a.Entries(filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerificationErr(entries, nil); err != nil {
+		return nil, err
+	}
+	dest = filepath.Clean(dest)
+	for _, entry := range entries {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Path)) {
+			return nil, model.NewUserErr(errors.Errorf("invalid entry '%s': path is outside of the destination", entry.Path), "invalid evidence archive")
+		}
+		target := filepath.Join(dest, filepath.FromSlash(entry.Path))
+		content, err := readZipFile(a.files[entry.Path])
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed to create folder for '%s'", entry.Path)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return nil, errors.Wrapf(err, "failed to write '%s'", entry.Path)
+		}
+	}
+	return entries, nil
+}
+
+// VerificationErr returns a user error if any of the entries was modified or files of the manifest are missing
+func VerificationErr(entries []Entry, missing []string) error {
+	var errs []error
+	for _, entry := range entries {
+		if entry.Verification == Modified {
+			errs = append(errs, errors.Errorf("file '%s' does not match the hash in the manifest", entry.Path))
+		}
+	}
+	for _, name := range missing {
+		errs = append(errs, errors.Errorf("file '%s' of the manifest is missing", name))
+	}
+	if len(errs) > 0 {
+		return model.NewUserErr(helper.Join(errs...), "evidence verification failed")
+	}
+	return nil
+}
+
+func (a *Archive) sortedNames() []string {
+	names := make([]string, 0, len(a.files))
+	for name := range a.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// entry derives check and step from the path, check files are in '<check>/steps/<step>/...' and '<check>/evaluation/...'
+func (a *Archive) entry(name string) Entry {
+	entry := Entry{Path: name, Size: a.files[name].UncompressedSize64}
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 {
+		return entry
+	}
+	status, isCheck := a.statuses[parts[0]]
+	if !isCheck && parts[1] != "steps" && parts[1] != "evaluation" {
+		return entry
+	}
+	entry.Check = parts[0]
+	entry.Status = status
+	if len(parts) > 3 && parts[1] == "steps" {
+		entry.Step = parts[2]
+	}
+	return entry
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.Check != "" && entry.Check != f.Check {
+		return false
+	}
+	if f.Step != "" && entry.Step != f.Step {
+		return false
+	}
+	if f.Glob != "" {
+		matchesPath, _ := path.Match(f.Glob, entry.Path)
+		matchesName, _ := path.Match(f.Glob, path.Base(entry.Path))
+		return matchesPath || matchesName
+	}
+	return true
+}
+
+func (a *Archive) verify(name string) (Verification, error) {
+	if a.manifest == nil || !hashed(name) {
+		return Unverified, nil
+	}
+	if _, ok := a.manifest.Files[name]; !ok {
+		return Unlisted, nil
+	}
+	content, err := readZipFile(a.files[name])
+	if err != nil {
+		return "", err
+	}
+	return a.verification(name, content), nil
+}
+
+func (a *Archive) verification(name string, content []byte) Verification {
+	if a.manifest == nil || !hashed(name) {
+		return Unverified
+	}
+	hash, ok := a.manifest.Files[name]
+	if !ok {
+		return Unlisted
+	}
+	actual, _ := hashReader(bytes.NewReader(content))
+	if actual != hash {
+		return Modified
+	}
+	return Verified
+}
+
+func (a *Archive) readManifest() error {
+	file, ok := a.files[MANIFEST_FILE]
+	if !ok {
+		return nil
+	}
+	content, err := readZipFile(file)
+	if err != nil {
+		return err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return model.NewUserErr(errors.Wrap(err, "failed to parse manifest"), "invalid evidence archive")
+	}
+	if manifest.Algorithm != AlgorithmSHA256 {
+		return model.NewUserErr(errors.Errorf("unsupported hash algorithm '%s' in manifest", manifest.Algorithm), "invalid evidence archive")
+	}
+	a.manifest = &manifest
+	return nil
+}
+
+// resultStatuses is the part of a result file containing the statuses of the checks, v1 results have the status on the check
+type resultStatuses struct {
+	Chapters map[string]struct {
+		Requirements map[string]struct {
+			Checks map[string]struct {
+				Status     string `yaml:"status"`
+				Evaluation struct {
+					Status string `yaml:"status"`
+				} `yaml:"evaluation"`
+			} `yaml:"checks"`
+		} `yaml:"requirements"`
+	} `yaml:"chapters"`
+}
+
+func (a *Archive) readStatuses() error {
+	file, ok := a.files[RESULT_FILE]
+	if !ok {
+		return nil
+	}
+	content, err := readZipFile(file)
+	if err != nil {
+		return err
+	}
+	var res resultStatuses
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return model.NewUserErr(errors.Wrapf(err, "failed to parse '%s'", RESULT_FILE), "invalid evidence archive")
+	}
+	for chapID, chap := range res.Chapters {
+		for reqID, req := range chap.Requirements {
+			for checkID, check := range req.Checks {
+				status := check.Evaluation.Status
+				if check.Status != "" {
+					status = check.Status
+				}
+				a.statuses[strings.Join([]string{chapID, reqID, checkID}, "_")] = status
+			}
+		}
+	}
+	return nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open '%s'", file.Name)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read '%s'", file.Name)
+	}
+	return content, nil
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package evidence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/zip"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const result = `chapters:
+  "1":
+    requirements:
+      "2":
+        checks:
+          "3":
+            evaluation:
+              status: GREEN
+`
+
+// createArchive zips the files with a manifest, modify changes files after the manifest was written
+func createArchive(t *testing.T, withManifest bool, modify map[string]string) string {
+	dir := filepath.Join(t.TempDir(), "run")
+	files := map[string]string{
+		RESULT_FILE:                         result,
+		"1_2_3/steps/fetch/files/data.json": `{"key": "value"}`,
+		"1_2_3/steps/fetch/logs.txt":        "fetched",
+		"1_2_3/evaluation/result.json":      `{"status": "GREEN"}`,
+		LOG_FILE:                            "started",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	if withManifest {
+		require.NoError(t, WriteManifest(dir))
+	}
+	for name, content := range modify {
+		if content == "" {
+			require.NoError(t, os.Remove(filepath.Join(dir, name)))
+			continue
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	archive := filepath.Join(t.TempDir(), "evidence.zip")
+	z := zip.New(afero.NewOsFs())
+	require.NoError(t, z.Directory(dir, archive))
+	return archive
+}
+
+func TestEntries(t *testing.T) {
+	testCases := map[string]struct {
+		withManifest bool
+		modify       map[string]string
+		filter       Filter
+		want         []Entry
+		wantMissing  []string
+	}{
+		"should verify files and add statuses": {
+			withManifest: true,
+			filter:       Filter{Check: "1_2_3"},
+			want: []Entry{
+				{Path: "1_2_3/evaluation/result.json", Check: "1_2_3", Status: "GREEN", Size: 19, Verification: Verified},
+				{Path: "1_2_3/steps/fetch/files/data.json", Check: "1_2_3", Status: "GREEN", Step: "fetch", Size: 16, Verification: Verified},
+				{Path: "1_2_3/steps/fetch/logs.txt", Check: "1_2_3", Status: "GREEN", Step: "fetch", Size: 7, Verification: Verified},
+			},
+		},
+		"should filter by step and glob": {
+			withManifest: true,
+			filter:       Filter{Step: "fetch", Glob: "*.json"},
+			want: []Entry{
+				{Path: "1_2_3/steps/fetch/files/data.json", Check: "1_2_3", Status: "GREEN", Step: "fetch", Size: 16, Verification: Verified},
+			},
+		},
+		"should detect modified and missing files": {
+			withManifest: true,
+			modify:       map[string]string{"1_2_3/steps/fetch/logs.txt": "changed", "1_2_3/evaluation/result.json": ""},
+			filter:       Filter{Glob: "1_2_3/*/*/*.txt"},
+			want: []Entry{
+				{Path: "1_2_3/steps/fetch/logs.txt", Check: "1_2_3", Status: "GREEN", Step: "fetch", Size: 7, Verification: Modified},
+			},
+			wantMissing: []string{"1_2_3/evaluation/result.json"},
+		},
+		"should not verify the log which is written after the manifest": {
+			withManifest: true,
+			modify:       map[string]string{LOG_FILE: "started\nfinished"},
+			filter:       Filter{Glob: LOG_FILE},
+			want: []Entry{
+				{Path: LOG_FILE, Size: 16, Verification: Unverified},
+			},
+		},
+		"should not verify without manifest": {
+			filter: Filter{Glob: RESULT_FILE},
+			want: []Entry{
+				{Path: RESULT_FILE, Size: uint64(len(result)), Verification: Unverified},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			archive, err := Open(createArchive(t, tc.withManifest, tc.modify))
+			require.NoError(t, err)
+			defer archive.Close()
+
+			entries, err := archive.Entries(tc.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, entries)
+			assert.Equal(t, tc.wantMissing, archive.Missing())
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	archive, err := Open(createArchive(t, true, map[string]string{"1_2_3/steps/fetch/logs.txt": "changed"}))
+	require.NoError(t, err)
+	defer archive.Close()
+
+	content, err := archive.ReadFile("1_2_3/steps/fetch/files/data.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"key": "value"}`, string(content))
+
+	_, err = archive.ReadFile("1_2_3/steps/fetch/logs.txt")
+	assert.ErrorContains(t, err, "file '1_2_3/steps/fetch/logs.txt' does not match the hash in the manifest")
+
+	_, err = archive.ReadFile("unknown.txt")
+	assert.ErrorContains(t, err, "file 'unknown.txt' not found in evidence archive")
+}
+
+func TestExtract(t *testing.T) {
+	archive, err := Open(createArchive(t, true, nil))
+	require.NoError(t, err)
+	defer archive.Close()
+	dest := t.TempDir()
+
+	entries, err := archive.Extract(Filter{Step: "fetch"}, dest)
+
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	content, err := os.ReadFile(filepath.Join(dest, "1_2_3", "steps", "fetch", "logs.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "fetched", string(content))
+	assert.NoFileExists(t, filepath.Join(dest, "1_2_3", "evaluation", "result.json"))
+}
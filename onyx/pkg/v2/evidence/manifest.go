@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package evidence describes and reads the evidence archives of runs.
+// An archive contains a manifest with the SHA-256 hashes of all other files,
+// so that modified or missing evidence files can be detected.
+package evidence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/timestamp"
+	"github.com/pkg/errors"
+)
+
+const (
+	MANIFEST_FILE = "evidence-manifest.json"
+	// TIMESTAMP_FILE is the RFC 3161 timestamp token of the manifest
+	TIMESTAMP_FILE = "evidence-manifest.tsr"
+	// RESULT_FILE is the result of the run which is part of the evidence
+	RESULT_FILE = "qg-result.yaml"
+	// LOG_FILE is the log of the run, it is still written after the manifest and therefore not hashed
+	LOG_FILE = "onyx.log"
+
+	AlgorithmSHA256 = "sha256"
+)
+
+// Manifest lists the files of an evidence archive with their hashes
+type Manifest struct {
+	Algorithm string `json:"algorithm"`
+	// Hashes of the files by their slash separated path in the archive
+	Files map[string]string `json:"files"`
+}
+
+// NewManifest hashes all files in dir, an existing manifest, its timestamp and the log are not included
+func NewManifest(dir string) (*Manifest, error) {
+	manifest := &Manifest{Algorithm: AlgorithmSHA256, Files: map[string]string{}}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if !hashed(relPath) {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		hash, err := hashReader(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to hash '%s'", relPath)
+		}
+		manifest.Files[relPath] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create manifest of '%s'", dir)
+	}
+	return manifest, nil
+}
+
+// WriteManifest writes the manifest of all files in dir into dir
+func WriteManifest(dir string) error {
+	manifest, err := NewManifest(dir)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+	return os.WriteFile(filepath.Join(dir, MANIFEST_FILE), content, 0644)
+}
+
+// WriteTimestamp requests a timestamp token for the manifest in dir from the TSA at tsaURL and writes it into dir.
+// As the manifest contains the hashes of the result and all other files, the token proves that they existed at its time.
+func WriteTimestamp(dir, tsaURL string) ([]byte, error) {
+	manifest, err := os.ReadFile(filepath.Join(dir, MANIFEST_FILE))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	digest := sha256.Sum256(manifest)
+	token, err := timestamp.Request(tsaURL, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, TIMESTAMP_FILE), token, 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to write timestamp")
+	}
+	return token, nil
+}
+
+// hashed reports whether the file with the slash separated path name is listed in the manifest
+func hashed(name string) bool {
+	return name != MANIFEST_FILE && name != TIMESTAMP_FILE && name != LOG_FILE
+}
+
+func hashReader(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
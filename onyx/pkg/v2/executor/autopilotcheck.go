@@ -30,7 +30,7 @@ type AutopilotExecutor struct {
 	strict      bool
 	logger      *logger.Autopilot
 	timeout     time.Duration
-	runner      *runner.Subprocess
+	runner      runner.Runner
 }
 
 type stepDirs struct {
@@ -56,7 +56,16 @@ func NewAutopilotExecutor(wdUtils workdir.Utilizer, rootWorkDir string, strict b
 	}
 }
 
+// WithShell selects the shell which runs the scripts, see runner.New
+func (a *AutopilotExecutor) WithShell(shell string) *AutopilotExecutor {
+	a.runner = runner.New(shell, a.logger)
+	return a
+}
+
 func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, env, secrets map[string]string) (*model.AutopilotResult, error) {
+	if result := checkApplicability(item, a.logger); result != nil {
+		return result, nil
+	}
 	if result := checkErrors(item, a.logger); result != nil {
 		return result, nil
 	}
@@ -114,10 +123,16 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 				"AUTOPILOT_INPUT_DIRS":  strings.Join(inputDirs, strconv.QuoteRune(os.PathListSeparator)),
 				"AUTOPILOT_RESULT_FILE": filepath.Join(stepDirs.stepDir, "data.json"),
 			}
-			runtimeEnv := helper.MergeMaps(env, step.Env, item.Autopilot.Env, specialEnv)
+			// create secret files, they are removed as soon as the step is done
+			secretFilesEnv, removeSecretFiles, err := createSecretFiles(mergeSecretFiles(item.Autopilot.SecretFiles, step.SecretFiles), secrets)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to create secret files for step '%s'", step.ID))
+			}
+			runtimeEnv := helper.MergeMaps(env, step.Env, item.Autopilot.Env, step.ProxyEnv, secretFilesEnv, specialEnv)
 			// do run
 			a.logger.Info(fmt.Sprintf("starting autopilot '%s' step '%s'", item.Autopilot.Name, step.ID))
 			runnerOutput, err := StartRunner(stepDirs.workDir, step.Run, runtimeEnv, secrets, a.logger, a.runner, a.timeout)
+			removeSecretFiles()
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("failed to run autopilot '%s' step '%s'", item.Autopilot.Name, step.ID))
 			}
@@ -140,6 +155,14 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create configuration files for evaluation")
 	}
+	// autopilots of legacy checks have no steps, they work on the files of the input folder in the evaluation
+	if item.Legacy {
+		err = a.wdUtils.LinkFiles(a.rootWorkDir, evalDir.String())
+		defer a.wdUtils.RemoveLinkedFiles(evalDir.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to link files for evaluation")
+		}
+	}
 	var evalInputFiles []string
 	for _, step := range stepResults {
 		dataFile := step.ResultFile
@@ -152,9 +175,14 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 		"EVALUATOR_INPUT_FILES": strings.Join(evalInputFiles, strconv.QuoteRune(os.PathListSeparator)),
 		"EVALUATOR_RESULT_FILE": filepath.Join(evalDir.String(), "result.json"),
 	}
-	runtimeEnv := helper.MergeMaps(env, item.Autopilot.Evaluate.Env, specialEnv)
+	secretFilesEnv, removeSecretFiles, err := createSecretFiles(item.Autopilot.SecretFiles, secrets)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secret files for evaluation")
+	}
+	runtimeEnv := helper.MergeMaps(env, item.Autopilot.Evaluate.Env, secretFilesEnv, specialEnv)
 	a.logger.Info("doing evaluation")
 	evalOutput, err := StartRunner(evalDir.String(), item.Autopilot.Evaluate.Run, runtimeEnv, secrets, a.logger, a.runner, a.timeout)
+	removeSecretFiles()
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to run autopilot '%s' evaluation", item.Autopilot.Name))
 	}
@@ -179,7 +207,8 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 		},
 		Name: item.Autopilot.Name,
 	}
-	checkResult(autopilotResult, a.strict, a.timeout, a.logger)
+	checkResult(autopilotResult, item.Legacy, a.strict, a.timeout, a.logger)
+	checkEvidence(autopilotResult, a.rootWorkDir, a.strict, a.logger)
 	output := output.Output{
 		ExitCode:     autopilotResult.EvaluateResult.ExitCode,
 		EvidencePath: checkDir.String(),
@@ -196,6 +225,20 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 	return autopilotResult, nil
 }
 
+func checkApplicability(item *model.AutopilotCheck, logger *logger.Autopilot) *model.AutopilotResult {
+	if item.NotApplicable == nil {
+		return nil
+	}
+	logger.Info(fmt.Sprintf("autopilot '%s' won't be executed: %s", item.Autopilot.Name, item.NotApplicable.Reason))
+	return &model.AutopilotResult{
+		EvaluateResult: model.EvaluateResult{
+			Status: item.NotApplicable.Status,
+			Reason: item.NotApplicable.Reason,
+		},
+		Name: item.Autopilot.Name,
+	}
+}
+
 func checkErrors(item *model.AutopilotCheck, logger *logger.Autopilot) *model.AutopilotResult {
 	if len(item.ValidationErrs) > 0 {
 		msg := fmt.Sprintf("autopilot '%s' has the following validation errors and won't be executed: %s", item.Autopilot.Name, errs.Join(item.ValidationErrs...).Error())
@@ -284,6 +327,9 @@ func parseEvaluatorResult(runnerOutput *runner.Output) (*evaluateResult, error)
 		if results, ok := data["result"].(map[string]interface{}); ok {
 			r := model.Result{}
 			resultMap := results
+			if id, ok := resultMap["id"].(string); ok {
+				r.ID = id
+			}
 			if criteria, ok := resultMap["criterion"].(string); ok {
 				r.Criterion = criteria
 			}
@@ -312,13 +358,19 @@ func parseEvaluatorResult(runnerOutput *runner.Output) (*evaluateResult, error)
 					r.Metadata = dataMap
 				}
 			}
+			if evidence, ok := resultMap["evidence"].([]interface{}); ok {
+				for _, reference := range evidence {
+					r.Evidence = append(r.Evidence, model.EvidenceReference{Reference: fmt.Sprintf("%v", reference)})
+				}
+			}
 			out.results = append(out.results, r)
 		}
 	}
 	return out, nil
 }
 
-func checkResult(result *model.AutopilotResult, strict bool, timeout time.Duration, logger *logger.Autopilot) {
+// checkResult validates the output of the evaluation, autopilots of legacy checks can also provide the status 'FAILED' without any results
+func checkResult(result *model.AutopilotResult, legacy, strict bool, timeout time.Duration, logger *logger.Autopilot) {
 	if result.EvaluateResult.ExitCode != 0 {
 		var msg string
 		if result.EvaluateResult.ExitCode == 124 {
@@ -331,8 +383,11 @@ func checkResult(result *model.AutopilotResult, strict bool, timeout time.Durati
 		logger.UserError(msg)
 		return
 	}
-	// autopilot must provide a status of RED, GREEN, YELLOW
-	allowedStatus := []string{"RED", "GREEN", "YELLOW"}
+	// autopilot must provide a status of RED, GREEN, YELLOW, NA or SKIPPED
+	allowedStatus := []string{"RED", "GREEN", "YELLOW", "NA", "SKIPPED"}
+	if legacy {
+		allowedStatus = append(allowedStatus, "FAILED")
+	}
 	if !helper.Contains(allowedStatus, result.EvaluateResult.Status) {
 		msg := fmt.Sprintf("autopilot '%s' provided an invalid 'status': '%s'", result.Name, result.EvaluateResult.Status)
 		result.EvaluateResult.Status = "ERROR"
@@ -340,17 +395,33 @@ func checkResult(result *model.AutopilotResult, strict bool, timeout time.Durati
 		logger.UserError(msg)
 		return
 	}
+	// autopilot with status NA must always explain why the check is not applicable
+	if result.EvaluateResult.Status == "NA" && result.EvaluateResult.Reason == "" {
+		msg := fmt.Sprintf("autopilot '%s' provided status 'NA' without a 'reason'", result.Name)
+		result.EvaluateResult.Status = "ERROR"
+		result.EvaluateResult.Reason = msg
+		logger.UserError(msg)
+		return
+	}
 	// autopilot must provide a reason
 	var msgs []string
 	if result.EvaluateResult.Reason == "" {
 		msgs = append(msgs, fmt.Sprintf("autopilot '%s' did not provide a 'reason'", result.Name))
 	}
 	// autopilot with status RED, GREEN, YELLOW must provide results
-	if len(result.EvaluateResult.Results) == 0 {
+	notEvaluated := result.EvaluateResult.Status == "NA" || result.EvaluateResult.Status == "SKIPPED" || result.EvaluateResult.Status == "FAILED"
+	if len(result.EvaluateResult.Results) == 0 && !notEvaluated {
 		msgs = append(msgs, fmt.Sprintf("autopilot '%s' did not provide any 'results'", result.Name))
 	}
-	// autopilot must provide a criterion and justification for each result
+	// autopilot must provide a criterion and justification for each result and unique ids, as the ids identify the results
+	ids := make(map[string]bool)
 	for i, r := range result.EvaluateResult.Results {
+		if r.ID != "" {
+			if ids[r.ID] {
+				msgs = append(msgs, fmt.Sprintf("autopilot '%s' provided the 'id' '%s' in more than one result", result.Name, r.ID))
+			}
+			ids[r.ID] = true
+		}
 		if r.Criterion == "" {
 			msgs = append(msgs, fmt.Sprintf("autopilot '%s' did not provide a 'criterion' in result '%d'", result.Name, i))
 		}
@@ -92,7 +92,7 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 							},
 						}},
 						ExitCode: 0,
-						Logs:     []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}}},
+						Logs:     []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}, Line: `{"status": "GREEN", "reason": "file matches", "result": {"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": {"severity": "HIGH", "package": "package1"}}}`}},
 						Status:   "GREEN",
 						Reason:   "file matches",
 					},
@@ -116,8 +116,8 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 					EvaluateResult: model.EvaluateResult{
 						ExitCode: 0,
 						Logs: []model.LogEntry{
-							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}},
-							{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}},
+							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}, Line: `{"reason": "hello world"}`},
+							{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}, Line: `{"status": "GREEN"}`},
 						},
 						Reason: "autopilot 'autopilot' did not provide any 'results'",
 						Status: "ERROR",
@@ -126,6 +126,37 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 				}
 			},
 		},
+		"should return error if results have the same id": {
+			strict: true,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"reason\": \"hello world\", \"status\": \"RED\"}'; echo '{\"result\": {\"id\": \"CVE-1\", \"criterion\": \"c1\", \"fulfilled\": false, \"justification\": \"j1\"}}'; echo '{\"result\": {\"id\": \"CVE-1\", \"criterion\": \"c2\", \"fulfilled\": false, \"justification\": \"j2\"}}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world", "status": "RED"}, Line: `{"reason": "hello world", "status": "RED"}`},
+							{Source: "stdout", Json: map[string]interface{}{"result": map[string]interface{}{"id": "CVE-1", "criterion": "c1", "fulfilled": false, "justification": "j1"}}, Line: `{"result": {"id": "CVE-1", "criterion": "c1", "fulfilled": false, "justification": "j1"}}`},
+							{Source: "stdout", Json: map[string]interface{}{"result": map[string]interface{}{"id": "CVE-1", "criterion": "c2", "fulfilled": false, "justification": "j2"}}, Line: `{"result": {"id": "CVE-1", "criterion": "c2", "fulfilled": false, "justification": "j2"}}`},
+						},
+						Results: []model.Result{
+							{ID: "CVE-1", Criterion: "c1", Justification: "j1"},
+							{ID: "CVE-1", Criterion: "c2", Justification: "j2"},
+						},
+						Reason: "autopilot 'autopilot' provided the 'id' 'CVE-1' in more than one result",
+						Status: "ERROR",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
 		"should return some evaluate results if wrong data is passed": {
 			strict: true,
 			check: &model.AutopilotCheck{
@@ -142,7 +173,7 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 					EvaluateResult: model.EvaluateResult{
 						ExitCode: 0,
 						Logs: []model.LogEntry{
-							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world", "status": "GREEN", "result": map[string]interface{}{"justification": "justified", "fulfilled": true, "criterion": "c1", "metadata": map[string]interface{}{"key": "value"}}}},
+							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world", "status": "GREEN", "result": map[string]interface{}{"justification": "justified", "fulfilled": true, "criterion": "c1", "metadata": map[string]interface{}{"key": "value"}}}, Line: `{"reason": "hello world", "status": "GREEN","result": {"justification": "justified", "fulfilled": true, "criterion": "c1", "metadata": {"key": "value"}}}`},
 							{Source: "stdout", Text: "{\"result\": { \"metadata\": { key2: value }}}"},
 						},
 						Results: []model.Result{
@@ -176,8 +207,8 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 					EvaluateResult: model.EvaluateResult{
 						ExitCode: 0,
 						Logs: []model.LogEntry{
-							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}},
-							{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}},
+							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}, Line: `{"reason": "hello world"}`},
+							{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}, Line: `{"status": "GREEN"}`},
 						},
 						Reason: "hello world",
 						Status: "GREEN",
@@ -186,6 +217,106 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 				}
 			},
 		},
+		"should accept NA without results": {
+			strict: true,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"NA\", \"reason\": \"no frontend in this project\"}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "NA", "reason": "no frontend in this project"}, Line: `{"status": "NA", "reason": "no frontend in this project"}`},
+						},
+						Reason: "no frontend in this project",
+						Status: "NA",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
+		"should accept SKIPPED without results": {
+			strict: true,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"SKIPPED\", \"reason\": \"no release planned\"}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "SKIPPED", "reason": "no release planned"}, Line: `{"status": "SKIPPED", "reason": "no release planned"}`},
+						},
+						Reason: "no release planned",
+						Status: "SKIPPED",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
+		"should only require a reason if SKIPPED is provided without results": {
+			strict: true,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"SKIPPED\"}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "SKIPPED"}, Line: `{"status": "SKIPPED"}`},
+						},
+						Reason: "autopilot 'autopilot' did not provide a 'reason'",
+						Status: "ERROR",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
+		"should return error if NA is provided without a reason": {
+			strict: false,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"NA\"}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "NA"}, Line: `{"status": "NA"}`},
+						},
+						Reason: "autopilot 'autopilot' provided status 'NA' without a 'reason'",
+						Status: "ERROR",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
 		"should error on evaluate timeout": {
 			strict: false,
 			check: &model.AutopilotCheck{
@@ -233,8 +364,8 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 					EvaluateResult: model.EvaluateResult{
 						ExitCode: 0,
 						Logs: []model.LogEntry{
-							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}},
-							{Source: "stdout", Json: map[string]interface{}{"status": "RED"}},
+							{Source: "stdout", Json: map[string]interface{}{"reason": "hello world"}, Line: `{"reason": "hello world"}`},
+							{Source: "stdout", Json: map[string]interface{}{"status": "RED"}, Line: `{"status": "RED"}`},
 							{Source: "stdout", Text: "***TEST_SECRET***"},
 						},
 						Reason: "autopilot 'autopilot' did not provide any 'results'",
@@ -244,6 +375,57 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 				}
 			},
 		},
+		"should accept status FAILED of legacy checks": {
+			strict: true,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"FAILED\", \"reason\": \"hello world\"}'",
+					},
+				},
+				Legacy: true,
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "FAILED", "reason": "hello world"}, Line: `{"status": "FAILED", "reason": "hello world"}`},
+						},
+						Reason: "hello world",
+						Status: "FAILED",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
+		"should reject status FAILED of v2 checks": {
+			strict: false,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+					Evaluate: model.Evaluate{
+						Run: "echo '{\"status\": \"FAILED\", \"reason\": \"hello world\"}'",
+					},
+				},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						ExitCode: 0,
+						Logs: []model.LogEntry{
+							{Source: "stdout", Json: map[string]interface{}{"status": "FAILED", "reason": "hello world"}, Line: `{"status": "FAILED", "reason": "hello world"}`},
+						},
+						Reason: "autopilot 'autopilot' provided an invalid 'status': 'FAILED'",
+						Status: "ERROR",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
 		"should handle validation errors": {
 			strict: false,
 			check: &model.AutopilotCheck{
@@ -264,6 +446,26 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 				}
 			},
 		},
+		"should not execute not applicable checks": {
+			strict: false,
+			check: &model.AutopilotCheck{
+				Item: item,
+				Autopilot: model.Autopilot{
+					Name: "autopilot",
+				},
+				ValidationErrs: []error{errors.New("validation error")},
+				NotApplicable:  &model.ManualResult{Status: "NA", Reason: "chapter '1' is not applicable, condition 'vars.HAS_UI == 'true'' is not met"},
+			},
+			want: func(tmpDir string) *model.AutopilotResult {
+				return &model.AutopilotResult{
+					EvaluateResult: model.EvaluateResult{
+						Status: "NA",
+						Reason: "chapter '1' is not applicable, condition 'vars.HAS_UI == 'true'' is not met",
+					},
+					Name: "autopilot",
+				}
+			},
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -289,6 +491,34 @@ func TestAutopilotExecuteIntegration(t *testing.T) {
 	}
 }
 
+func TestAutopilotExecuteLegacyLinksFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, "input.txt"), []byte("input file"), 0444)
+	assert.NoError(t, err)
+	check := &model.AutopilotCheck{
+		Item: model.Item{
+			Chapter:     configuration.Chapter{Id: "chapter"},
+			Requirement: configuration.Requirement{Id: "requirement"},
+			Check:       configuration.Check{Id: "check"},
+		},
+		Autopilot: model.Autopilot{
+			Name: "autopilot",
+			Evaluate: model.Evaluate{
+				Run: "cat input.txt; echo; echo '{\"status\": \"FAILED\", \"reason\": \"hello world\"}'",
+			},
+		},
+		Legacy: true,
+	}
+
+	autopilotExecutor := NewAutopilotExecutor(workdir.NewUtils(afero.NewOsFs()), tmpDir, false, logger.NewAutopilot(), 10*time.Second)
+	actual, err := autopilotExecutor.ExecuteAutopilotCheck(check, map[string]string{}, map[string]string{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FAILED", actual.EvaluateResult.Status)
+	assert.Equal(t, model.LogEntry{Source: "stdout", Text: "input file"}, actual.EvaluateResult.Logs[0])
+	assert.NoFileExists(t, filepath.Join(tmpDir, "chapter_requirement_check", "evaluation", "input.txt"))
+}
+
 func TestAutopilotExecuteDirectoryStructure(t *testing.T) {
 	item :=
 		model.Item{
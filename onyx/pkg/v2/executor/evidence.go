@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+// checkEvidence resolves the evidence references of the results to files in the step outputs.
+// Invalid references are removed from the results, in strict mode they set the status to ERROR.
+func checkEvidence(result *model.AutopilotResult, rootWorkDir string, strict bool, logger *logger.Autopilot) {
+	var msgs []string
+	for i := range result.EvaluateResult.Results {
+		r := &result.EvaluateResult.Results[i]
+		var resolved []model.EvidenceReference
+		for _, reference := range r.Evidence {
+			ref, err := resolveEvidence(reference.Reference, result.StepResults, rootWorkDir)
+			if err != nil {
+				msgs = append(msgs, fmt.Sprintf("autopilot '%s' provided an invalid evidence reference '%s' in result '%d': %s", result.Name, reference.Reference, i, err.Error()))
+				continue
+			}
+			resolved = append(resolved, ref)
+		}
+		r.Evidence = resolved
+	}
+	if len(msgs) == 0 {
+		return
+	}
+	msg := strings.Join(msgs, "; ")
+	if strict && result.EvaluateResult.Status != "ERROR" {
+		result.EvaluateResult.Status = "ERROR"
+		result.EvaluateResult.Reason = msg
+		logger.UserError(msg)
+		return
+	}
+	logger.Warn(msg)
+}
+
+// resolveEvidence resolves a reference like 'files/report.json#/items/3' or '<step>/files/report.json'.
+// References starting with 'files/' are looked up in the outputs of all steps and must be unique.
+func resolveEvidence(reference string, steps []model.StepResult, rootWorkDir string) (model.EvidenceReference, error) {
+	refPath, pointer, _ := strings.Cut(reference, "#")
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return model.EvidenceReference{}, errors.Errorf("pointer '%s' must start with '/'", pointer)
+	}
+	cleaned := filepath.ToSlash(filepath.Clean(refPath))
+	if refPath == "" || filepath.IsAbs(refPath) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return model.EvidenceReference{}, errors.New("path must be relative to the step output")
+	}
+
+	stepID, filePath, _ := strings.Cut(cleaned, "/")
+	if stepID != "files" {
+		var found bool
+		filePath, found = strings.CutPrefix(filePath, "files/")
+		if !found {
+			return model.EvidenceReference{}, errors.New("path must start with 'files/' or '<step>/files/'")
+		}
+	}
+	var candidates []string
+	for _, step := range steps {
+		if stepID != "files" && step.ID != stepID {
+			continue
+		}
+		file, err := stepOutputFile(step.OutputDir, filePath)
+		if err != nil {
+			return model.EvidenceReference{}, err
+		}
+		if file != "" {
+			candidates = append(candidates, file)
+		}
+	}
+	if len(candidates) == 0 {
+		return model.EvidenceReference{}, errors.New("file does not exist in the step output")
+	}
+	if len(candidates) > 1 {
+		return model.EvidenceReference{}, errors.New("file exists in the output of several steps, prefix the path with the step id")
+	}
+
+	content, err := os.ReadFile(candidates[0])
+	if err != nil {
+		return model.EvidenceReference{}, errors.Wrap(err, "failed to read file")
+	}
+	if pointer != "" {
+		var doc interface{}
+		if err := json.Unmarshal(content, &doc); err != nil {
+			return model.EvidenceReference{}, errors.New("pointer can only be used for JSON files")
+		}
+		if err := resolvePointer(doc, pointer); err != nil {
+			return model.EvidenceReference{}, err
+		}
+	}
+	rel, err := filepath.Rel(rootWorkDir, candidates[0])
+	if err != nil {
+		return model.EvidenceReference{}, errors.Wrap(err, "failed to determine path in the evidence")
+	}
+	hash := sha256.Sum256(content)
+	return model.EvidenceReference{
+		Reference: reference,
+		Path:      filepath.ToSlash(rel),
+		Pointer:   pointer,
+		Size:      int64(len(content)),
+		SHA256:    hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// stepOutputFile returns the file in the output directory of a step or an empty string if it does not exist.
+// Files which are links to a location outside of the output directory are rejected.
+func stepOutputFile(outputDir, filePath string) (string, error) {
+	if outputDir == "" {
+		return "", nil
+	}
+	file := filepath.Join(outputDir, filepath.FromSlash(filePath))
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", nil
+	}
+	if !info.Mode().IsRegular() {
+		return "", errors.New("path is not a file")
+	}
+	realDir, err := filepath.EvalSymlinks(outputDir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve step output")
+	}
+	realFile, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve file")
+	}
+	if !strings.HasPrefix(realFile, realDir+string(filepath.Separator)) {
+		return "", errors.New("file is outside of the step output")
+	}
+	return file, nil
+}
+
+// resolvePointer checks that a JSON pointer (RFC 6901) points to a value in the document
+func resolvePointer(doc interface{}, pointer string) error {
+	current := doc
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[token]
+			if !ok {
+				return errors.Errorf("pointer '%s' does not exist in the file", pointer)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return errors.Errorf("pointer '%s' does not exist in the file", pointer)
+			}
+			current = value[index]
+		default:
+			return errors.Errorf("pointer '%s' does not exist in the file", pointer)
+		}
+	}
+	return nil
+}
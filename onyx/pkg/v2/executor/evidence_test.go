@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEvidence(t *testing.T) {
+	rootWorkDir := t.TempDir()
+	fetchDir := filepath.Join(rootWorkDir, "1_1_1", "steps", "fetch", "files")
+	scanDir := filepath.Join(rootWorkDir, "1_1_1", "steps", "scan", "files")
+	require.NoError(t, os.MkdirAll(filepath.Join(fetchDir, "screenshots"), 0755))
+	require.NoError(t, os.MkdirAll(scanDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(fetchDir, "report.json"), []byte(`{"items": [{"id": 1}, {"a/b": true}]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(fetchDir, "screenshots", "login.png"), []byte("png"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(fetchDir, "summary.txt"), []byte("fetch"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(scanDir, "summary.txt"), []byte("scan"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootWorkDir, "secret.txt"), []byte("secret"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(rootWorkDir, "secret.txt"), filepath.Join(scanDir, "link.txt")))
+	steps := []model.StepResult{{ID: "fetch", OutputDir: fetchDir}, {ID: "scan", OutputDir: scanDir}}
+
+	tests := map[string]struct {
+		reference string
+		want      model.EvidenceReference
+		wantErr   string
+	}{
+		"should resolve file in output of any step": {
+			reference: "files/screenshots/login.png",
+			want: model.EvidenceReference{
+				Reference: "files/screenshots/login.png",
+				Path:      "1_1_1/steps/fetch/files/screenshots/login.png",
+				Size:      3,
+				SHA256:    "8f8cbb7dcf46e0bc7d53265749a6c17d116093a6ba95e442764060c76fd4a86c",
+			},
+		},
+		"should resolve file with json pointer": {
+			reference: "files/report.json#/items/1/a~1b",
+			want: model.EvidenceReference{
+				Reference: "files/report.json#/items/1/a~1b",
+				Path:      "1_1_1/steps/fetch/files/report.json",
+				Pointer:   "/items/1/a~1b",
+				Size:      37,
+				SHA256:    "724f88447cb8744047e06efc672374836cfe9b9d4138b26525f65aa35fff7b13",
+			},
+		},
+		"should resolve file of a given step": {
+			reference: "scan/files/summary.txt",
+			want: model.EvidenceReference{
+				Reference: "scan/files/summary.txt",
+				Path:      "1_1_1/steps/scan/files/summary.txt",
+				Size:      4,
+				SHA256:    "59ad1b2fc74287ded1bba7af67765d23ad4a49f1ae51902cc2ed3f8ebee96cfa",
+			},
+		},
+		"should fail for ambiguous file": {
+			reference: "files/summary.txt",
+			wantErr:   "file exists in the output of several steps, prefix the path with the step id",
+		},
+		"should fail for missing file": {
+			reference: "files/missing.json",
+			wantErr:   "file does not exist in the step output",
+		},
+		"should fail for missing pointer": {
+			reference: "files/report.json#/items/5",
+			wantErr:   "pointer '/items/5' does not exist in the file",
+		},
+		"should fail for pointer into non json file": {
+			reference: "files/screenshots/login.png#/0",
+			wantErr:   "pointer can only be used for JSON files",
+		},
+		"should fail for path outside of the step output": {
+			reference: "../../secret.txt",
+			wantErr:   "path must be relative to the step output",
+		},
+		"should fail for link to a file outside of the step output": {
+			reference: "scan/files/link.txt",
+			wantErr:   "file is outside of the step output",
+		},
+		"should fail for directory": {
+			reference: "files/screenshots",
+			wantErr:   "path is not a file",
+		},
+		"should fail for path without files directory": {
+			reference: "fetch/report.json",
+			wantErr:   "path must start with 'files/' or '<step>/files/'",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveEvidence(tt.reference, steps, rootWorkDir)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckEvidence(t *testing.T) {
+	tests := map[string]struct {
+		strict     bool
+		wantStatus string
+		wantReason string
+	}{
+		"should drop invalid references": {
+			strict:     false,
+			wantStatus: "RED",
+			wantReason: "some reason",
+		},
+		"should set status to ERROR for invalid references in strict mode": {
+			strict:     true,
+			wantStatus: "ERROR",
+			wantReason: "autopilot 'scanner' provided an invalid evidence reference 'files/missing.json' in result '0': file does not exist in the step output",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootWorkDir := t.TempDir()
+			outputDir := filepath.Join(rootWorkDir, "1_1_1", "steps", "scan", "files")
+			require.NoError(t, os.MkdirAll(outputDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(outputDir, "report.json"), []byte(`{}`), 0644))
+			result := &model.AutopilotResult{
+				Name:        "scanner",
+				StepResults: []model.StepResult{{ID: "scan", OutputDir: outputDir}},
+				EvaluateResult: model.EvaluateResult{
+					Status: "RED",
+					Reason: "some reason",
+					Results: []model.Result{{
+						Criterion:     "criterion",
+						Justification: "justification",
+						Evidence:      []model.EvidenceReference{{Reference: "files/report.json"}, {Reference: "files/missing.json"}},
+					}},
+				},
+			}
+
+			checkEvidence(result, rootWorkDir, tt.strict, logger.NewAutopilot())
+
+			assert.Equal(t, tt.wantStatus, result.EvaluateResult.Status)
+			assert.Equal(t, tt.wantReason, result.EvaluateResult.Reason)
+			require.Len(t, result.EvaluateResult.Results[0].Evidence, 1)
+			assert.Equal(t, "1_1_1/steps/scan/files/report.json", result.EvaluateResult.Results[0].Evidence[0].Path)
+		})
+	}
+}
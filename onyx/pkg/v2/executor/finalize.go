@@ -5,6 +5,8 @@
 package executor
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -22,7 +24,7 @@ type FinalizeExecutor struct {
 	rootWorkDir string
 	logger      *logger.Autopilot
 	timeout     time.Duration
-	runner      *runner.Subprocess
+	runner      runner.Runner
 }
 
 func NewFinalizeExecutor(wdUtils workdir.Utilizer, rootWorkDir string, logger *logger.Autopilot, timeout time.Duration) *FinalizeExecutor {
@@ -35,14 +37,27 @@ func NewFinalizeExecutor(wdUtils workdir.Utilizer, rootWorkDir string, logger *l
 	}
 }
 
+// WithShell selects the shell which runs the scripts, see runner.New
+func (f *FinalizeExecutor) WithShell(shell string) *FinalizeExecutor {
+	f.runner = runner.New(shell, f.logger)
+	return f
+}
+
 func (f *FinalizeExecutor) Execute(item *model.Finalize, env, secrets map[string]string) (*model.FinalizeResult, error) {
 	err := overWriteConfigFiles(f.wdUtils, item.Configs, f.rootWorkDir)
 	if err != nil {
 		return nil, err
 	}
 	specialEnv := map[string]string{"result_path": f.rootWorkDir}
+	if item.AppPath != "" {
+		specialEnv["PATH"] = fmt.Sprintf("%s:%s", item.AppPath, os.Getenv("PATH"))
+	}
 	runtimeEnv := helper.MergeMaps(env, item.Env, specialEnv)
-	runnerOutput, err := StartRunner(f.rootWorkDir, item.Run, runtimeEnv, secrets, f.logger, f.runner, f.timeout)
+	timeout := f.timeout
+	if item.Timeout > 0 {
+		timeout = item.Timeout
+	}
+	runnerOutput, err := StartRunner(f.rootWorkDir, item.Run, runtimeEnv, secrets, f.logger, f.runner, timeout)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run finalize")
 	}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package executor
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+// secretFilesBaseDir is preferred for secret files as it is a tmpfs, so secrets are never written to disk
+var secretFilesBaseDir = "/dev/shm"
+
+// mergeSecretFiles returns the secret files of the autopilot, files of the step replace those with the same name
+func mergeSecretFiles(autopilotFiles, stepFiles []model.SecretFile) []model.SecretFile {
+	files := make([]model.SecretFile, 0, len(autopilotFiles)+len(stepFiles))
+	names := make(map[string]bool, len(stepFiles))
+	for _, file := range stepFiles {
+		names[file.Name] = true
+	}
+	for _, file := range autopilotFiles {
+		if !names[file.Name] {
+			files = append(files, file)
+		}
+	}
+	return append(files, stepFiles...)
+}
+
+// createSecretFiles writes the secret files to a private directory outside of the work directory,
+// so they never become part of the evidence.
+// It returns the environment variables with the paths of the files and a function removing them.
+func createSecretFiles(files []model.SecretFile, secrets map[string]string) (map[string]string, func(), error) {
+	if len(files) == 0 {
+		return nil, func() {}, nil
+	}
+	baseDir := secretFilesBaseDir
+	if info, err := os.Stat(baseDir); err != nil || !info.IsDir() {
+		baseDir = os.TempDir()
+	}
+	dir, err := os.MkdirTemp(baseDir, "onyx-secrets-*")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create directory for secret files")
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+	env := make(map[string]string, len(files))
+	for _, file := range files {
+		secret, ok := secrets[file.Secret]
+		if !ok {
+			cleanup()
+			return nil, nil, errors.Errorf("secret '%s' of secret file '%s' is not available", file.Secret, file.Name)
+		}
+		path := filepath.Join(dir, file.Name)
+		if err := os.WriteFile(path, []byte(secret), file.Mode); err != nil {
+			cleanup()
+			return nil, nil, errors.Wrapf(err, "failed to write secret file '%s'", file.Name)
+		}
+		// the mode of WriteFile is subject to the umask, e.g., a read-only mode must be set explicitly
+		if err := os.Chmod(path, file.Mode); err != nil {
+			cleanup()
+			return nil, nil, errors.Wrapf(err, "failed to set mode of secret file '%s'", file.Name)
+		}
+		env[model.SecretFileEnv(file.Name)] = path
+	}
+	return env, cleanup, nil
+}
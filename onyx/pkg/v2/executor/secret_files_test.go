@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/configuration"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/workdir"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTempSecretFilesBaseDir writes the secret files of a test to a temporary folder
+func useTempSecretFilesBaseDir(t *testing.T) {
+	original := secretFilesBaseDir
+	t.Cleanup(func() { secretFilesBaseDir = original })
+	secretFilesBaseDir = t.TempDir()
+}
+
+func TestMergeSecretFiles(t *testing.T) {
+	autopilotFiles := []model.SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG"}, {Name: "id_rsa", Secret: "SSH_KEY"}}
+	stepFiles := []model.SecretFile{{Name: "kubeconfig", Secret: "OTHER_KUBECONFIG"}}
+
+	files := mergeSecretFiles(autopilotFiles, stepFiles)
+
+	assert.Equal(t, []model.SecretFile{{Name: "id_rsa", Secret: "SSH_KEY"}, {Name: "kubeconfig", Secret: "OTHER_KUBECONFIG"}}, files)
+}
+
+func TestCreateSecretFiles(t *testing.T) {
+	testCases := map[string]struct {
+		files   []model.SecretFile
+		wantErr string
+	}{
+		"should write files with their mode": {
+			files: []model.SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG", Mode: 0600}, {Name: "key.json", Secret: "KEY", Mode: 0400}},
+		},
+		"should fail for missing secrets": {
+			files:   []model.SecretFile{{Name: "kubeconfig", Secret: "MISSING", Mode: 0600}},
+			wantErr: "secret 'MISSING' of secret file 'kubeconfig' is not available",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			useTempSecretFilesBaseDir(t)
+			secrets := map[string]string{"KUBECONFIG": "apiVersion: v1", "KEY": "{}"}
+
+			env, cleanup, err := createSecretFiles(tc.files, secrets)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				entries, _ := os.ReadDir(secretFilesBaseDir)
+				assert.Empty(t, entries)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, env, len(tc.files))
+			for _, file := range tc.files {
+				path := env[model.SecretFileEnv(file.Name)]
+				assert.Equal(t, secretFilesBaseDir, filepath.Dir(filepath.Dir(path)))
+				content, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, secrets[file.Secret], string(content))
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				assert.Equal(t, file.Mode, info.Mode().Perm())
+			}
+			cleanup()
+			entries, err := os.ReadDir(secretFilesBaseDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestAutopilotExecuteSecretFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	useTempSecretFilesBaseDir(t)
+	check := &model.AutopilotCheck{
+		Item: model.Item{
+			Chapter:     configuration.Chapter{Id: "chapter"},
+			Requirement: configuration.Requirement{Id: "requirement"},
+			Check:       configuration.Check{Id: "check"},
+		},
+		Autopilot: model.Autopilot{
+			Name:        "autopilot",
+			SecretFiles: []model.SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG", Mode: 0600}},
+			Steps: [][]model.Step{{{
+				ID:          "fetch",
+				SecretFiles: []model.SecretFile{{Name: "token", Secret: "TOKEN", Mode: 0400}},
+				Run:         "[ -f \"$SECRET_FILE_KUBECONFIG\" ] && [ -f \"$SECRET_FILE_TOKEN\" ] && echo \"$SECRET_FILE_TOKEN\" > $AUTOPILOT_OUTPUT_DIR/path.txt",
+			}}},
+			Evaluate: model.Evaluate{
+				Run: "[ -z \"$SECRET_FILE_TOKEN\" ] && grep -q v1 \"$SECRET_FILE_KUBECONFIG\" && echo '{\"status\": \"GREEN\", \"reason\": \"secret files found\", \"result\": {\"criterion\": \"c\", \"fulfilled\": true, \"justification\": \"j\"}}'",
+			},
+		},
+	}
+	secrets := map[string]string{"KUBECONFIG": "apiVersion: v1", "TOKEN": "token"}
+
+	autopilotExecutor := NewAutopilotExecutor(workdir.NewUtils(afero.NewOsFs()), tmpDir, false, logger.NewAutopilot(), 10*time.Second)
+	result, err := autopilotExecutor.ExecuteAutopilotCheck(check, map[string]string{}, secrets)
+
+	require.NoError(t, err)
+	assert.Equal(t, "GREEN", result.EvaluateResult.Status)
+	path, err := os.ReadFile(filepath.Join(result.StepResults[0].OutputDir, "path.txt"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(path), tmpDir)
+	entries, err := os.ReadDir(secretFilesBaseDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
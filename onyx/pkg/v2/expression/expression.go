@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package expression implements the small boolean expression language used in
+// conditions of the configuration (e.g. `if: status == 'RED'`).
+//
+// Supported syntax:
+//   - string literals in single or double quotes: 'RED', "GREEN"
+//   - boolean literals: true, false
+//   - lists: ['RED', 'YELLOW']
+//   - variable paths: status, chapters.1.status, chapters['my chapter'].status
+//   - comparison operators: ==, !=, in, not in
+//   - logical operators: &&, ||, ! and parentheses
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Expression struct {
+	raw  string
+	root node
+}
+
+// Parse parses the given expression and returns an error if its syntax is invalid.
+func Parse(expr string) (*Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid expression '%s'", expr)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid expression '%s'", expr)
+	}
+	if !p.done() {
+		return nil, errors.Errorf("invalid expression '%s': unexpected token '%s'", expr, p.peek().value)
+	}
+	return &Expression{raw: expr, root: root}, nil
+}
+
+// Evaluate parses and evaluates the given expression in one go.
+func Evaluate(expr string, vars map[string]interface{}) (bool, error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return false, err
+	}
+	return e.Evaluate(vars)
+}
+
+// Evaluate evaluates the expression with the given variables. The expression must
+// result in a boolean value.
+func (e *Expression) Evaluate(vars map[string]interface{}) (bool, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate expression '%s'", e.raw)
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, errors.Errorf("expression '%s' does not evaluate to a boolean but to '%v'", e.raw, value)
+	}
+	return result, nil
+}
+
+func (e *Expression) String() string {
+	return e.raw
+}
+
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(map[string]interface{}) (interface{}, error) {
+	return l.value, nil
+}
+
+type list struct {
+	items []node
+}
+
+func (l list) eval(vars map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, 0, len(l.items))
+	for _, item := range l.items {
+		v, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+type path struct {
+	segments []string
+}
+
+func (p path) eval(vars map[string]interface{}) (interface{}, error) {
+	var current interface{} = vars
+	for i, segment := range p.segments {
+		var ok bool
+		switch m := current.(type) {
+		case map[string]interface{}:
+			current, ok = m[segment]
+		case map[string]string:
+			current, ok = m[segment]
+		default:
+			return nil, errors.Errorf("'%s' is not an object", strings.Join(p.segments[:i], "."))
+		}
+		if !ok {
+			return nil, errors.Errorf("unknown variable '%s'", strings.Join(p.segments[:i+1], "."))
+		}
+	}
+	return current, nil
+}
+
+type not struct {
+	operand node
+}
+
+func (n not) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, errors.Errorf("operator '!' expects a boolean but got '%v'", v)
+	}
+	return !b, nil
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b binary) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := b.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch b.op {
+	case "&&", "||":
+		l, ok := left.(bool)
+		if !ok {
+			return nil, errors.Errorf("operator '%s' expects booleans but got '%v'", b.op, left)
+		}
+		// short circuit evaluation
+		if (b.op == "&&" && !l) || (b.op == "||" && l) {
+			return l, nil
+		}
+		right, err := b.right.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, errors.Errorf("operator '%s' expects booleans but got '%v'", b.op, right)
+		}
+		return r, nil
+	}
+
+	right, err := b.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch b.op {
+	case "==":
+		return equals(left, right), nil
+	case "!=":
+		return !equals(left, right), nil
+	case "in", "not in":
+		items, ok := right.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("operator '%s' expects a list but got '%v'", b.op, right)
+		}
+		found := false
+		for _, item := range items {
+			if equals(left, item) {
+				found = true
+				break
+			}
+		}
+		if b.op == "in" {
+			return found, nil
+		}
+		return !found, nil
+	}
+	return nil, errors.Errorf("unknown operator '%s'", b.op)
+}
+
+func equals(a, b interface{}) bool {
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]interface{}{
+		"status": "RED",
+		"chapters": map[string]interface{}{
+			"1":          map[string]interface{}{"status": "GREEN"},
+			"my chapter": map[string]interface{}{"status": "YELLOW"},
+		},
+		"env":     map[string]string{"STAGE": "prod"},
+		"enabled": true,
+	}
+	testCases := map[string]struct {
+		expr string
+		want bool
+	}{
+		"equal":                    {expr: "status == 'RED'", want: true},
+		"not equal":                {expr: `status != "RED"`, want: false},
+		"dotted path":              {expr: "chapters.1.status == 'GREEN'", want: true},
+		"quoted path":              {expr: "chapters['my chapter'].status == 'YELLOW'", want: true},
+		"string map":               {expr: "env.STAGE == 'prod'", want: true},
+		"in list":                  {expr: "status in ['RED', 'YELLOW']", want: true},
+		"not in list":              {expr: "status not in ['RED', 'YELLOW']", want: false},
+		"and":                      {expr: "status == 'RED' && chapters.1.status == 'GREEN'", want: true},
+		"or":                       {expr: "status == 'GREEN' || chapters.1.status == 'GREEN'", want: true},
+		"negation and parenthesis": {expr: "!(status == 'RED' || enabled)", want: false},
+		"boolean variable":         {expr: "enabled", want: true},
+		"short circuit":            {expr: "status == 'GREEN' && unknown == 'x'", want: false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Evaluate(tc.expr, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	vars := map[string]interface{}{"status": "RED"}
+	testCases := map[string]struct {
+		expr    string
+		wantErr string
+	}{
+		"syntax error":        {expr: "status ==", wantErr: "unexpected end of expression"},
+		"unterminated string": {expr: "status == 'RED", wantErr: "unterminated string"},
+		"trailing tokens":     {expr: "status == 'RED' 'GREEN'", wantErr: "unexpected token 'GREEN'"},
+		"unknown variable":    {expr: "overall == 'RED'", wantErr: "unknown variable 'overall'"},
+		"not a boolean":       {expr: "status", wantErr: "does not evaluate to a boolean"},
+		"invalid character":   {expr: "status = 'RED'", wantErr: "unexpected character '='"},
+		"trailing dot":        {expr: "status.", wantErr: "expected a name after '.' but reached end of expression"},
+		"trailing dot of map": {expr: "chapters. == 'RED'", wantErr: "expected a name after '.' but got '=='"},
+		"unterminated key":    {expr: "chapters[", wantErr: "expected a quoted key after '[' but reached end of expression"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Evaluate(tc.expr, vars)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
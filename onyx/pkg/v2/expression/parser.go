@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package expression
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	// tokenEOF is returned at the end of the expression, it is the zero value so no other kind is mistaken for it
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			var sb strings.Builder
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				sb.WriteRune(runes[end])
+			}
+			if end >= len(runes) {
+				return nil, errors.Errorf("unterminated string starting at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String()})
+			i = end + 1
+		case strings.ContainsRune("()[].,", r):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(r)})
+			i++
+		case r == '=' || r == '!' || r == '&' || r == '|':
+			if i+1 < len(runes) {
+				op := string(runes[i : i+2])
+				if op == "==" || op == "!=" || op == "&&" || op == "||" {
+					tokens = append(tokens, token{kind: tokenOperator, value: op})
+					i += 2
+					continue
+				}
+			}
+			if r == '!' {
+				tokens = append(tokens, token{kind: tokenOperator, value: "!"})
+				i++
+				continue
+			}
+			return nil, errors.Errorf("unexpected character '%c' at position %d", r, i)
+		case isIdentRune(r):
+			end := i
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[i:end])})
+			i = end
+		default:
+			return nil, errors.Errorf("unexpected character '%c' at position %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) accept(kind tokenKind, value string) bool {
+	if !p.done() && p.peek().kind == kind && p.peek().value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.accept(kind, value) {
+		if p.done() {
+			return errors.Errorf("expected '%s' but reached end of expression", value)
+		}
+		return errors.Errorf("expected '%s' but got '%s'", value, p.peek().value)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept(tokenOperator, "!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	var op string
+	switch {
+	case p.accept(tokenOperator, "=="):
+		op = "=="
+	case p.accept(tokenOperator, "!="):
+		op = "!="
+	case p.accept(tokenIdent, "in"):
+		op = "in"
+	case p.peek().kind == tokenIdent && p.peek().value == "not":
+		p.next()
+		if err := p.expect(tokenIdent, "in"); err != nil {
+			return nil, err
+		}
+		op = "not in"
+	default:
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return binary{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literal{value: t.value}, nil
+	case tokenPunct:
+		switch t.value {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(tokenPunct, ")")
+		case "[":
+			var items []node
+			if p.accept(tokenPunct, "]") {
+				return list{}, nil
+			}
+			for {
+				item, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+				if p.accept(tokenPunct, "]") {
+					return list{items: items}, nil
+				}
+				if err := p.expect(tokenPunct, ","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		}
+		return p.parsePath(t.value)
+	}
+	return nil, errors.Errorf("unexpected token '%s'", t.value)
+}
+
+func (p *parser) parsePath(first string) (node, error) {
+	segments := []string{first}
+	for {
+		switch {
+		case p.accept(tokenPunct, "."):
+			t := p.next()
+			if t.kind == tokenEOF {
+				return nil, errors.New("expected a name after '.' but reached end of expression")
+			}
+			if t.kind != tokenIdent {
+				return nil, errors.Errorf("expected a name after '.' but got '%s'", t.value)
+			}
+			segments = append(segments, t.value)
+		case p.accept(tokenPunct, "["):
+			t := p.next()
+			if t.kind == tokenEOF {
+				return nil, errors.New("expected a quoted key after '[' but reached end of expression")
+			}
+			if t.kind != tokenString {
+				return nil, errors.Errorf("expected a quoted key after '[' but got '%s'", t.value)
+			}
+			segments = append(segments, t.value)
+			if err := p.expect(tokenPunct, "]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path{segments: segments}, nil
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package history implements a simple file based store of previous runs.
+// Every project gets its own JSON lines file in the history directory and
+// every run appends one entry to it.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/pkg/errors"
+)
+
+const fileSuffix = ".history.jsonl"
+
+// Entry contains the summary of a single run
+type Entry struct {
+	Project       string            `json:"project"`
+	Version       string            `json:"version"`
+	Date          string            `json:"date"`
+	OverallStatus string            `json:"overallStatus"`
+	Chapters      map[string]string `json:"chapters,omitempty"`
+	// Statuses of the checks, the key is '<chapter>_<requirement>_<check>'
+	Checks map[string]string `json:"checks,omitempty"`
+	// Durations of the automated checks in seconds, the key is the same as for the checks
+	Durations map[string]float64 `json:"durations,omitempty"`
+}
+
+type Store struct {
+	dir string
+}
+
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewEntry creates a history entry from a result
+func NewEntry(res *result.Result) Entry {
+	entry := Entry{
+		Project:       res.Header.Name,
+		Version:       res.Header.Version,
+		Date:          res.Header.Date,
+		OverallStatus: res.OverallStatus,
+		Chapters:      make(map[string]string),
+		Checks:        make(map[string]string),
+		Durations:     make(map[string]float64),
+	}
+	for chapID, chap := range res.Chapters {
+		entry.Chapters[chapID] = chap.Status
+		for reqID, req := range chap.Requirements {
+			for checkID, check := range req.Checks {
+				entry.Checks[CheckKey(chapID, reqID, checkID)] = check.Evaluation.Status
+				if check.Duration > 0 {
+					entry.Durations[CheckKey(chapID, reqID, checkID)] = check.Duration
+				}
+			}
+		}
+	}
+	return entry
+}
+
+// CheckKey returns the key of a check in the history entry
+func CheckKey(chapter, requirement, check string) string {
+	return strings.Join([]string{chapter, requirement, check}, "_")
+}
+
+// Append adds the entry to the history of its project
+func (s *Store) Append(entry Entry) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create history directory '%s'", s.dir)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal history entry")
+	}
+	file, err := os.OpenFile(s.path(entry.Project), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to open history file")
+	}
+	defer file.Close()
+	if _, err := fmt.Fprintln(file, string(line)); err != nil {
+		return errors.Wrap(err, "failed to write history entry")
+	}
+	return nil
+}
+
+// Entries returns all history entries of a project, oldest first
+func (s *Store) Entries(project string) ([]Entry, error) {
+	file, err := os.Open(s.path(project))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to open history file")
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse history file of project '%s'", project)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read history file")
+	}
+	return entries, nil
+}
+
+// Latest returns the latest history entry of a project or nil if there is none
+func (s *Store) Latest(project string) (*Entry, error) {
+	entries, err := s.Entries(project)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[len(entries)-1], nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *Store) path(project string) string {
+	name := unsafeFileChars.ReplaceAllString(project, "_")
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(s.dir, name+fileSuffix)
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := New(filepath.Join(dir, "history"))
+
+	latest, err := store.Latest("My Project")
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	first := Entry{Project: "My Project", Date: "2024-01-01T10:00:00Z", OverallStatus: "RED"}
+	second := Entry{Project: "My Project", Date: "2024-01-02T10:00:00Z", OverallStatus: "GREEN"}
+	other := Entry{Project: "Other", Date: "2024-01-03T10:00:00Z", OverallStatus: "YELLOW"}
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+	require.NoError(t, store.Append(other))
+
+	entries, err := store.Entries("My Project")
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{first, second}, entries)
+
+	latest, err = store.Latest("My Project")
+	require.NoError(t, err)
+	assert.Equal(t, &second, latest)
+
+	assert.FileExists(t, filepath.Join(dir, "history", "My_Project.history.jsonl"))
+}
+
+func TestStore_CorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "p.history.jsonl"), []byte("not json\n"), 0644))
+
+	_, err := New(dir).Latest("p")
+	assert.ErrorContains(t, err, "failed to parse history file of project 'p'")
+}
+
+func TestNewEntry(t *testing.T) {
+	res := &result.Result{
+		Header:        result.Header{Name: "project", Version: "1.0", Date: "2024-01-01T10:00:00Z"},
+		OverallStatus: "RED",
+		Chapters: map[string]*result.Chapter{
+			"1": {Status: "RED", Requirements: map[string]*result.Requirement{
+				"1": {Status: "RED", Checks: map[string]*result.Check{
+					"1": {Evaluation: result.Evaluation{Status: "RED"}, Duration: 12.5},
+					"2": {Evaluation: result.Evaluation{Status: "GREEN"}},
+				}},
+			}},
+		},
+	}
+
+	entry := NewEntry(res)
+
+	assert.Equal(t, Entry{
+		Project:       "project",
+		Version:       "1.0",
+		Date:          "2024-01-01T10:00:00Z",
+		OverallStatus: "RED",
+		Chapters:      map[string]string{"1": "RED"},
+		Checks:        map[string]string{"1_1_1": "RED", "1_1_2": "GREEN"},
+		Durations:     map[string]float64{"1_1_1": 12.5},
+	}, entry)
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package httprecorder
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const bodyEncodingBase64 = "base64"
+
+// sharedCassette records the requests which can not be assigned to a check
+const sharedCassette = "shared"
+
+var nonFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Cassette contains the recorded HTTP interactions of a check in the order they happened
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions" json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `yaml:"request" json:"request"`
+	Response Response `yaml:"response" json:"response"`
+}
+
+type Request struct {
+	Method  string              `yaml:"method" json:"method"`
+	URL     string              `yaml:"url" json:"url"`
+	Headers map[string][]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty" json:"body,omitempty"`
+	// BodyEncoding is 'base64' if the body is not valid UTF-8
+	BodyEncoding string `yaml:"bodyEncoding,omitempty" json:"bodyEncoding,omitempty"`
+}
+
+type Response struct {
+	Status  int                 `yaml:"status" json:"status"`
+	Headers map[string][]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty" json:"body,omitempty"`
+	// BodyEncoding is 'base64' if the body is not valid UTF-8
+	BodyEncoding string `yaml:"bodyEncoding,omitempty" json:"bodyEncoding,omitempty"`
+}
+
+// CassetteFile returns the file of the cassette of a check in the cassette directory
+func CassetteFile(cassetteDir, check string) string {
+	if check == "" {
+		check = sharedCassette
+	}
+	return filepath.Join(cassetteDir, nonFileNameChars.ReplaceAllString(check, "_")+".yaml")
+}
+
+// ReadCassette reads a cassette, a cassette which does not exist is empty
+func ReadCassette(file string) (*Cassette, error) {
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Cassette{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read cassette '%s'", file)
+	}
+	var cassette Cassette
+	if err := yaml.Unmarshal(content, &cassette); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse cassette '%s'", file)
+	}
+	return &cassette, nil
+}
+
+// WriteCassette writes a cassette, an existing cassette is replaced
+func WriteCassette(file string, cassette *Cassette) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create cassette directory '%s'", filepath.Dir(file))
+	}
+	content, err := yaml.Marshal(cassette)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal cassette '%s'", file)
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write cassette '%s'", file)
+	}
+	return nil
+}
+
+// encodeBody returns the body as string and 'base64' as encoding if it is not valid UTF-8
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), bodyEncodingBase64
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == bodyEncodingBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode body")
+		}
+		return decoded, nil
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,459 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package httprecorder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// CAFileEnvVar points to the certificate of the ephemeral certificate authority of the recorder
+const CAFileEnvVar = "HTTP_RECORDER_CA_FILE"
+
+// systemBundles are the usual locations of the trusted certificates, the first existing one is combined with the certificate of the recorder
+var systemBundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// hopHeaders only apply to a single connection and are not forwarded or recorded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// sensitiveHeaders are forwarded but not recorded
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+}
+
+// sensitiveResponseHeaders are returned to the checks but not recorded, so they are missing in replayed responses
+var sensitiveResponseHeaders = []string{
+	"Set-Cookie",
+}
+
+// Recorder is a local HTTP(S) proxy which records the requests of the checks and their responses into a cassette per check,
+// or replays the recorded responses without contacting the original servers.
+// HTTPS connections are intercepted with certificates of an ephemeral certificate authority, which the checks have to trust.
+type Recorder struct {
+	mode        string
+	cassetteDir string
+	secrets     map[string]string
+	logger      logger.Logger
+	authority   *authority
+	transport   *http.Transport
+	listener    net.Listener
+	server      *http.Server
+	certDir     string
+
+	mu        sync.Mutex
+	cassettes map[string]*Cassette
+	// replayed marks the interactions of the cassettes which were already replayed
+	replayed map[string][]bool
+	tunnels  map[net.Conn]struct{}
+	misses   int
+}
+
+func New(mode, cassetteDir string, secrets map[string]string, logger logger.Logger) (*Recorder, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, errors.Errorf("invalid mode '%s', either '%s' or '%s' is supported", mode, ModeRecord, ModeReplay)
+	}
+	authority, err := newAuthority()
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		mode:        mode,
+		cassetteDir: cassetteDir,
+		secrets:     helper.MergeMaps(secrets),
+		logger:      logger,
+		authority:   authority,
+		transport:   http.DefaultTransport.(*http.Transport).Clone(),
+		cassettes:   make(map[string]*Cassette),
+		replayed:    make(map[string][]bool),
+		tunnels:     make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start starts the proxy on a random port of localhost
+func (r *Recorder) Start() error {
+	certDir, err := os.MkdirTemp("", "onyx-http-recorder-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create directory for the certificate authority")
+	}
+	r.certDir = certDir
+	bundle := append([]byte{}, r.authority.pem...)
+	if system := systemBundle(); system != nil {
+		bundle = append(bundle, system...)
+	}
+	if err := os.WriteFile(r.caFile(), r.authority.pem, 0644); err != nil {
+		return errors.Wrap(err, "failed to write certificate of the certificate authority")
+	}
+	if err := os.WriteFile(r.bundleFile(), bundle, 0644); err != nil {
+		return errors.Wrap(err, "failed to write certificate bundle")
+	}
+
+	r.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "failed to start http recorder")
+	}
+	r.server = &http.Server{Handler: r, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := r.server.Serve(r.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Errorf("http recorder stopped: %s", err.Error())
+		}
+	}()
+	r.logger.Infof("http recorder is %sing on %s", r.mode, r.listener.Addr().String())
+	return nil
+}
+
+// Env returns the environment variables which route the HTTP traffic of a check through the proxy.
+// The check is identified by the user name in the proxy URL.
+func (r *Recorder) Env(check string) map[string]string {
+	proxyURL := &url.URL{Scheme: "http", Host: r.listener.Addr().String()}
+	if check != "" {
+		proxyURL.User = url.User(check)
+	}
+	proxy := proxyURL.String()
+	return map[string]string{
+		"HTTP_PROXY":          proxy,
+		"HTTPS_PROXY":         proxy,
+		"http_proxy":          proxy,
+		"https_proxy":         proxy,
+		"NO_PROXY":            "localhost,127.0.0.1",
+		"no_proxy":            "localhost,127.0.0.1",
+		"SSL_CERT_FILE":       r.bundleFile(),
+		"REQUESTS_CA_BUNDLE":  r.bundleFile(),
+		"CURL_CA_BUNDLE":      r.bundleFile(),
+		"NODE_EXTRA_CA_CERTS": r.caFile(),
+		CAFileEnvVar:          r.caFile(),
+	}
+}
+
+// Stop stops the proxy, in record mode the cassettes of all checks which sent requests are written
+func (r *Recorder) Stop() error {
+	if r.server != nil {
+		_ = r.server.Close()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for conn := range r.tunnels {
+		conn.Close()
+	}
+	if r.certDir != "" {
+		os.RemoveAll(r.certDir)
+	}
+
+	if r.mode == ModeReplay {
+		if r.misses > 0 {
+			r.logger.Warnf("%d HTTP requests could not be replayed from the cassettes in '%s'", r.misses, r.cassetteDir)
+		}
+		return nil
+	}
+	checks := make([]string, 0, len(r.cassettes))
+	for check := range r.cassettes {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	interactions := 0
+	for _, check := range checks {
+		if err := WriteCassette(CassetteFile(r.cassetteDir, check), r.cassettes[check]); err != nil {
+			return err
+		}
+		interactions += len(r.cassettes[check].Interactions)
+	}
+	r.logger.Infof("recorded %d HTTP interactions of %d checks in '%s'", interactions, len(checks), r.cassetteDir)
+	return nil
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	check := proxyUser(req.Header.Get("Proxy-Authorization"))
+	if req.Method == http.MethodConnect {
+		r.tunnel(w, req, check)
+		return
+	}
+	if !req.URL.IsAbs() {
+		http.Error(w, "onyx http-recorder only accepts proxy requests", http.StatusBadRequest)
+		return
+	}
+	status, header, body := r.exchange(check, req.URL.String(), req)
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// tunnel intercepts a HTTPS connection and handles the requests sent through it
+func (r *Recorder) tunnel(w http.ResponseWriter, req *http.Request, check string) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection can not be intercepted", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		r.logger.Warnf("failed to intercept connection to '%s': %s", req.Host, err.Error())
+		return
+	}
+	r.mu.Lock()
+	r.tunnels[conn] = struct{}{}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.tunnels, conn)
+		r.mu.Unlock()
+		conn.Close()
+	}()
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+
+	host, port, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host, port = req.Host, "443"
+	}
+	origin := "https://" + host
+	if port != "443" {
+		origin = "https://" + net.JoinHostPort(host, port)
+	}
+	tlsConn := tls.Server(conn, &tls.Config{
+		NextProtos: []string{"http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName != "" {
+				return r.authority.certificate(hello.ServerName)
+			}
+			return r.authority.certificate(host)
+		},
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		r.logger.Warnf("TLS handshake for '%s' failed, the certificate in %s must be trusted: %s", req.Host, CAFileEnvVar, err.Error())
+		return
+	}
+	reader := bufio.NewReader(tlsConn)
+	for {
+		inner, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+		status, header, body := r.exchange(check, origin+inner.URL.RequestURI(), inner)
+		header.Del("Content-Length")
+		response := &http.Response{
+			StatusCode:    status,
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       inner,
+			Close:         inner.Close,
+		}
+		if err := response.Write(tlsConn); err != nil || inner.Close {
+			return
+		}
+	}
+}
+
+// exchange records the request to target and its response or replays a recorded response
+func (r *Recorder) exchange(check, target string, req *http.Request) (int, http.Header, []byte) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return failure(http.StatusBadRequest, "failed to read request body: %s", err.Error())
+	}
+	request := Request{
+		Method:  req.Method,
+		URL:     helper.HideSecretsInString(target, r.secrets),
+		Headers: r.recordedHeaders(req.Header, sensitiveHeaders...),
+	}
+	request.Body, request.BodyEncoding = r.recordedBody(body)
+	r.logger.Debugf("http recorder: %s %s of check '%s'", request.Method, request.URL, check)
+	if r.mode == ModeReplay {
+		return r.replay(check, request)
+	}
+	return r.record(check, target, req, body, request)
+}
+
+func (r *Recorder) record(check, target string, req *http.Request, body []byte, request Request) (int, http.Header, []byte) {
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return failure(http.StatusBadRequest, "invalid request: %s", err.Error())
+	}
+	out.Header = withoutHopHeaders(req.Header)
+	// responses are recorded uncompressed, so that the cassettes can be read and edited
+	out.Header.Del("Accept-Encoding")
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		r.logger.Warnf("http recorder: %s %s of check '%s' failed: %s", request.Method, request.URL, check, err.Error())
+		return failure(http.StatusBadGateway, "request failed: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return failure(http.StatusBadGateway, "failed to read response: %s", err.Error())
+	}
+	header := withoutHopHeaders(resp.Header)
+	header.Del("Content-Length")
+
+	response := Response{Status: resp.StatusCode, Headers: r.recordedHeaders(header, sensitiveResponseHeaders...)}
+	response.Body, response.BodyEncoding = r.recordedBody(respBody)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cassettes[check] == nil {
+		r.cassettes[check] = &Cassette{}
+	}
+	r.cassettes[check].Interactions = append(r.cassettes[check].Interactions, Interaction{Request: request, Response: response})
+	return resp.StatusCode, header, respBody
+}
+
+// replay returns the response of the first recorded interaction of the check which matches the request and was not replayed yet
+func (r *Recorder) replay(check string, request Request) (int, http.Header, []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cassette, ok := r.cassettes[check]
+	if !ok {
+		var err error
+		cassette, err = ReadCassette(CassetteFile(r.cassetteDir, check))
+		if err != nil {
+			r.logger.Warnf("http recorder: %s", err.Error())
+			cassette = &Cassette{}
+		}
+		r.cassettes[check] = cassette
+		r.replayed[check] = make([]bool, len(cassette.Interactions))
+	}
+	for i, interaction := range cassette.Interactions {
+		if r.replayed[check][i] || !matches(interaction.Request, request) {
+			continue
+		}
+		body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			continue
+		}
+		r.replayed[check][i] = true
+		return interaction.Response.Status, http.Header(interaction.Response.Headers).Clone(), body
+	}
+	r.misses++
+	r.logger.Warnf("http recorder: no recorded interaction of check '%s' matches %s %s", check, request.Method, request.URL)
+	return failure(http.StatusBadGateway, "onyx http-recorder: no recorded interaction matches %s %s", request.Method, request.URL)
+}
+
+// matches compares the method, URL and body of two requests, headers are not compared
+func matches(recorded, request Request) bool {
+	return recorded.Method == request.Method &&
+		recorded.URL == request.URL &&
+		recorded.Body == request.Body &&
+		recorded.BodyEncoding == request.BodyEncoding
+}
+
+// recordedHeaders returns the headers without the hop-by-hop and the omitted headers, secrets are masked
+func (r *Recorder) recordedHeaders(header http.Header, omit ...string) map[string][]string {
+	recorded := withoutHopHeaders(header)
+	for _, key := range omit {
+		recorded.Del(key)
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	for _, values := range recorded {
+		for i, value := range values {
+			values[i] = helper.HideSecretsInString(value, r.secrets)
+		}
+	}
+	return recorded
+}
+
+// recordedBody encodes the body for the cassette, secrets are masked in text bodies
+func (r *Recorder) recordedBody(body []byte) (string, string) {
+	encoded, encoding := encodeBody(body)
+	if encoding == "" {
+		encoded = helper.HideSecretsInString(encoded, r.secrets)
+	}
+	return encoded, encoding
+}
+
+func (r *Recorder) caFile() string {
+	return filepath.Join(r.certDir, "ca.pem")
+}
+
+func (r *Recorder) bundleFile() string {
+	return filepath.Join(r.certDir, "bundle.pem")
+}
+
+func withoutHopHeaders(header http.Header) http.Header {
+	result := header.Clone()
+	if result == nil {
+		result = http.Header{}
+	}
+	for _, key := range hopHeaders {
+		result.Del(key)
+	}
+	return result
+}
+
+// proxyUser returns the user name of basic proxy authorization, it identifies the check which sent the request
+func proxyUser(authorization string) string {
+	encoded, found := strings.CutPrefix(authorization, "Basic ")
+	if !found {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	user, _, _ := strings.Cut(string(decoded), ":")
+	return user
+}
+
+// systemBundle returns the trusted certificates of the system, the bundle configured for onyx itself takes precedence
+func systemBundle() []byte {
+	files := systemBundles
+	if file := os.Getenv("SSL_CERT_FILE"); file != "" {
+		files = append([]string{file}, files...)
+	}
+	for _, file := range files {
+		if content, err := os.ReadFile(file); err == nil {
+			return content
+		}
+	}
+	return nil
+}
+
+func failure(status int, format string, args ...interface{}) (int, http.Header, []byte) {
+	header := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+	return status, header, []byte(fmt.Sprintf(format, args...) + "\n")
+}
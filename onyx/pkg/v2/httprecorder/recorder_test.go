@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package httprecorder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUpstream(t *testing.T, tls bool) (*httptest.Server, *int) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3ss10n"})
+		fmt.Fprintf(w, `{"call": %d, "path": "%s", "body": "%s", "auth": "%s"}`, calls, req.URL.Path, body, req.Header.Get("Authorization"))
+	})
+	var server *httptest.Server
+	if tls {
+		server = httptest.NewTLSServer(handler)
+	} else {
+		server = httptest.NewServer(handler)
+	}
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func startRecorder(t *testing.T, mode, cassetteDir string, upstream *httptest.Server) *Recorder {
+	recorder, err := New(mode, cassetteDir, map[string]string{"TOKEN": "s3cr3t"}, logger.Get())
+	require.NoError(t, err)
+	recorder.transport.TLSClientConfig = upstream.Client().Transport.(*http.Transport).TLSClientConfig
+	require.NoError(t, recorder.Start())
+	return recorder
+}
+
+// client sends requests through the recorder in the same way as a check configured with the environment of the recorder
+func client(t *testing.T, env map[string]string) *http.Client {
+	proxyURL, err := url.Parse(env["HTTPS_PROXY"])
+	require.NoError(t, err)
+	ca, err := os.ReadFile(env[CAFileEnvVar])
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca))
+	return &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: pool},
+	}}
+}
+
+func send(t *testing.T, client *http.Client, method, target, body string) (int, string) {
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(content)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	for name, useTLS := range map[string]bool{"http": false, "https": true} {
+		t.Run(name, func(t *testing.T) {
+			cassetteDir := t.TempDir()
+			upstream, calls := newUpstream(t, useTLS)
+
+			recorder := startRecorder(t, ModeRecord, cassetteDir, upstream)
+			c := client(t, recorder.Env("1_1_check"))
+			status, first := send(t, c, http.MethodGet, upstream.URL+"/items", "")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, `{"call": 1, "path": "/items", "body": "", "auth": "Bearer s3cr3t"}`, first)
+			_, second := send(t, c, http.MethodGet, upstream.URL+"/items", "")
+			_, posted := send(t, c, http.MethodPost, upstream.URL+"/items", "new")
+			require.NoError(t, recorder.Stop())
+			assert.Equal(t, 3, *calls)
+
+			cassette, err := ReadCassette(filepath.Join(cassetteDir, "1_1_check.yaml"))
+			require.NoError(t, err)
+			require.Len(t, cassette.Interactions, 3)
+			assert.Equal(t, Request{Method: http.MethodPost, URL: upstream.URL + "/items", Headers: cassette.Interactions[2].Request.Headers, Body: "new"}, cassette.Interactions[2].Request)
+			assert.NotContains(t, cassette.Interactions[0].Request.Headers, "Authorization")
+			assert.NotContains(t, cassette.Interactions[0].Response.Headers, "Set-Cookie")
+			assert.Equal(t, []string{"application/json"}, cassette.Interactions[0].Response.Headers["Content-Type"])
+			content, err := os.ReadFile(filepath.Join(cassetteDir, "1_1_check.yaml"))
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "s3ss10n")
+			assert.Equal(t, `{"call": 1, "path": "/items", "body": "", "auth": "Bearer ***TOKEN***"}`, cassette.Interactions[0].Response.Body)
+
+			recorder = startRecorder(t, ModeReplay, cassetteDir, upstream)
+			defer recorder.Stop()
+			c = client(t, recorder.Env("1_1_check"))
+			_, replayedFirst := send(t, c, http.MethodGet, upstream.URL+"/items", "")
+			_, replayedSecond := send(t, c, http.MethodGet, upstream.URL+"/items", "")
+			_, replayedPost := send(t, c, http.MethodPost, upstream.URL+"/items", "new")
+			assert.Equal(t, 3, *calls)
+			assert.Equal(t, strings.ReplaceAll(first, "s3cr3t", "***TOKEN***"), replayedFirst)
+			assert.Equal(t, strings.ReplaceAll(second, "s3cr3t", "***TOKEN***"), replayedSecond)
+			assert.Equal(t, strings.ReplaceAll(posted, "s3cr3t", "***TOKEN***"), replayedPost)
+
+			status, missing := send(t, c, http.MethodGet, upstream.URL+"/items", "")
+			assert.Equal(t, http.StatusBadGateway, status)
+			assert.Equal(t, fmt.Sprintf("onyx http-recorder: no recorded interaction matches GET %s/items\n", upstream.URL), missing)
+			assert.Equal(t, 3, *calls)
+		})
+	}
+}
+
+func TestReplayUsesCassetteOfCheck(t *testing.T) {
+	cassetteDir := t.TempDir()
+	upstream, _ := newUpstream(t, false)
+	require.NoError(t, WriteCassette(CassetteFile(cassetteDir, "1_1_a"), &Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: http.MethodGet, URL: upstream.URL + "/status"},
+		Response: Response{Status: http.StatusTeapot, Body: "check a"},
+	}}}))
+
+	recorder := startRecorder(t, ModeReplay, cassetteDir, upstream)
+	defer recorder.Stop()
+
+	status, body := send(t, client(t, recorder.Env("1_1_a")), http.MethodGet, upstream.URL+"/status", "")
+	assert.Equal(t, http.StatusTeapot, status)
+	assert.Equal(t, "check a", body)
+	status, _ = send(t, client(t, recorder.Env("1_1_b")), http.MethodGet, upstream.URL+"/status", "")
+	assert.Equal(t, http.StatusBadGateway, status)
+}
+
+func TestNewFailsForInvalidMode(t *testing.T) {
+	_, err := New("rewind", t.TempDir(), nil, logger.Get())
+	assert.EqualError(t, err, "invalid mode 'rewind', either 'record' or 'replay' is supported")
+}
+
+func TestCassetteFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("cassettes", "1_1_check.yaml"), CassetteFile("cassettes", "1_1_check"))
+	assert.Equal(t, filepath.Join("cassettes", "a_b_c_d.yaml"), CassetteFile("cassettes", "a/b c:d"))
+	assert.Equal(t, filepath.Join("cassettes", "shared.yaml"), CassetteFile("cassettes", ""))
+}
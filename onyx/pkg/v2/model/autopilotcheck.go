@@ -5,6 +5,8 @@
 package model
 
 import (
+	"time"
+
 	conf "github.com/B-S-F/yaku/onyx/pkg/configuration"
 )
 
@@ -15,6 +17,12 @@ type AutopilotCheck struct {
 	AppReferences  []*conf.AppReference
 	ValidationErrs []error
 	AppPath        string
+	// ExpectedDuration is the configured duration of the check, zero if not configured
+	ExpectedDuration time.Duration
+	// NotApplicable contains the status and reason of the check if it is not applicable, the autopilot is not executed then
+	NotApplicable *ManualResult
+	// Legacy is set for checks of v0 and v1 configs, their autopilots are run like on the legacy engine
+	Legacy bool
 }
 
 type StepResult struct {
@@ -24,6 +32,18 @@ type StepResult struct {
 	Logs       []LogEntry
 	ExitCode   int
 	InputDirs  []string
+	// Destinations the step connected to through the egress proxy
+	Destinations []Destination
+}
+
+// Destination is a host a step connected to
+type Destination struct {
+	Host string
+	Port int
+	// Requests is the number of requests and tunnels to the destination
+	Requests int
+	// Denied is set if the destination is not allowed by the network settings of the autopilot
+	Denied bool
 }
 type EvaluateResult struct {
 	Logs     []LogEntry
@@ -34,20 +54,37 @@ type EvaluateResult struct {
 }
 
 type Result struct {
+	// ID is the stable identifier of the result provided by the autopilot, empty if not provided
+	ID            string
 	Criterion     string
 	Fulfilled     bool
 	Justification string
 	Metadata      map[string]string
+	Evidence      []EvidenceReference
+}
+
+// EvidenceReference points to a file in the step output which proves a result
+type EvidenceReference struct {
+	// Reference as provided by the autopilot, e.g. "files/report.json#/items/3"
+	Reference string
+	// Path of the file relative to the root work directory
+	Path    string
+	Pointer string
+	Size    int64
+	SHA256  string
 }
 
 type AutopilotResult struct {
 	StepResults    []StepResult
 	EvaluateResult EvaluateResult
 	Name           string
+	Duration       time.Duration
 }
 
 type LogEntry struct {
 	Source string                 `json:"source,omitempty"`
 	Json   map[string]interface{} `json:"json,omitempty"`
 	Text   string                 `json:"text,omitempty"`
+	// Line is the output line the json was decoded from, it is kept for results in the v1 format
+	Line string `json:"-"`
 }
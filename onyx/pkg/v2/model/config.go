@@ -5,6 +5,10 @@
 package model
 
 import (
+	"os"
+	"regexp"
+	"strings"
+
 	conf "github.com/B-S-F/yaku/onyx/pkg/configuration"
 )
 
@@ -16,20 +20,46 @@ type ExecutionPlan struct {
 	AutopilotChecks []AutopilotCheck
 	ManualChecks    []ManualCheck
 	Repositories    []conf.Repository
+	Services        []Service
+	Setup           []Finalize
+	Teardown        []Finalize
 	Finalize        *Finalize
+	Finalizers      []Finalize
+	Notifications   []Notification
 }
 
 type Item struct {
 	Chapter     conf.Chapter
 	Requirement conf.Requirement
 	Check       conf.Check
+	Labels      []string
+	// PreviousIds are former IDs of the check, e.g. before it was renamed
+	PreviousIds []string
+	Conditions  []Condition
+}
+
+// Condition restricts the applicability of an item, it is defined on the chapter, requirement or check of the item
+type Condition struct {
+	// Level is the part of the item which defines the condition, e.g. "chapter '1'"
+	Level      string
+	When       string
+	ValidFrom  string
+	ValidUntil string
 }
 
 type Autopilot struct {
-	Env      map[string]string
-	Evaluate Evaluate
-	Name     string
-	Steps    [][]Step
+	Env         map[string]string
+	Evaluate    Evaluate
+	Name        string
+	Steps       [][]Step
+	SecretFiles []SecretFile
+	// Network restricts the destinations of the steps, nil if they are not restricted
+	Network *Network
+}
+
+// Network contains the destinations the steps of an autopilot are allowed to connect to
+type Network struct {
+	Allow []string
 }
 
 type Step struct {
@@ -37,12 +67,41 @@ type Step struct {
 	ID      string
 	Env     map[string]string
 	Configs map[string]string
-	Run     string
-	Depends []string
+	// InlineConfigs are config files defined in the config, they are added to Configs when the config files are loaded
+	InlineConfigs  []InlineConfig
+	ConfigTemplate bool
+	SecretFiles    []SecretFile
+	Run            string
+	Depends        []string
+	// ProxyEnv routes the traffic of the step through the egress proxy, it takes precedence over the env of the step and the autopilot
+	ProxyEnv map[string]string
 }
 
 type Evaluate struct {
 	Env     map[string]string
 	Configs map[string]string
-	Run     string
+	// InlineConfigs are config files defined in the config, they are added to Configs when the config files are loaded
+	InlineConfigs []InlineConfig
+	Run           string
+}
+
+// SecretFile is a file with the content of a secret
+type SecretFile struct {
+	Name   string
+	Secret string
+	Mode   os.FileMode
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// SecretFileEnv returns the name of the environment variable with the path of the secret file
+func SecretFileEnv(name string) string {
+	return "SECRET_FILE_" + nonEnvChars.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+// InlineConfig is a config file defined in the config.
+// It is kept apart from the loaded config files so that its content only gets the replacements of config files.
+type InlineConfig struct {
+	Name    string
+	Content string
 }
@@ -4,10 +4,22 @@
 
 package model
 
+import (
+	"time"
+
+	conf "github.com/B-S-F/yaku/onyx/pkg/configuration"
+)
+
 type Finalize struct {
-	Env     map[string]string
-	Configs map[string]string
-	Run     string
+	// Name and If are only set for named finalizers, Name is also set for setup and teardown steps
+	Name          string
+	If            string
+	Env           map[string]string
+	Configs       map[string]string
+	Run           string
+	AppReferences []*conf.AppReference
+	AppPath       string
+	Timeout       time.Duration
 }
 
 type FinalizeResult struct {
@@ -15,3 +27,11 @@ type FinalizeResult struct {
 	ExitCode   int
 	OutputPath string
 }
+
+// FinalizerRun contains the outcome of a named finalizer
+type FinalizerRun struct {
+	Finalize Finalize
+	Status   string
+	Reason   string
+	Result   *FinalizeResult
+}
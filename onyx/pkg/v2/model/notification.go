@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package model
+
+import "time"
+
+type Notification struct {
+	Name    string
+	Type    string
+	On      *NotificationTrigger
+	URL     string
+	Headers map[string]string
+	SMTP    *SMTP
+}
+
+type NotificationTrigger struct {
+	Status                []string
+	StatusChanged         bool
+	ManualAnswersExpiring time.Duration
+}
+
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Subject  string
+}
+
+// NotificationRun contains the outcome of a notification
+type NotificationRun struct {
+	Notification Notification
+	Status       string
+	Reason       string
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+type Service struct {
+	Name           string
+	Env            map[string]string
+	Port           int
+	HealthCheck    string
+	StartupTimeout time.Duration
+	Restart        string
+	MaxRestarts    int
+	Run            string
+}
+
+// ServiceRun contains the outcome of a background service
+type ServiceRun struct {
+	Service  Service
+	Status   string
+	Reason   string
+	Restarts int
+	LogFile  string
+}
+
+// ServiceEnvPrefix returns the prefix of the environment variables which expose the service to the checks
+func ServiceEnvPrefix(name string) string {
+	return "SERVICE_" + nonEnvChars.ReplaceAllString(strings.ToUpper(name), "_") + "_"
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+// httpNotifier posts the payload created from the message as JSON to a webhook
+type httpNotifier struct {
+	url     string
+	headers map[string]string
+	payload func(msg Message) interface{}
+	client  *http.Client
+}
+
+func newHTTPNotifier(n model.Notification, payload func(msg Message) interface{}) *httpNotifier {
+	return &httpNotifier{
+		url:     n.URL,
+		headers: n.Headers,
+		payload: payload,
+		client:  &http.Client{},
+	}
+}
+
+func (h *httpNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(h.payload(msg))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal payload")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range h.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		// the url might contain a secret token, so it is not part of the error
+		return errors.New("failed to send request to webhook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("webhook responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+func webhookPayload(msg Message) interface{} {
+	return msg
+}
+
+// teamsPayload creates a message card for Microsoft Teams incoming webhooks
+func teamsPayload(msg Message) interface{} {
+	facts := []map[string]string{
+		{"name": "Status", "value": msg.OverallStatus},
+		{"name": "Reason", "value": strings.Join(msg.Reasons, ", ")},
+	}
+	if msg.PreviousStatus != "" {
+		facts = append(facts, map[string]string{"name": "Previous status", "value": msg.PreviousStatus})
+	}
+	facts = append(facts, map[string]string{"name": "Checks", "value": fmt.Sprintf("%d", msg.Statistics.CountChecks)})
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title(),
+		"themeColor": statusColor(msg.OverallStatus),
+		"title":      msg.Title(),
+		"sections": []map[string]interface{}{
+			{
+				"facts":    facts,
+				"text":     strings.Join(detailLines(msg), "<br>"),
+				"markdown": true,
+			},
+		},
+	}
+}
+
+// slackPayload creates a message for Slack incoming webhooks
+func slackPayload(msg Message) interface{} {
+	fields := []map[string]interface{}{
+		{"title": "Status", "value": msg.OverallStatus, "short": true},
+		{"title": "Reason", "value": strings.Join(msg.Reasons, ", "), "short": true},
+	}
+	if msg.PreviousStatus != "" {
+		fields = append(fields, map[string]interface{}{"title": "Previous status", "value": msg.PreviousStatus, "short": true})
+	}
+	return map[string]interface{}{
+		"text": msg.Title(),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  "#" + statusColor(msg.OverallStatus),
+				"fields": fields,
+				"text":   strings.Join(detailLines(msg), "\n"),
+			},
+		},
+	}
+}
+
+// detailLines lists the checks which need attention and the expiring manual answers
+func detailLines(msg Message) []string {
+	var lines []string
+	for _, check := range msg.Checks {
+		lines = append(lines, fmt.Sprintf("[%s] %s_%s_%s %s: %s", check.Status, check.Chapter, check.Requirement, check.Check, check.Title, check.Reason))
+	}
+	for _, answer := range msg.ExpiringAnswers {
+		verb := "expires"
+		if answer.Expired {
+			verb = "expired"
+		}
+		lines = append(lines, fmt.Sprintf("manual answer of %s_%s_%s %s %s on %s", answer.Chapter, answer.Requirement, answer.Check, answer.Title, verb, answer.Expires))
+	}
+	return lines
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package notifier
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+)
+
+// Message is the summary of a run which is sent by all notifiers.
+// It is also the payload of the generic webhook.
+type Message struct {
+	Project         string                        `json:"project"`
+	Version         string                        `json:"version"`
+	Date            string                        `json:"date"`
+	OverallStatus   string                        `json:"overallStatus"`
+	PreviousStatus  string                        `json:"previousStatus,omitempty"`
+	Reasons         []string                      `json:"reasons"`
+	Statistics      result.Statistics             `json:"statistics"`
+	Chapters        []ChapterSummary              `json:"chapters,omitempty"`
+	LabelStatistics map[string]*result.Statistics `json:"labelStatistics,omitempty"`
+	Checks          []CheckSummary                `json:"checks,omitempty"`
+	ExpiringAnswers []ExpiringAnswer              `json:"expiringAnswers,omitempty"`
+}
+
+// ChapterSummary contains the status and the statistics of a chapter
+type ChapterSummary struct {
+	Chapter    string             `json:"chapter"`
+	Title      string             `json:"title"`
+	Status     string             `json:"status"`
+	Statistics *result.Statistics `json:"statistics,omitempty"`
+}
+
+// CheckSummary contains a check which needs attention, i.e. is not GREEN
+type CheckSummary struct {
+	Chapter     string `json:"chapter"`
+	Requirement string `json:"requirement"`
+	Check       string `json:"check"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason"`
+}
+
+// ExpiringAnswer contains a manual answer which expires soon or is already expired
+type ExpiringAnswer struct {
+	Chapter     string `json:"chapter"`
+	Requirement string `json:"requirement"`
+	Check       string `json:"check"`
+	Title       string `json:"title"`
+	Expires     string `json:"expires"`
+	Expired     bool   `json:"expired"`
+}
+
+var attentionStatus = map[string]bool{"RED": true, "YELLOW": true, "ERROR": true}
+
+func NewMessage(res *result.Result, previous *history.Entry, reasons []string, expiring []ExpiringAnswer) Message {
+	msg := Message{
+		Project:         res.Header.Name,
+		Version:         res.Header.Version,
+		Date:            res.Header.Date,
+		OverallStatus:   res.OverallStatus,
+		Reasons:         reasons,
+		Statistics:      res.Statistics,
+		LabelStatistics: res.LabelStatistics,
+		ExpiringAnswers: expiring,
+	}
+	if previous != nil {
+		msg.PreviousStatus = previous.OverallStatus
+	}
+	for _, chapID := range sortedKeys(res.Chapters) {
+		chapter := res.Chapters[chapID]
+		msg.Chapters = append(msg.Chapters, ChapterSummary{
+			Chapter:    chapID,
+			Title:      chapter.Title,
+			Status:     chapter.Status,
+			Statistics: chapter.Statistics,
+		})
+	}
+	forEachCheck(res, func(chapID, reqID, checkID string, check *result.Check) {
+		if !attentionStatus[check.Evaluation.Status] {
+			return
+		}
+		msg.Checks = append(msg.Checks, CheckSummary{
+			Chapter:     chapID,
+			Requirement: reqID,
+			Check:       checkID,
+			Title:       check.Title,
+			Status:      check.Evaluation.Status,
+			Reason:      check.Evaluation.Reason,
+		})
+	})
+	return msg
+}
+
+// Title returns a one line summary of the message
+func (m Message) Title() string {
+	return fmt.Sprintf("Quality gate '%s' (%s) finished with status %s", m.Project, m.Version, m.OverallStatus)
+}
+
+// forEachCheck calls fn for all checks of the result ordered by their ids
+func forEachCheck(res *result.Result, fn func(chapID, reqID, checkID string, check *result.Check)) {
+	for _, chapID := range sortedKeys(res.Chapters) {
+		chapter := res.Chapters[chapID]
+		for _, reqID := range sortedKeys(chapter.Requirements) {
+			requirement := chapter.Requirements[reqID]
+			for _, checkID := range sortedKeys(requirement.Checks) {
+				fn(chapID, reqID, checkID, requirement.Checks[checkID])
+			}
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func statusColor(status string) string {
+	switch status {
+	case "GREEN":
+		return "2EB67D"
+	case "YELLOW":
+		return "ECB22E"
+	case "RED", "ERROR":
+		return "E01E5A"
+	default:
+		return "808080"
+	}
+}
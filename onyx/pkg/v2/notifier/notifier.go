@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package notifier sends the outcome of a run to email recipients and chat or
+// webhook endpoints if one of the configured trigger conditions is met.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/pkg/errors"
+)
+
+const (
+	NotificationSent    = "SENT"
+	NotificationSkipped = "SKIPPED"
+	NotificationFailed  = "FAILED"
+)
+
+// Timeout is the maximum time a single notification may take
+var Timeout = 30 * time.Second
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// New creates the notifier for the type of the notification
+func New(n model.Notification) (Notifier, error) {
+	switch n.Type {
+	case "smtp":
+		if n.SMTP == nil {
+			return nil, errors.New("missing smtp settings")
+		}
+		return &smtpNotifier{settings: *n.SMTP}, nil
+	case "webhook":
+		return newHTTPNotifier(n, webhookPayload), nil
+	case "teams":
+		return newHTTPNotifier(n, teamsPayload), nil
+	case "slack":
+		return newHTTPNotifier(n, slackPayload), nil
+	default:
+		return nil, errors.Errorf("unsupported notification type '%s'", n.Type)
+	}
+}
+
+// Send evaluates the trigger conditions of the notification and sends it if any of them is met.
+// The previous history entry is nil if there is no previous run.
+func Send(n model.Notification, res *result.Result, previous *history.Entry, now time.Time) model.NotificationRun {
+	log := logger.Get()
+	run := model.NotificationRun{Notification: n}
+
+	expiring := expiringAnswers(n, res, now)
+	reasons := triggerReasons(n, res, previous, expiring)
+	if len(reasons) == 0 {
+		run.Status = NotificationSkipped
+		run.Reason = "no trigger condition is met"
+		log.Infof("skipping notification '%s': %s", n.Name, run.Reason)
+		return run
+	}
+
+	notifier, err := New(n)
+	if err != nil {
+		run.Status = NotificationFailed
+		run.Reason = err.Error()
+		log.Warnf("failed to send notification '%s': %s", n.Name, run.Reason)
+		return run
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	if err := notifier.Notify(ctx, NewMessage(res, previous, reasons, expiring)); err != nil {
+		run.Status = NotificationFailed
+		run.Reason = err.Error()
+		log.Warnf("failed to send notification '%s': %s", n.Name, run.Reason)
+		return run
+	}
+
+	run.Status = NotificationSent
+	run.Reason = strings.Join(reasons, ", ")
+	log.Infof("sent notification '%s': %s", n.Name, run.Reason)
+	return run
+}
+
+func triggerReasons(n model.Notification, res *result.Result, previous *history.Entry, expiring []ExpiringAnswer) []string {
+	if n.On == nil {
+		return []string{"notification is sent after every run"}
+	}
+	var reasons []string
+	for _, status := range n.On.Status {
+		if status == res.OverallStatus {
+			reasons = append(reasons, fmt.Sprintf("overall status is '%s'", status))
+			break
+		}
+	}
+	if n.On.StatusChanged && previous != nil && previous.OverallStatus != res.OverallStatus {
+		reasons = append(reasons, fmt.Sprintf("overall status changed from '%s' to '%s'", previous.OverallStatus, res.OverallStatus))
+	}
+	if len(expiring) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d manual answer(s) expire within %s", len(expiring), formatPeriod(n.On.ManualAnswersExpiring)))
+	}
+	return reasons
+}
+
+// expiringAnswers returns the manual answers which expire within the configured period or are already expired
+func expiringAnswers(n model.Notification, res *result.Result, now time.Time) []ExpiringAnswer {
+	if n.On == nil || n.On.ManualAnswersExpiring <= 0 {
+		return nil
+	}
+	deadline := now.Add(n.On.ManualAnswersExpiring)
+	var expiring []ExpiringAnswer
+	forEachCheck(res, func(chapID, reqID, checkID string, check *result.Check) {
+		if check.Type != "manual" || check.Expires == "" {
+			return
+		}
+		expires, err := time.Parse(time.DateOnly, check.Expires)
+		if err != nil {
+			logger.Get().Warnf("invalid expiry date '%s' of check '%s'", check.Expires, history.CheckKey(chapID, reqID, checkID))
+			return
+		}
+		if expires.After(deadline) {
+			return
+		}
+		expiring = append(expiring, ExpiringAnswer{
+			Chapter:     chapID,
+			Requirement: reqID,
+			Check:       checkID,
+			Title:       check.Title,
+			Expires:     check.Expires,
+			Expired:     !expires.After(now),
+		})
+	})
+	return expiring
+}
+
+func formatPeriod(d time.Duration) string {
+	day := 24 * time.Hour
+	if d%day == 0 {
+		if d == day {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
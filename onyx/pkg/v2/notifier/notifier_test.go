@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func testResult() *result.Result {
+	return &result.Result{
+		Header:        result.Header{Name: "My Project", Version: "1.0", Date: "2024-06-01 12:00"},
+		OverallStatus: "RED",
+		Statistics:    result.Statistics{CountChecks: 3, CountAutomatedChecks: 1, CountManualChecks: 2},
+		LabelStatistics: map[string]*result.Statistics{
+			"security": {CountChecks: 1, CountAutomatedChecks: 1, CountCriteria: 2, CountFailedCriteria: 1},
+		},
+		Chapters: map[string]*result.Chapter{
+			"1": {Title: "chapter", Status: "RED", Statistics: &result.Statistics{CountChecks: 3, CountCriteria: 2, CountFailedCriteria: 1}, Requirements: map[string]*result.Requirement{
+				"1": {Status: "RED", Checks: map[string]*result.Check{
+					"1": {Title: "automated", Type: "automation", Evaluation: result.Evaluation{Status: "RED", Reason: "criterion not fulfilled"}},
+					"2": {Title: "reviewed", Type: "manual", Evaluation: result.Evaluation{Status: "GREEN", Reason: "reviewed"}, Expires: "2024-06-10"},
+					"3": {Title: "approved", Type: "manual", Evaluation: result.Evaluation{Status: "GREEN", Reason: "approved"}, Expires: "2025-01-01"},
+				}},
+			}},
+		},
+	}
+}
+
+func TestTriggerReasons(t *testing.T) {
+	tests := map[string]struct {
+		on       *model.NotificationTrigger
+		previous *history.Entry
+		want     []string
+	}{
+		"should_always_trigger_without_conditions": {
+			want: []string{"notification is sent after every run"},
+		},
+		"should_trigger_on_status": {
+			on:   &model.NotificationTrigger{Status: []string{"YELLOW", "RED"}},
+			want: []string{"overall status is 'RED'"},
+		},
+		"should_trigger_on_status_change": {
+			on:       &model.NotificationTrigger{StatusChanged: true},
+			previous: &history.Entry{OverallStatus: "GREEN"},
+			want:     []string{"overall status changed from 'GREEN' to 'RED'"},
+		},
+		"should_not_trigger_on_status_change_without_previous_run": {
+			on: &model.NotificationTrigger{StatusChanged: true},
+		},
+		"should_not_trigger_on_unchanged_status": {
+			on:       &model.NotificationTrigger{StatusChanged: true, Status: []string{"GREEN"}},
+			previous: &history.Entry{OverallStatus: "RED"},
+		},
+		"should_trigger_on_expiring_manual_answers": {
+			on:   &model.NotificationTrigger{ManualAnswersExpiring: 14 * 24 * time.Hour},
+			want: []string{"1 manual answer(s) expire within 14 days"},
+		},
+		"should_not_trigger_if_manual_answers_expire_later": {
+			on: &model.NotificationTrigger{ManualAnswersExpiring: 24 * time.Hour},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := model.Notification{Name: "test", Type: "webhook", On: tt.on}
+			res := testResult()
+
+			reasons := triggerReasons(n, res, tt.previous, expiringAnswers(n, res, now))
+
+			assert.Equal(t, tt.want, reasons)
+		})
+	}
+}
+
+func TestSend_Webhook(t *testing.T) {
+	var received Message
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	n := model.Notification{
+		Name:    "hook",
+		Type:    "webhook",
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		On:      &model.NotificationTrigger{StatusChanged: true, ManualAnswersExpiring: 14 * 24 * time.Hour},
+	}
+
+	run := Send(n, testResult(), &history.Entry{OverallStatus: "GREEN"}, now)
+
+	assert.Equal(t, model.NotificationRun{
+		Notification: n,
+		Status:       NotificationSent,
+		Reason:       "overall status changed from 'GREEN' to 'RED', 1 manual answer(s) expire within 14 days",
+	}, run)
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, Message{
+		Project:        "My Project",
+		Version:        "1.0",
+		Date:           "2024-06-01 12:00",
+		OverallStatus:  "RED",
+		PreviousStatus: "GREEN",
+		Reasons:        []string{"overall status changed from 'GREEN' to 'RED'", "1 manual answer(s) expire within 14 days"},
+		Statistics:     result.Statistics{CountChecks: 3, CountAutomatedChecks: 1, CountManualChecks: 2},
+		Chapters: []ChapterSummary{
+			{Chapter: "1", Title: "chapter", Status: "RED", Statistics: &result.Statistics{CountChecks: 3, CountCriteria: 2, CountFailedCriteria: 1}},
+		},
+		LabelStatistics: map[string]*result.Statistics{
+			"security": {CountChecks: 1, CountAutomatedChecks: 1, CountCriteria: 2, CountFailedCriteria: 1},
+		},
+		Checks: []CheckSummary{{Chapter: "1", Requirement: "1", Check: "1", Title: "automated", Status: "RED", Reason: "criterion not fulfilled"}},
+		ExpiringAnswers: []ExpiringAnswer{
+			{Chapter: "1", Requirement: "1", Check: "2", Title: "reviewed", Expires: "2024-06-10"},
+		},
+	}, received)
+}
+
+func TestSend_TeamsAndSlack(t *testing.T) {
+	tests := map[string]struct {
+		assert func(t *testing.T, payload map[string]interface{})
+	}{
+		"teams": {
+			assert: func(t *testing.T, payload map[string]interface{}) {
+				assert.Equal(t, "MessageCard", payload["@type"])
+				assert.Equal(t, "E01E5A", payload["themeColor"])
+				assert.Equal(t, "Quality gate 'My Project' (1.0) finished with status RED", payload["title"])
+			},
+		},
+		"slack": {
+			assert: func(t *testing.T, payload map[string]interface{}) {
+				assert.Equal(t, "Quality gate 'My Project' (1.0) finished with status RED", payload["text"])
+				attachment := payload["attachments"].([]interface{})[0].(map[string]interface{})
+				assert.Equal(t, "#E01E5A", attachment["color"])
+				assert.Equal(t, "[RED] 1_1_1 automated: criterion not fulfilled", attachment["text"])
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &payload)
+			}))
+			defer server.Close()
+
+			run := Send(model.Notification{Name: name, Type: name, URL: server.URL}, testResult(), nil, now)
+
+			require.Equal(t, NotificationSent, run.Status)
+			tt.assert(t, payload)
+		})
+	}
+}
+
+func TestSend_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("invalid token"))
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		notification model.Notification
+		wantStatus   string
+		wantReason   string
+	}{
+		"should_fail_on_error_response": {
+			notification: model.Notification{Name: "hook", Type: "webhook", URL: server.URL},
+			wantStatus:   NotificationFailed,
+			wantReason:   "webhook responded with status 403: invalid token",
+		},
+		"should_not_leak_url_on_connection_error": {
+			notification: model.Notification{Name: "hook", Type: "webhook", URL: "http://127.0.0.1:1/secret-token"},
+			wantStatus:   NotificationFailed,
+			wantReason:   "failed to send request to webhook",
+		},
+		"should_skip_if_not_triggered": {
+			notification: model.Notification{Name: "hook", Type: "webhook", URL: server.URL, On: &model.NotificationTrigger{Status: []string{"GREEN"}}},
+			wantStatus:   NotificationSkipped,
+			wantReason:   "no trigger condition is met",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			run := Send(tt.notification, testResult(), nil, now)
+
+			assert.Equal(t, tt.wantStatus, run.Status)
+			assert.Equal(t, tt.wantReason, run.Reason)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSMTPPort  = 587
+	implicitTLSPort  = 465
+	emailContentType = "text/html; charset=UTF-8"
+)
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{ .Title }}</h2>
+<p>Status: <strong style="color: #{{ .Color }};">{{ .Message.OverallStatus }}</strong>{{ if .Message.PreviousStatus }} (previous run: {{ .Message.PreviousStatus }}){{ end }}</p>
+<p>Date: {{ .Message.Date }}</p>
+<ul>
+{{- range .Message.Reasons }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+<p>{{ .Message.Statistics.CountChecks }} checks, {{ .Message.Statistics.CountAutomatedChecks }} automated, {{ .Message.Statistics.CountManualChecks }} manual, {{ .Message.Statistics.CountUnansweredChecks }} unanswered{{ if .Message.Statistics.CountFailedCriteria }}, {{ .Message.Statistics.CountFailedCriteria }} of {{ .Message.Statistics.CountCriteria }} criteria failed{{ end }}</p>
+{{- if .Message.Chapters }}
+<h3>Chapters</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Chapter</th><th>Title</th><th>Status</th><th>Checks</th><th>Failed criteria</th></tr>
+{{- range .Message.Chapters }}
+<tr><td>{{ .Chapter }}</td><td>{{ .Title }}</td><td>{{ .Status }}</td>{{ if .Statistics }}<td>{{ .Statistics.CountChecks }}</td><td>{{ .Statistics.CountFailedCriteria }}</td>{{ else }}<td>-</td><td>-</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Message.LabelStatistics }}
+<h3>Labels</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Label</th><th>Checks</th><th>Automated</th><th>Done</th><th>Failed criteria</th></tr>
+{{- range $label, $statistics := .Message.LabelStatistics }}
+<tr><td>{{ $label }}</td><td>{{ $statistics.CountChecks }}</td><td>{{ $statistics.PercentageAutomated }}%</td><td>{{ $statistics.PercentageDone }}%</td><td>{{ $statistics.CountFailedCriteria }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Message.Checks }}
+<h3>Checks which need attention</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Check</th><th>Title</th><th>Status</th><th>Reason</th></tr>
+{{- range .Message.Checks }}
+<tr><td>{{ .Chapter }}_{{ .Requirement }}_{{ .Check }}</td><td>{{ .Title }}</td><td>{{ .Status }}</td><td>{{ .Reason }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Message.ExpiringAnswers }}
+<h3>Expiring manual answers</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Check</th><th>Title</th><th>Expires</th></tr>
+{{- range .Message.ExpiringAnswers }}
+<tr><td>{{ .Chapter }}_{{ .Requirement }}_{{ .Check }}</td><td>{{ .Title }}</td><td>{{ .Expires }}{{ if .Expired }} (expired){{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+type smtpNotifier struct {
+	settings model.SMTP
+}
+
+func (s *smtpNotifier) Notify(ctx context.Context, msg Message) error {
+	from, to, err := s.addresses()
+	if err != nil {
+		return err
+	}
+	body, err := s.render(msg, from, to)
+	if err != nil {
+		return err
+	}
+
+	port := s.settings.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(s.settings.Host, strconv.Itoa(port))
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(Timeout)
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	if port == implicitTLSPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.settings.Host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to smtp server '%s'", addr)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return errors.Wrap(err, "failed to set deadline")
+	}
+
+	client, err := smtp.NewClient(conn, s.settings.Host)
+	if err != nil {
+		return errors.Wrap(err, "failed to create smtp client")
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != implicitTLSPort {
+		if err := client.StartTLS(&tls.Config{ServerName: s.settings.Host}); err != nil {
+			return errors.Wrap(err, "failed to start tls")
+		}
+	}
+	if s.settings.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.settings.Username, s.settings.Password, s.settings.Host)); err != nil {
+			return errors.Wrap(err, "failed to authenticate at smtp server")
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return errors.Wrapf(err, "smtp server rejected sender '%s'", from.Address)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient.Address); err != nil {
+			return errors.Wrapf(err, "smtp server rejected recipient '%s'", recipient.Address)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+	if _, err := w.Write(body); err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+	return client.Quit()
+}
+
+// addresses parses the sender and the recipients, which also rejects line breaks that would inject headers into the email
+func (s *smtpNotifier) addresses() (*mail.Address, []*mail.Address, error) {
+	from, err := mail.ParseAddress(s.settings.From)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid sender '%s'", s.settings.From)
+	}
+	var to []*mail.Address
+	for _, recipient := range s.settings.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid recipient '%s'", recipient)
+		}
+		to = append(to, address)
+	}
+	return from, to, nil
+}
+
+func (s *smtpNotifier) render(msg Message, from *mail.Address, to []*mail.Address) ([]byte, error) {
+	subject := s.settings.Subject
+	if subject == "" {
+		subject = msg.Title()
+	}
+	recipients := make([]string, 0, len(to))
+	for _, recipient := range to {
+		recipients = append(recipients, recipient.String())
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", emailContentType)
+
+	err := emailTemplate.Execute(&buf, map[string]interface{}{
+		"Title":   msg.Title(),
+		"Color":   statusColor(msg.OverallStatus),
+		"Message": msg,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render email")
+	}
+	return buf.Bytes(), nil
+}
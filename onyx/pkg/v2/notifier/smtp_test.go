@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package notifier
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer starts a minimal smtp server which accepts a single email
+func startSMTPServer(t *testing.T) (string, int, <-chan receivedMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan receivedMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := textproto.NewReader(bufio.NewReader(conn))
+		w := textproto.NewWriter(bufio.NewWriter(conn))
+		var mail receivedMail
+		_ = w.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch cmd {
+			case "EHLO", "HELO":
+				_ = w.PrintfLine("250 localhost")
+			case "MAIL":
+				mail.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+				_ = w.PrintfLine("250 OK")
+			case "RCPT":
+				mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+				_ = w.PrintfLine("250 OK")
+			case "DATA":
+				_ = w.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				lines, err := r.ReadDotLines()
+				if err != nil {
+					return
+				}
+				mail.data = strings.Join(lines, "\n")
+				_ = w.PrintfLine("250 OK")
+				mails <- mail
+			case "QUIT":
+				_ = w.PrintfLine("221 Bye")
+				return
+			default:
+				_ = w.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return host, p, mails
+}
+
+func TestSend_SMTP(t *testing.T) {
+	host, port, mails := startSMTPServer(t)
+	n := model.Notification{
+		Name: "mail",
+		Type: "smtp",
+		On:   &model.NotificationTrigger{Status: []string{"RED"}},
+		SMTP: &model.SMTP{
+			Host: host,
+			Port: port,
+			From: "Quality Gate <qg@example.com>",
+			To:   []string{"team@example.com", "lead@example.com"},
+		},
+	}
+
+	run := Send(n, testResult(), nil, now)
+
+	require.Equal(t, NotificationSent, run.Status, run.Reason)
+	mail := <-mails
+	assert.Equal(t, "qg@example.com", mail.from)
+	assert.Equal(t, []string{"team@example.com", "lead@example.com"}, mail.to)
+	assert.Contains(t, mail.data, "From: \"Quality Gate\" <qg@example.com>")
+	assert.Contains(t, mail.data, "To: <team@example.com>, <lead@example.com>")
+	assert.Contains(t, mail.data, "Subject: Quality gate 'My Project' (1.0) finished with status RED")
+	assert.Contains(t, mail.data, "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, mail.data, "<li>overall status is &#39;RED&#39;</li>")
+	assert.Contains(t, mail.data, "<tr><td>1_1_1</td><td>automated</td><td>RED</td><td>criterion not fulfilled</td></tr>")
+	assert.Contains(t, mail.data, "<tr><td>1</td><td>chapter</td><td>RED</td><td>3</td><td>1</td></tr>")
+	assert.Contains(t, mail.data, "<tr><td>security</td><td>1</td><td>0%</td><td>0%</td><td>1</td></tr>")
+}
+
+func TestSend_SMTPConnectionError(t *testing.T) {
+	n := model.Notification{
+		Name: "mail",
+		Type: "smtp",
+		SMTP: &model.SMTP{Host: "127.0.0.1", Port: 1, From: "qg@example.com", To: []string{"team@example.com"}},
+	}
+
+	run := Send(n, testResult(), nil, now)
+
+	assert.Equal(t, NotificationFailed, run.Status)
+	assert.Contains(t, run.Reason, "failed to connect to smtp server '127.0.0.1:1'")
+}
+
+func TestSend_SMTPRejectsHeaderInjection(t *testing.T) {
+	testCases := map[string]struct {
+		from   string
+		to     []string
+		reason string
+	}{
+		"sender": {
+			from:   "qg@example.com\r\nBcc: attacker@example.com",
+			to:     []string{"team@example.com"},
+			reason: "invalid sender 'qg@example.com\r\nBcc: attacker@example.com'",
+		},
+		"recipient": {
+			from:   "qg@example.com",
+			to:     []string{"team@example.com\r\nBcc: attacker@example.com"},
+			reason: "invalid recipient 'team@example.com\r\nBcc: attacker@example.com'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			n := model.Notification{
+				Name: "mail",
+				Type: "smtp",
+				SMTP: &model.SMTP{Host: "127.0.0.1", Port: 1, From: tc.from, To: tc.to},
+			}
+
+			run := Send(n, testResult(), nil, now)
+
+			assert.Equal(t, NotificationFailed, run.Status)
+			assert.Contains(t, run.Reason, tc.reason)
+		})
+	}
+}
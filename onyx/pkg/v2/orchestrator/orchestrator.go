@@ -14,6 +14,7 @@ import (
 
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/executor"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/expression"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
 	"github.com/B-S-F/yaku/onyx/pkg/workdir"
 	errs "github.com/pkg/errors"
@@ -21,17 +22,68 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	FinalizerSucceeded = "SUCCEEDED"
+	FinalizerFailed    = "FAILED"
+	FinalizerSkipped   = "SKIPPED"
+	FinalizerError     = "ERROR"
+)
+
 type Orchestrator struct {
 	rootWorkDir string
 	strict      bool
 	timeout     time.Duration
 	logger      logger.Logger
+	limiter     *Limiter
+	shell       string
 }
 
 func New(rootWorkDir string, strict bool, timeout time.Duration, logger logger.Logger) *Orchestrator {
 	return &Orchestrator{rootWorkDir: rootWorkDir, timeout: timeout, logger: logger, strict: strict}
 }
 
+// WithLimiter bounds the number of autopilots and finalizers run at the same time.
+// The limiter can be shared between orchestrators to apply a global limit.
+func (o *Orchestrator) WithLimiter(limiter *Limiter) *Orchestrator {
+	o.limiter = limiter
+	return o
+}
+
+// WithShell selects the shell which runs the scripts of autopilots, finalizers and setup and teardown steps.
+// Scripts are run with /bin/bash by default, see runner.New.
+func (o *Orchestrator) WithShell(shell string) *Orchestrator {
+	o.shell = shell
+	return o
+}
+
+// Limiter restricts the number of concurrent executions.
+// A nil limiter does not restrict anything.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter returns a limiter for at most max concurrent executions, or nil if max is not positive.
+func NewLimiter(max int) *Limiter {
+	if max <= 0 {
+		return nil
+	}
+	return &Limiter{slots: make(chan struct{}, max)}
+}
+
+func (l *Limiter) acquire() {
+	if l == nil {
+		return
+	}
+	l.slots <- struct{}{}
+}
+
+func (l *Limiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
 type manualExec struct {
 	ManualCheck model.ManualCheck
 	Result      *model.ManualResult
@@ -163,10 +215,14 @@ func (o *Orchestrator) runAutopilots(autopilots []model.AutopilotCheck, env, sec
 	var wg sync.WaitGroup
 	executions := make(chan autopilotExec, len(autopilots))
 
+	// autopilots are started in the given order, the slot is taken before the goroutine is
+	// started so that a limited number of slots is handed out in that order as well
 	for _, a := range autopilots {
 		wg.Add(1)
+		o.limiter.acquire()
 		go func(autopilot model.AutopilotCheck, secrets map[string]string, wg *sync.WaitGroup, execs chan<- autopilotExec, rootWorkDir string, strict bool, timeout time.Duration) {
 			defer wg.Done()
+			defer o.limiter.release()
 
 			logger := logger.NewAutopilot(logger.Settings{
 				Secrets: secrets,
@@ -178,12 +234,16 @@ func (o *Orchestrator) runAutopilots(autopilots []model.AutopilotCheck, env, sec
 				o.strict,
 				logger,
 				o.timeout,
-			)
+			).WithShell(o.shell)
 
 			logger.Info(fmt.Sprintf("[[ CHAPTER: %s REQUIREMENT: %s CHECK: %s ]]", strings.ToUpper(autopilot.Chapter.Id), strings.ToUpper(autopilot.Requirement.Id), strings.ToUpper(autopilot.Check.Id)))
 
 			exec := autopilotExec{AutopilotCheck: autopilot, Logs: logger}
+			started := time.Now()
 			exec.Result, exec.Err = autopilotExecutor.ExecuteAutopilotCheck(&autopilot, env, secrets)
+			if exec.Result != nil {
+				exec.Result.Duration = time.Since(started)
+			}
 			execs <- exec
 		}(a, secrets, &wg, executions, o.rootWorkDir, o.strict, o.timeout)
 	}
@@ -219,14 +279,81 @@ func (o *Orchestrator) runAutopilots(autopilots []model.AutopilotCheck, env, sec
 func (o *Orchestrator) RunFinalizer(finalize model.Finalize, env, secrets map[string]string) (*model.FinalizeResult, error) {
 	o.logger.Info("finalizer started")
 	o.logger.Debug("finalizer config", zap.Any("finalizer", finalize))
+	return o.runFinalize(finalize, "finalizer.log", env, secrets)
+}
+
+// RunNamedFinalizer runs a named finalizer if its condition is met by the given status variables.
+// The finalizer is skipped if the condition evaluates to false.
+func (o *Orchestrator) RunNamedFinalizer(finalize model.Finalize, statusVars map[string]interface{}, env, secrets map[string]string) model.FinalizerRun {
+	run := model.FinalizerRun{Finalize: finalize}
+	if finalize.If != "" {
+		ok, err := expression.Evaluate(finalize.If, statusVars)
+		if err != nil {
+			o.logger.UserErrorf("failed to evaluate condition of finalizer '%s': %s", finalize.Name, err.Error())
+			run.Status = FinalizerError
+			run.Reason = err.Error()
+			return run
+		}
+		if !ok {
+			o.logger.Infof("skipping finalizer '%s' because condition '%s' is not met", finalize.Name, finalize.If)
+			run.Status = FinalizerSkipped
+			run.Reason = fmt.Sprintf("condition '%s' evaluated to false", finalize.If)
+			return run
+		}
+	}
+
+	return o.runNamed("finalizer", finalize, env, secrets)
+}
+
+// RunSetup runs a setup step, it is executed like a named finalizer without a condition.
+func (o *Orchestrator) RunSetup(setup model.Finalize, env, secrets map[string]string) model.FinalizerRun {
+	return o.runNamed("setup", setup, env, secrets)
+}
+
+// RunTeardown runs a teardown step, it is executed like a named finalizer without a condition.
+func (o *Orchestrator) RunTeardown(teardown model.Finalize, env, secrets map[string]string) model.FinalizerRun {
+	return o.runNamed("teardown", teardown, env, secrets)
+}
+
+// runNamed runs a named finalize item of the given kind and logs into '<kind>_<name>.log'.
+func (o *Orchestrator) runNamed(kind string, finalize model.Finalize, env, secrets map[string]string) model.FinalizerRun {
+	run := model.FinalizerRun{Finalize: finalize}
+	o.logger.Infof("%s '%s' started", kind, finalize.Name)
+	o.logger.Debug(kind+" config", zap.Any(kind, finalize))
+	result, err := o.runFinalize(finalize, fmt.Sprintf("%s_%s.log", kind, finalize.Name), env, secrets)
+	if err != nil {
+		o.logger.UserErrorf("failed to run %s '%s': %s", kind, finalize.Name, err.Error())
+		run.Status = FinalizerError
+		run.Reason = err.Error()
+		return run
+	}
+
+	run.Result = result
+	if result.ExitCode != 0 {
+		run.Status = FinalizerFailed
+		if result.ExitCode == 124 {
+			run.Reason = fmt.Sprintf("%s '%s' timed out", kind, finalize.Name)
+		} else {
+			run.Reason = fmt.Sprintf("%s '%s' exited with exit code %d", kind, finalize.Name, result.ExitCode)
+		}
+		return run
+	}
+	run.Status = FinalizerSucceeded
+	return run
+}
+
+func (o *Orchestrator) runFinalize(finalize model.Finalize, logFile string, env, secrets map[string]string) (*model.FinalizeResult, error) {
+	o.limiter.acquire()
+	defer o.limiter.release()
+
 	logger := logger.NewAutopilot(logger.Settings{
 		Secrets: secrets,
-		Files:   []string{filepath.Join(o.rootWorkDir, "finalizer.log")},
+		Files:   []string{filepath.Join(o.rootWorkDir, logFile)},
 	})
 	defer logger.Flush()
 	defer logger.ToFile()
 
-	finalizeExecutor := executor.NewFinalizeExecutor(workdir.NewUtils(afero.NewOsFs()), o.rootWorkDir, logger, o.timeout)
+	finalizeExecutor := executor.NewFinalizeExecutor(workdir.NewUtils(afero.NewOsFs()), o.rootWorkDir, logger, o.timeout).WithShell(o.shell)
 
 	result, err := finalizeExecutor.Execute(&finalize, env, secrets)
 	if err != nil {
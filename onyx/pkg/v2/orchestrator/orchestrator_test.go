@@ -7,6 +7,7 @@ package orchestrator
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -244,7 +245,7 @@ echo 'done transforming'`},
 									}},
 									ExitCode: 0,
 									Logs: []model.LogEntry{
-										{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}},
+										{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}, Line: `{"status": "GREEN", "reason": "file matches", "result": {"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": {"severity": "HIGH", "package": "package1"}}}`},
 									},
 									Status: "GREEN",
 									Reason: "file matches",
@@ -279,14 +280,16 @@ echo 'done transforming'`},
 			}
 			for _, wantRes := range want.result.Autopilots {
 				for _, gotRes := range got.Autopilots {
-					if wantRes.AutopilotCheck.Item == gotRes.AutopilotCheck.Item {
+					if assert.ObjectsAreEqual(wantRes.AutopilotCheck.Item, gotRes.AutopilotCheck.Item) {
+						assert.Positive(t, gotRes.Result.Duration)
+						gotRes.Result.Duration = 0
 						assert.Equal(t, wantRes, gotRes)
 					}
 				}
 			}
 			for _, wantRes := range want.result.Manuals {
 				for _, gotRes := range got.Manuals {
-					if wantRes.ManualCheck.Item == gotRes.ManualCheck.Item {
+					if assert.ObjectsAreEqual(wantRes.ManualCheck.Item, gotRes.ManualCheck.Item) {
 						assert.Equal(t, wantRes, gotRes)
 					}
 				}
@@ -355,7 +358,7 @@ func simpleAutopilotCheckResult(dir string) *model.AutopilotResult {
 			}},
 			ExitCode: 0,
 			Logs: []model.LogEntry{
-				{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}},
+				{Source: "stdout", Json: map[string]interface{}{"status": "GREEN", "reason": "file matches", "result": map[string]interface{}{"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": map[string]interface{}{"severity": "HIGH", "package": "package1"}}}, Line: `{"status": "GREEN", "reason": "file matches", "result": {"criterion": "criteria1", "fulfilled": true, "justification": "reason1", "metadata": {"severity": "HIGH", "package": "package1"}}}`},
 			},
 			Status: "GREEN",
 			Reason: "file matches",
@@ -363,3 +366,115 @@ func simpleAutopilotCheckResult(dir string) *model.AutopilotResult {
 		Name: "autopilot",
 	}
 }
+
+func TestOrchestrator_RunNamedFinalizer(t *testing.T) {
+	statusVars := map[string]interface{}{
+		"status":   "RED",
+		"chapters": map[string]interface{}{"1": map[string]interface{}{"status": "GREEN"}},
+	}
+	tests := map[string]struct {
+		finalize   model.Finalize
+		wantStatus string
+		wantReason string
+		wantLogs   []model.LogEntry
+	}{
+		"should run finalizer without condition": {
+			finalize:   model.Finalize{Name: "always", Run: "echo hello"},
+			wantStatus: FinalizerSucceeded,
+			wantLogs:   []model.LogEntry{{Source: "stdout", Text: "hello"}},
+		},
+		"should run finalizer if condition is met": {
+			finalize:   model.Finalize{Name: "tickets", If: "status == 'RED' && chapters.1.status == 'GREEN'", Run: "echo $TICKET_PROJECT", Env: map[string]string{"TICKET_PROJECT": "QG"}},
+			wantStatus: FinalizerSucceeded,
+			wantLogs:   []model.LogEntry{{Source: "stdout", Text: "QG"}},
+		},
+		"should skip finalizer if condition is not met": {
+			finalize:   model.Finalize{Name: "publish", If: "status == 'GREEN'", Run: "echo publish"},
+			wantStatus: FinalizerSkipped,
+			wantReason: "condition 'status == 'GREEN'' evaluated to false",
+		},
+		"should return error if condition can not be evaluated": {
+			finalize:   model.Finalize{Name: "publish", If: "chapters.2.status == 'GREEN'", Run: "echo publish"},
+			wantStatus: FinalizerError,
+			wantReason: "failed to evaluate expression 'chapters.2.status == 'GREEN'': unknown variable 'chapters.2'",
+		},
+		"should fail if finalizer exits with non zero exit code": {
+			finalize:   model.Finalize{Name: "broken", Run: "exit 3"},
+			wantStatus: FinalizerFailed,
+			wantReason: "finalizer 'broken' exited with exit code 3",
+		},
+		"should fail if finalizer times out": {
+			finalize:   model.Finalize{Name: "slow", Run: "sleep 5", Timeout: 100 * time.Millisecond},
+			wantStatus: FinalizerFailed,
+			wantReason: "finalizer 'slow' timed out",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootWorkDir := t.TempDir()
+			o := New(rootWorkDir, false, 10*time.Second, logger.NewAutopilot())
+
+			run := o.RunNamedFinalizer(tt.finalize, statusVars, map[string]string{}, map[string]string{})
+
+			assert.Equal(t, tt.wantStatus, run.Status)
+			assert.Equal(t, tt.wantReason, run.Reason)
+			if tt.wantLogs != nil {
+				require.NotNil(t, run.Result)
+				assert.Equal(t, tt.wantLogs, run.Result.Logs)
+				assert.FileExists(t, filepath.Join(rootWorkDir, fmt.Sprintf("finalizer_%s.log", tt.finalize.Name)))
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RunSetupAndTeardown(t *testing.T) {
+	rootWorkDir := t.TempDir()
+	o := New(rootWorkDir, false, 10*time.Second, logger.NewAutopilot())
+
+	setup := o.RunSetup(model.Finalize{Name: "login", Run: "echo $USER_NAME", Env: map[string]string{"USER_NAME": "onyx"}}, map[string]string{}, map[string]string{})
+	teardown := o.RunTeardown(model.Finalize{Name: "logout", Run: "exit 2"}, map[string]string{}, map[string]string{})
+
+	assert.Equal(t, FinalizerSucceeded, setup.Status)
+	require.NotNil(t, setup.Result)
+	assert.Equal(t, []model.LogEntry{{Source: "stdout", Text: "onyx"}}, setup.Result.Logs)
+	assert.FileExists(t, filepath.Join(rootWorkDir, "setup_login.log"))
+	assert.Equal(t, FinalizerFailed, teardown.Status)
+	assert.Equal(t, "teardown 'logout' exited with exit code 2", teardown.Reason)
+	assert.FileExists(t, filepath.Join(rootWorkDir, "teardown_logout.log"))
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("should not limit without maximum", func(t *testing.T) {
+		limiter := NewLimiter(0)
+		assert.Nil(t, limiter)
+		limiter.acquire()
+		limiter.release()
+	})
+
+	t.Run("should limit concurrent executions", func(t *testing.T) {
+		limiter := NewLimiter(2)
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				limiter.acquire()
+				defer limiter.release()
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 2, maxRunning)
+	})
+}
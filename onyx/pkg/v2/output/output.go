@@ -47,6 +47,9 @@ func (o *Output) Log(l logger.Logger) error {
 			logHelper.LogKeyValueIndented("Fulfilled:", strconv.FormatBool(r.Fulfilled), 6)
 			logHelper.LogKeyValueIndented("Justification:", r.Justification, 6)
 			logHelper.LogFormatMapIndented("Metadata:", r.Metadata, 6)
+			for _, ref := range r.Evidence {
+				logHelper.LogKeyValueIndented("Evidence:", ref.Path+pointerFragment(ref.Pointer), 6)
+			}
 		}
 	}
 	if len(o.Outputs) != 0 {
@@ -64,3 +67,10 @@ func (o *Output) Log(l logger.Logger) error {
 
 	return nil
 }
+
+func pointerFragment(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	return "#" + pointer
+}
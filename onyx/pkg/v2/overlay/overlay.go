@@ -0,0 +1,395 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package overlay patches a base config with overlay files.
+//
+// An overlay is either a partial config which is merged into the base config or
+// a list of JSON patch operations:
+//   - merge: mappings are merged recursively, a null value removes a key and all
+//     other values replace the value of the base config. Lists whose items all
+//     have a 'name' are merged by name, an item with '$patch: delete' removes the
+//     item with the same name. Other lists are replaced.
+//   - JSON patch: a list of 'add', 'remove' and 'replace' operations with a JSON
+//     pointer as 'path', e.g. /chapters/1/requirements/2/checks/3
+package overlay
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	OperationAdd     = "add"
+	OperationRemove  = "remove"
+	OperationReplace = "replace"
+
+	patchKey    = "$patch"
+	patchDelete = "delete"
+	nameKey     = "name"
+)
+
+// Change is a single modification of the base config made by an overlay
+type Change struct {
+	Overlay   string
+	Operation string
+	Path      string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s", c.Operation, c.Path)
+}
+
+type patchOperation struct {
+	Op    string    `yaml:"op"`
+	Path  string    `yaml:"path"`
+	Value yaml.Node `yaml:"value"`
+}
+
+// ApplyFiles applies the overlay files in the given order to the base config.
+// Overlays are only supported for configs of version v2.
+func ApplyFiles(base []byte, files []string) ([]byte, []Change, error) {
+	var changes []Change
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "error reading overlay file %s", file)
+		}
+		var fileChanges []Change
+		base, fileChanges, err = Apply(base, file, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, fileChanges...)
+	}
+	return base, changes, nil
+}
+
+// Apply applies the overlay with the given name to the base config and returns the merged config and its changes
+func Apply(base []byte, name string, overlay []byte) ([]byte, []Change, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(base, &doc); err != nil {
+		return nil, nil, model.NewUserErr(errors.Wrap(err, "error parsing config file"), "invalid config file")
+	}
+	root := documentRoot(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, nil, model.NewUserErr(errors.New("config file is not a yaml mapping"), "invalid config file")
+	}
+	if version := configVersion(root); version != "v2" {
+		return nil, nil, model.NewUserErr(errors.Errorf("config of version '%s' can't be patched, overlays are only supported for version 'v2'", version), "invalid config file version")
+	}
+
+	var patch yaml.Node
+	if err := yaml.Unmarshal(overlay, &patch); err != nil {
+		return nil, nil, model.NewUserErr(errors.Wrapf(err, "error parsing overlay %s", name), "invalid overlay")
+	}
+	a := &applier{overlay: name}
+	patchRoot := documentRoot(&patch)
+	var err error
+	switch {
+	case patchRoot == nil || isNull(patchRoot):
+		return base, nil, nil
+	case patchRoot.Kind == yaml.MappingNode:
+		a.merge(root, patchRoot, "")
+	case patchRoot.Kind == yaml.SequenceNode:
+		err = a.applyOperations(root, patchRoot)
+	default:
+		err = errors.New("overlay must be a yaml mapping or a list of JSON patch operations")
+	}
+	if err != nil {
+		return nil, nil, model.NewUserErr(errors.Wrapf(err, "error applying overlay %s", name), "invalid overlay")
+	}
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, nil, errors.Wrap(err, "error writing patched config")
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "error writing patched config")
+	}
+	return b.Bytes(), a.changes, nil
+}
+
+type applier struct {
+	overlay string
+	changes []Change
+}
+
+func (a *applier) record(operation, path string) {
+	a.changes = append(a.changes, Change{Overlay: a.overlay, Operation: operation, Path: path})
+}
+
+// merge merges the patch into the base node, the path is the JSON pointer of the base node
+func (a *applier) merge(base, patch *yaml.Node, path string) {
+	switch {
+	case base.Kind == yaml.MappingNode && patch.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(patch.Content); i += 2 {
+			key, value := patch.Content[i], patch.Content[i+1]
+			keyPath := path + "/" + escape(key.Value)
+			index := mappingIndex(base, key.Value)
+			switch {
+			case isNull(value):
+				if index >= 0 {
+					base.Content = append(base.Content[:index], base.Content[index+2:]...)
+					a.record(OperationRemove, keyPath)
+				}
+			case index < 0:
+				base.Content = append(base.Content, key, withoutPatchKeys(value))
+				a.record(OperationAdd, keyPath)
+			default:
+				a.merge(base.Content[index+1], value, keyPath)
+			}
+		}
+	case base.Kind == yaml.SequenceNode && patch.Kind == yaml.SequenceNode && isNamedList(base) && isNamedList(patch):
+		for _, item := range patch.Content {
+			itemName := mappingValue(item, nameKey).Value
+			index := namedIndex(base, itemName)
+			deleted := mappingValue(item, patchKey) != nil && mappingValue(item, patchKey).Value == patchDelete
+			switch {
+			case deleted:
+				if index >= 0 {
+					base.Content = append(base.Content[:index], base.Content[index+1:]...)
+					a.record(OperationRemove, fmt.Sprintf("%s/%d", path, index))
+				}
+			case index < 0:
+				base.Content = append(base.Content, withoutPatchKeys(item))
+				a.record(OperationAdd, fmt.Sprintf("%s/%d", path, len(base.Content)-1))
+			default:
+				a.merge(base.Content[index], item, fmt.Sprintf("%s/%d", path, index))
+			}
+		}
+	default:
+		if equal(base, patch) {
+			return
+		}
+		*base = *withoutPatchKeys(patch)
+		a.record(OperationReplace, path)
+	}
+}
+
+func (a *applier) applyOperations(root, operations *yaml.Node) error {
+	for i, node := range operations.Content {
+		var op patchOperation
+		if err := node.Decode(&op); err != nil {
+			return errors.Wrapf(err, "invalid operation %d", i)
+		}
+		if err := a.applyOperation(root, op); err != nil {
+			return errors.Wrapf(err, "operation %d '%s %s' failed", i, op.Op, op.Path)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyOperation(root *yaml.Node, op patchOperation) error {
+	if op.Op != OperationRemove && op.Value.Kind == 0 {
+		return errors.New("missing 'value'")
+	}
+	segments, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return errors.New("the whole config can't be patched")
+	}
+	parent, err := resolve(root, segments[:len(segments)-1])
+	if err != nil {
+		return err
+	}
+	last := segments[len(segments)-1]
+	switch parent.Kind {
+	case yaml.MappingNode:
+		index := mappingIndex(parent, last)
+		switch op.Op {
+		case OperationAdd:
+			if index >= 0 {
+				parent.Content[index+1] = &op.Value
+			} else {
+				parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}, &op.Value)
+			}
+		case OperationRemove, OperationReplace:
+			if index < 0 {
+				return errors.Errorf("key '%s' does not exist", last)
+			}
+			if op.Op == OperationRemove {
+				parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+			} else {
+				parent.Content[index+1] = &op.Value
+			}
+		default:
+			return errors.Errorf("unsupported operation '%s', supported are 'add', 'remove' and 'replace'", op.Op)
+		}
+	case yaml.SequenceNode:
+		if op.Op == OperationAdd && last == "-" {
+			parent.Content = append(parent.Content, &op.Value)
+			break
+		}
+		index, err := strconv.Atoi(last)
+		if err != nil || index < 0 || index > len(parent.Content) || (op.Op != OperationAdd && index == len(parent.Content)) {
+			return errors.Errorf("index '%s' is out of range", last)
+		}
+		switch op.Op {
+		case OperationAdd:
+			parent.Content = append(parent.Content[:index], append([]*yaml.Node{&op.Value}, parent.Content[index:]...)...)
+		case OperationRemove:
+			parent.Content = append(parent.Content[:index], parent.Content[index+1:]...)
+		case OperationReplace:
+			parent.Content[index] = &op.Value
+		default:
+			return errors.Errorf("unsupported operation '%s', supported are 'add', 'remove' and 'replace'", op.Op)
+		}
+	default:
+		return errors.Errorf("'%s' is neither a mapping nor a list", strings.Join(segments[:len(segments)-1], "/"))
+	}
+	a.record(op.Op, op.Path)
+	return nil
+}
+
+// parsePointer splits a JSON pointer into its unescaped segments
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("path '%s' must start with '/'", pointer)
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments, nil
+}
+
+func escape(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
+
+func resolve(node *yaml.Node, segments []string) (*yaml.Node, error) {
+	for i, segment := range segments {
+		switch node.Kind {
+		case yaml.MappingNode:
+			value := mappingValue(node, segment)
+			if value == nil {
+				return nil, errors.Errorf("'/%s' does not exist", strings.Join(segments[:i+1], "/"))
+			}
+			node = value
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, errors.Errorf("'/%s' does not exist", strings.Join(segments[:i+1], "/"))
+			}
+			node = node.Content[index]
+		default:
+			return nil, errors.Errorf("'/%s' does not exist", strings.Join(segments[:i+1], "/"))
+		}
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+	}
+	return node, nil
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == 0 {
+		return nil
+	}
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return nil
+		}
+		return doc.Content[0]
+	}
+	return doc
+}
+
+func configVersion(root *yaml.Node) string {
+	metadata := mappingValue(root, "metadata")
+	if metadata == nil {
+		return ""
+	}
+	version := mappingValue(metadata, "version")
+	if version == nil {
+		return ""
+	}
+	return version.Value
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	index := mappingIndex(node, key)
+	if index < 0 {
+		return nil
+	}
+	return node.Content[index+1]
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// isNamedList returns true if all items of the list are mappings with a name
+func isNamedList(node *yaml.Node) bool {
+	for _, item := range node.Content {
+		name := mappingValue(item, nameKey)
+		if name == nil || name.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return len(node.Content) > 0
+}
+
+func namedIndex(list *yaml.Node, name string) int {
+	for i, item := range list.Content {
+		if mappingValue(item, nameKey).Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutPatchKeys removes the '$patch' directives of an overlay node before it is added to the config
+func withoutPatchKeys(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.MappingNode {
+		if index := mappingIndex(node, patchKey); index >= 0 {
+			node.Content = append(node.Content[:index], node.Content[index+2:]...)
+		}
+	}
+	for _, child := range node.Content {
+		withoutPatchKeys(child)
+	}
+	return node
+}
+
+func equal(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode && a.ShortTag() != b.ShortTag() {
+		return false
+	}
+	for i := range a.Content {
+		if !equal(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package overlay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseConfig = `metadata:
+  version: v2
+autopilots:
+  scanner:
+    run: scan
+    env:
+      LEVEL: high
+      TARGET: staging
+finalizers:
+  - name: html
+    run: html-finalizer
+  - name: tickets
+    run: ticket-creator
+chapters:
+  "1":
+    title: Security
+    requirements:
+      "1":
+        title: Scans
+        checks:
+          "1":
+            title: Vulnerabilities
+            automation:
+              autopilot: scanner
+          "2":
+            title: Penetration test
+            manual:
+              status: UNANSWERED
+              reason: not done yet
+`
+
+func TestApply(t *testing.T) {
+	testCases := map[string]struct {
+		overlay     string
+		want        string
+		wantChanges []Change
+	}{
+		"should merge a partial config": {
+			overlay: `autopilots:
+  scanner:
+    env:
+      TARGET: production
+      DEBUG: null
+chapters:
+  "1":
+    requirements:
+      "1":
+        checks:
+          "1": null
+          "2":
+            manual:
+              status: GREEN
+              reason: done by external company
+          "3":
+            title: Secrets
+            manual:
+              status: NA
+              reason: no secrets
+`,
+			want: `metadata:
+  version: v2
+autopilots:
+  scanner:
+    run: scan
+    env:
+      LEVEL: high
+      TARGET: production
+finalizers:
+  - name: html
+    run: html-finalizer
+  - name: tickets
+    run: ticket-creator
+chapters:
+  "1":
+    title: Security
+    requirements:
+      "1":
+        title: Scans
+        checks:
+          "2":
+            title: Penetration test
+            manual:
+              status: GREEN
+              reason: done by external company
+          "3":
+            title: Secrets
+            manual:
+              status: NA
+              reason: no secrets
+`,
+			wantChanges: []Change{
+				{Overlay: "prod.yaml", Operation: "replace", Path: "/autopilots/scanner/env/TARGET"},
+				{Overlay: "prod.yaml", Operation: "remove", Path: "/chapters/1/requirements/1/checks/1"},
+				{Overlay: "prod.yaml", Operation: "replace", Path: "/chapters/1/requirements/1/checks/2/manual/status"},
+				{Overlay: "prod.yaml", Operation: "replace", Path: "/chapters/1/requirements/1/checks/2/manual/reason"},
+				{Overlay: "prod.yaml", Operation: "add", Path: "/chapters/1/requirements/1/checks/3"},
+			},
+		},
+		"should merge lists by name": {
+			overlay: `finalizers:
+  - name: html
+    $patch: delete
+  - name: tickets
+    run: ticket-creator --dry-run
+  - name: mail
+    run: mailer
+`,
+			want: `metadata:
+  version: v2
+autopilots:
+  scanner:
+    run: scan
+    env:
+      LEVEL: high
+      TARGET: staging
+finalizers:
+  - name: tickets
+    run: ticket-creator --dry-run
+  - name: mail
+    run: mailer
+chapters:
+  "1":
+    title: Security
+    requirements:
+      "1":
+        title: Scans
+        checks:
+          "1":
+            title: Vulnerabilities
+            automation:
+              autopilot: scanner
+          "2":
+            title: Penetration test
+            manual:
+              status: UNANSWERED
+              reason: not done yet
+`,
+			wantChanges: []Change{
+				{Overlay: "prod.yaml", Operation: "remove", Path: "/finalizers/0"},
+				{Overlay: "prod.yaml", Operation: "replace", Path: "/finalizers/0/run"},
+				{Overlay: "prod.yaml", Operation: "add", Path: "/finalizers/1"},
+			},
+		},
+		"should apply JSON patch operations": {
+			overlay: `- op: replace
+  path: /autopilots/scanner/env/LEVEL
+  value: low
+- op: remove
+  path: /finalizers/1
+- op: add
+  path: /finalizers/-
+  value:
+    name: mail
+    run: mailer
+- op: remove
+  path: /chapters/1/requirements/1/checks/2
+`,
+			want: `metadata:
+  version: v2
+autopilots:
+  scanner:
+    run: scan
+    env:
+      LEVEL: low
+      TARGET: staging
+finalizers:
+  - name: html
+    run: html-finalizer
+  - name: mail
+    run: mailer
+chapters:
+  "1":
+    title: Security
+    requirements:
+      "1":
+        title: Scans
+        checks:
+          "1":
+            title: Vulnerabilities
+            automation:
+              autopilot: scanner
+`,
+			wantChanges: []Change{
+				{Overlay: "prod.yaml", Operation: "replace", Path: "/autopilots/scanner/env/LEVEL"},
+				{Overlay: "prod.yaml", Operation: "remove", Path: "/finalizers/1"},
+				{Overlay: "prod.yaml", Operation: "add", Path: "/finalizers/-"},
+				{Overlay: "prod.yaml", Operation: "remove", Path: "/chapters/1/requirements/1/checks/2"},
+			},
+		},
+		"should not change anything for an empty overlay": {
+			want: baseConfig,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, changes, err := Apply([]byte(baseConfig), "prod.yaml", []byte(tc.overlay))
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+			assert.Equal(t, tc.wantChanges, changes)
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	testCases := map[string]struct {
+		base    string
+		overlay string
+		want    string
+	}{
+		"should only patch v2 configs": {
+			base:    "metadata:\n  version: v1\n",
+			overlay: "header:\n  name: test\n",
+			want:    "invalid config file version: config of version 'v1' can't be patched, overlays are only supported for version 'v2'",
+		},
+		"should fail for missing paths": {
+			base:    baseConfig,
+			overlay: "- op: replace\n  path: /chapters/2/title\n  value: other\n",
+			want:    "invalid overlay: error applying overlay prod.yaml: operation 0 'replace /chapters/2/title' failed: '/chapters/2' does not exist",
+		},
+		"should fail for unsupported operations": {
+			base:    baseConfig,
+			overlay: "- op: move\n  path: /header\n  value: x\n",
+			want:    "invalid overlay: error applying overlay prod.yaml: operation 0 'move /header' failed: unsupported operation 'move', supported are 'add', 'remove' and 'replace'",
+		},
+		"should fail for scalar overlays": {
+			base:    baseConfig,
+			overlay: "just a string",
+			want:    "invalid overlay: error applying overlay prod.yaml: overlay must be a yaml mapping or a list of JSON patch operations",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Apply([]byte(tc.base), "prod.yaml", []byte(tc.overlay))
+
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestApplyFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "base-deviations.yaml")
+	second := filepath.Join(dir, "prod.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("autopilots:\n  scanner:\n    env:\n      TARGET: production\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("- op: replace\n  path: /autopilots/scanner/env/TARGET\n  value: production-eu\n"), 0644))
+
+	got, changes, err := ApplyFiles([]byte(baseConfig), []string{first, second})
+
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "TARGET: production-eu")
+	assert.Equal(t, []Change{
+		{Overlay: first, Operation: "replace", Path: "/autopilots/scanner/env/TARGET"},
+		{Overlay: second, Operation: "replace", Path: "/autopilots/scanner/env/TARGET"},
+	}, changes)
+
+	_, _, err = ApplyFiles([]byte(baseConfig), []string{filepath.Join(dir, "missing.yaml")})
+	assert.ErrorContains(t, err, "error reading overlay file")
+}
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package portfolio combines the results of the projects of a multi-project run.
+package portfolio
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/result"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	version     = "v1"
+	errorStatus = "ERROR"
+	naStatus    = "NA"
+)
+
+// Contains the combined result of all projects of a run
+type Portfolio struct {
+	// Metadata of the portfolio
+	Metadata Metadata `yaml:"metadata" json:"metadata"`
+	// Header of the portfolio
+	Header Header `yaml:"header" json:"header"`
+	// Overall status of the portfolio (is composed of the status of the projects)
+	OverallStatus string `yaml:"overallStatus" json:"overallStatus"`
+	// Statistics of the portfolio
+	Statistics Statistics `yaml:"statistics" json:"statistics"`
+	// Projects in the order of execution
+	Projects []Project `yaml:"projects" json:"projects"`
+}
+
+// Contains the metadata of the portfolio
+type Metadata struct {
+	// Version of the portfolio format
+	// Example "v1"
+	Version string `yaml:"version" json:"version"`
+}
+
+// Contains the header of the portfolio
+type Header struct {
+	// Date of the run
+	// Example "2023-08-03T16:16:00+02:00"
+	Date string `yaml:"date" json:"date"`
+	// Version of the tool
+	// Example "0.1.0"
+	ToolVersion string `yaml:"toolVersion" json:"toolVersion"`
+}
+
+// Contains the statistics summed up over all projects
+type Statistics struct {
+	// Number of projects
+	CountProjects uint `yaml:"counted-projects" json:"counted-projects"`
+	// Number of projects per overall status
+	CountProjectsByStatus map[string]uint `yaml:"counted-projects-by-status" json:"counted-projects-by-status"`
+	// Number of checks
+	CountChecks uint `yaml:"counted-checks" json:"counted-checks"`
+	// Number of automated checks
+	CountAutomatedChecks uint `yaml:"counted-automated-checks" json:"counted-automated-checks"`
+	// Number of manual checks (excluding unanswered and skipped)
+	CountManualChecks uint `yaml:"counted-manual-check" json:"counted-manual-check"`
+	// Number of unanswered checks
+	CountUnansweredChecks uint `yaml:"counted-unanswered-checks" json:"counted-unanswered-checks"`
+	// Number of skipped checks
+	CountSkippedChecks uint `yaml:"counted-skipped-checks" json:"counted-skipped-checks"`
+	// Number of checks per status
+	CountChecksByStatus map[string]uint `yaml:"counted-checks-by-status,omitempty" json:"counted-checks-by-status,omitempty"`
+	// Number of criteria which are not fulfilled
+	CountFailedCriteria uint `yaml:"counted-failed-criteria,omitempty" json:"counted-failed-criteria,omitempty"`
+}
+
+// Contains the outcome of a single project
+type Project struct {
+	// Name of the project, unique within the portfolio
+	Name string `yaml:"name" json:"name"`
+	// Input folder of the project
+	Path string `yaml:"path" json:"path"`
+	// Path of the result file of the project, relative to the portfolio
+	ResultFile string `yaml:"resultFile,omitempty" json:"resultFile,omitempty"`
+	// Name of the quality gate of the project
+	QgName string `yaml:"qgName,omitempty" json:"qgName,omitempty"`
+	// Version of the quality gate of the project
+	QgVersion string `yaml:"qgVersion,omitempty" json:"qgVersion,omitempty"`
+	// Overall status of the project, ERROR if the project could not be run
+	OverallStatus string `yaml:"overallStatus" json:"overallStatus"`
+	// Statistics of the project
+	Statistics *ProjectStatistics `yaml:"statistics,omitempty" json:"statistics,omitempty"`
+	// Error which prevented a complete run of the project
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// Contains the statistics of a single project
+type ProjectStatistics struct {
+	CountChecks           uint    `yaml:"counted-checks" json:"counted-checks"`
+	CountAutomatedChecks  uint    `yaml:"counted-automated-checks" json:"counted-automated-checks"`
+	CountManualChecks     uint    `yaml:"counted-manual-check" json:"counted-manual-check"`
+	CountUnansweredChecks uint    `yaml:"counted-unanswered-checks" json:"counted-unanswered-checks"`
+	CountSkippedChecks    uint    `yaml:"counted-skipped-checks" json:"counted-skipped-checks"`
+	PercentageAutomated   float64 `yaml:"degree-of-automation" json:"degree-of-automation"`
+	PercentageDone        float64 `yaml:"degree-of-completion" json:"degree-of-completion"`
+	// only contained in v2 results
+	CountChecksByStatus map[string]uint `yaml:"counted-checks-by-status,omitempty" json:"counted-checks-by-status,omitempty"`
+	CountFailedCriteria uint            `yaml:"counted-failed-criteria,omitempty" json:"counted-failed-criteria,omitempty"`
+}
+
+// projectResult contains the parts of a result file which are shared by the v1 and v2 result format
+type projectResult struct {
+	Header struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"header"`
+	OverallStatus string            `yaml:"overallStatus"`
+	Statistics    ProjectStatistics `yaml:"statistics"`
+}
+
+// LoadProject reads the outcome of a project from its result file.
+// If the project failed with runErr, it is kept as error of the project.
+func LoadProject(name, path, resultFile, relativeResultFile string, runErr error) Project {
+	project := Project{Name: name, Path: path}
+	if runErr != nil {
+		project.OverallStatus = errorStatus
+		project.Error = runErr.Error()
+	}
+	content, err := os.ReadFile(resultFile)
+	if err != nil {
+		if runErr == nil {
+			project.OverallStatus = errorStatus
+			project.Error = errors.Wrap(err, "error reading result file").Error()
+		}
+		return project
+	}
+	var res projectResult
+	err = yaml.Unmarshal(content, &res)
+	if err != nil {
+		if runErr == nil {
+			project.OverallStatus = errorStatus
+			project.Error = errors.Wrap(err, "error parsing result file").Error()
+		}
+		return project
+	}
+	project.ResultFile = relativeResultFile
+	project.QgName = res.Header.Name
+	project.QgVersion = res.Header.Version
+	project.Statistics = &res.Statistics
+	if runErr == nil {
+		project.OverallStatus = res.OverallStatus
+	}
+	return project
+}
+
+// New combines the projects to a portfolio
+func New(projects []Project) Portfolio {
+	portfolio := Portfolio{
+		Metadata: Metadata{Version: version},
+		Header: Header{
+			Date:        time.Now().Local().Format(time.RFC3339),
+			ToolVersion: helper.ToolVersion,
+		},
+		Statistics: Statistics{CountProjectsByStatus: make(map[string]uint)},
+		Projects:   projects,
+	}
+	for _, project := range projects {
+		portfolio.OverallStatus = result.PriorityStatus(portfolio.OverallStatus, project.OverallStatus)
+		portfolio.Statistics.CountProjects++
+		portfolio.Statistics.CountProjectsByStatus[project.OverallStatus]++
+		if project.Statistics == nil {
+			continue
+		}
+		portfolio.Statistics.CountChecks += project.Statistics.CountChecks
+		portfolio.Statistics.CountAutomatedChecks += project.Statistics.CountAutomatedChecks
+		portfolio.Statistics.CountManualChecks += project.Statistics.CountManualChecks
+		portfolio.Statistics.CountUnansweredChecks += project.Statistics.CountUnansweredChecks
+		portfolio.Statistics.CountSkippedChecks += project.Statistics.CountSkippedChecks
+		portfolio.Statistics.CountFailedCriteria += project.Statistics.CountFailedCriteria
+		for status, count := range project.Statistics.CountChecksByStatus {
+			if portfolio.Statistics.CountChecksByStatus == nil {
+				portfolio.Statistics.CountChecksByStatus = make(map[string]uint)
+			}
+			portfolio.Statistics.CountChecksByStatus[status] += count
+		}
+	}
+	if portfolio.OverallStatus == "" {
+		portfolio.OverallStatus = naStatus
+	}
+	return portfolio
+}
+
+// Statuses returns the overall statuses of the projects ordered by severity
+func (p Portfolio) Statuses() []string {
+	statuses := make([]string, 0, len(p.Statistics.CountProjectsByStatus))
+	for status := range p.Statistics.CountProjectsByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return result.PriorityStatus(statuses[i], statuses[j]) == statuses[i] && statuses[i] != statuses[j]
+	})
+	return statuses
+}
+
+// WriteFile writes the portfolio as YAML to path
+func WriteFile(p Portfolio, path string) error {
+	content, err := yaml.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal portfolio")
+	}
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write portfolio to '%s'", path)
+	}
+	return nil
+}
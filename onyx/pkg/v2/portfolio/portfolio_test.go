@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package portfolio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	resultFile := filepath.Join(dir, "qg-result.yaml")
+	err := os.WriteFile(resultFile, []byte(`metadata:
+  version: v2
+header:
+  name: qg
+  version: "1.0"
+overallStatus: RED
+statistics:
+  counted-checks: 4
+  counted-automated-checks: 3
+  counted-manual-check: 1
+  degree-of-automation: 75
+  degree-of-completion: 100
+`), 0644)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		resultFile string
+		runErr     error
+		want       Project
+	}{
+		"should read status and statistics from result file": {
+			resultFile: resultFile,
+			want: Project{
+				Name:          "a",
+				Path:          "input/a",
+				ResultFile:    "a/qg-result.yaml",
+				QgName:        "qg",
+				QgVersion:     "1.0",
+				OverallStatus: "RED",
+				Statistics: &ProjectStatistics{
+					CountChecks:          4,
+					CountAutomatedChecks: 3,
+					CountManualChecks:    1,
+					PercentageAutomated:  75,
+					PercentageDone:       100,
+				},
+			},
+		},
+		"should return error status if result file is missing": {
+			resultFile: filepath.Join(dir, "missing.yaml"),
+			want: Project{
+				Name:          "a",
+				Path:          "input/a",
+				OverallStatus: "ERROR",
+				Error:         "error reading result file: open " + filepath.Join(dir, "missing.yaml") + ": no such file or directory",
+			},
+		},
+		"should keep run error even if result file exists": {
+			resultFile: resultFile,
+			runErr:     errors.New("finalizer failed"),
+			want: Project{
+				Name:          "a",
+				Path:          "input/a",
+				ResultFile:    "a/qg-result.yaml",
+				QgName:        "qg",
+				QgVersion:     "1.0",
+				OverallStatus: "ERROR",
+				Statistics: &ProjectStatistics{
+					CountChecks:          4,
+					CountAutomatedChecks: 3,
+					CountManualChecks:    1,
+					PercentageAutomated:  75,
+					PercentageDone:       100,
+				},
+				Error: "finalizer failed",
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			project := LoadProject("a", "input/a", tc.resultFile, "a/qg-result.yaml", tc.runErr)
+			assert.Equal(t, tc.want, project)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		projects   []Project
+		wantStatus string
+		wantStats  Statistics
+	}{
+		"should be NA without projects": {
+			wantStatus: "NA",
+			wantStats:  Statistics{CountProjectsByStatus: map[string]uint{}},
+		},
+		"should use most severe status and sum up statistics": {
+			projects: []Project{
+				{Name: "a", OverallStatus: "GREEN", Statistics: &ProjectStatistics{CountChecks: 2, CountAutomatedChecks: 2}},
+				{Name: "b", OverallStatus: "RED", Statistics: &ProjectStatistics{CountChecks: 3, CountAutomatedChecks: 1, CountManualChecks: 1, CountUnansweredChecks: 1,
+					CountChecksByStatus: map[string]uint{"RED": 2, "UNANSWERED": 1}, CountFailedCriteria: 2}},
+				{Name: "c", OverallStatus: "GREEN", Statistics: &ProjectStatistics{CountChecks: 1, CountSkippedChecks: 1, CountChecksByStatus: map[string]uint{"SKIPPED": 1}}},
+			},
+			wantStatus: "RED",
+			wantStats: Statistics{
+				CountProjects:         3,
+				CountProjectsByStatus: map[string]uint{"GREEN": 2, "RED": 1},
+				CountChecks:           6,
+				CountAutomatedChecks:  3,
+				CountManualChecks:     1,
+				CountUnansweredChecks: 1,
+				CountSkippedChecks:    1,
+				CountChecksByStatus:   map[string]uint{"RED": 2, "UNANSWERED": 1, "SKIPPED": 1},
+				CountFailedCriteria:   2,
+			},
+		},
+		"should be ERROR if a project failed": {
+			projects: []Project{
+				{Name: "a", OverallStatus: "RED", Statistics: &ProjectStatistics{CountChecks: 1}},
+				{Name: "b", OverallStatus: "ERROR", Error: "failed"},
+			},
+			wantStatus: "ERROR",
+			wantStats: Statistics{
+				CountProjects:         2,
+				CountProjectsByStatus: map[string]uint{"RED": 1, "ERROR": 1},
+				CountChecks:           1,
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := New(tc.projects)
+			assert.Equal(t, "v1", p.Metadata.Version)
+			assert.NotEmpty(t, p.Header.Date)
+			assert.Equal(t, tc.wantStatus, p.OverallStatus)
+			assert.Equal(t, tc.wantStats, p.Statistics)
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	p := New([]Project{
+		{Name: "a", QgName: "qg", QgVersion: "1.0", ResultFile: "a/qg-result.yaml", OverallStatus: "GREEN",
+			Statistics: &ProjectStatistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageAutomated: 100, PercentageDone: 100, CountFailedCriteria: 1}},
+		{Name: "b", OverallStatus: "ERROR", Error: "error reading files"},
+	})
+	p.Header.Date = "2024-06-01T12:00:00Z"
+
+	report := Report(p)
+
+	assert.Equal(t, `# Portfolio Report
+
+Date: 2024-06-01T12:00:00Z
+
+Overall status: **ERROR**
+
+Projects: 2 (1 ERROR, 1 GREEN)
+
+Checks: 2 (2 automated, 0 manual, 0 unanswered, 0 skipped)
+
+Failed criteria: 1
+
+| Project | Quality Gate | Status | Checks | Automated | Done | Result |
+| --- | --- | --- | --- | --- | --- | --- |
+| a | qg (1.0) | GREEN | 2 | 100.00% | 100.00% | [a/qg-result.yaml](a/qg-result.yaml) |
+| b |  | ERROR | - | - | - | - |
+
+## Errors
+
+- **b**: error reading files
+`, report)
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package portfolio
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Report renders the portfolio as a Markdown overview of all projects
+func Report(p Portfolio) string {
+	var b strings.Builder
+	b.WriteString("# Portfolio Report\n\n")
+	fmt.Fprintf(&b, "Date: %s\n\n", p.Header.Date)
+	fmt.Fprintf(&b, "Overall status: **%s**\n\n", p.OverallStatus)
+
+	var counts []string
+	for _, status := range p.Statuses() {
+		counts = append(counts, fmt.Sprintf("%d %s", p.Statistics.CountProjectsByStatus[status], status))
+	}
+	fmt.Fprintf(&b, "Projects: %d", p.Statistics.CountProjects)
+	if len(counts) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(counts, ", "))
+	}
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "Checks: %d (%d automated, %d manual, %d unanswered, %d skipped)\n\n",
+		p.Statistics.CountChecks,
+		p.Statistics.CountAutomatedChecks,
+		p.Statistics.CountManualChecks,
+		p.Statistics.CountUnansweredChecks,
+		p.Statistics.CountSkippedChecks,
+	)
+	if p.Statistics.CountFailedCriteria > 0 {
+		fmt.Fprintf(&b, "Failed criteria: %d\n\n", p.Statistics.CountFailedCriteria)
+	}
+
+	b.WriteString("| Project | Quality Gate | Status | Checks | Automated | Done | Result |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, project := range p.Projects {
+		qg := project.QgName
+		if project.QgVersion != "" {
+			qg = fmt.Sprintf("%s (%s)", project.QgName, project.QgVersion)
+		}
+		checks, automated, done := "-", "-", "-"
+		if project.Statistics != nil {
+			checks = fmt.Sprint(project.Statistics.CountChecks)
+			automated = fmt.Sprintf("%.2f%%", project.Statistics.PercentageAutomated)
+			done = fmt.Sprintf("%.2f%%", project.Statistics.PercentageDone)
+		}
+		resultFile := "-"
+		if project.ResultFile != "" {
+			resultFile = fmt.Sprintf("[%s](%s)", project.ResultFile, project.ResultFile)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			escape(project.Name), escape(qg), project.OverallStatus, checks, automated, done, resultFile)
+	}
+
+	var failed []Project
+	for _, project := range p.Projects {
+		if project.Error != "" {
+			failed = append(failed, project)
+		}
+	}
+	if len(failed) > 0 {
+		b.WriteString("\n## Errors\n\n")
+		for _, project := range failed {
+			fmt.Fprintf(&b, "- **%s**: %s\n", escape(project.Name), escape(project.Error))
+		}
+	}
+	return b.String()
+}
+
+// WriteReport writes the Markdown report of the portfolio to path
+func WriteReport(p Portfolio, path string) error {
+	err := os.WriteFile(path, []byte(Report(p)), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write portfolio report to '%s'", path)
+	}
+	return nil
+}
+
+func escape(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package provenance describes what produced the result of a run.
+// The provenance is an in-toto statement with a SLSA provenance predicate,
+// the installed apps are additionally listed in a CycloneDX SBOM.
+package provenance
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/pkg/errors"
+)
+
+const (
+	PROVENANCE_FILE = "provenance.json"
+	SBOM_FILE       = "sbom.cdx.json"
+
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://slsa.dev/provenance/v1"
+	BuildType     = "https://github.com/B-S-F/yaku/onyx/exec/v1"
+	BuilderID     = "https://github.com/B-S-F/yaku/onyx"
+	digestSHA256  = "sha256"
+)
+
+// App is an app installed for the run
+type App struct {
+	Repository string
+	Name       string
+	Version    string
+	// SHA-256 checksum of the executable
+	Checksum string
+}
+
+// Run contains everything about a run which is recorded in the provenance
+type Run struct {
+	// Content of the config file
+	Config []byte
+	// Variables of the run, secrets are never recorded
+	Vars  map[string]string
+	Apps  []App
+	Flags map[string]string
+	// Result file which is the subject of the provenance
+	ResultFile string
+	StartedOn  time.Time
+	FinishedOn time.Time
+}
+
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+type ExternalParameters struct {
+	Config ResourceDescriptor `json:"config"`
+	Vars   ResourceDescriptor `json:"vars"`
+	Flags  map[string]string  `json:"flags,omitempty"`
+}
+
+type InternalParameters struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type RunDetails struct {
+	Builder  Builder  `json:"builder"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+type Metadata struct {
+	StartedOn  string `json:"startedOn"`
+	FinishedOn string `json:"finishedOn"`
+}
+
+// New creates the provenance statement of a run
+func New(run Run) (*Statement, error) {
+	resultDigest, err := fileDigest(run.ResultFile)
+	if err != nil {
+		return nil, err
+	}
+	varsDigest, err := varsDigest(run.Vars)
+	if err != nil {
+		return nil, err
+	}
+	statement := &Statement{
+		Type: StatementType,
+		Subject: []Subject{{
+			Name:   filepath.Base(run.ResultFile),
+			Digest: map[string]string{digestSHA256: resultDigest},
+		}},
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: BuildType,
+				ExternalParameters: ExternalParameters{
+					Config: ResourceDescriptor{Digest: map[string]string{digestSHA256: digest(run.Config)}},
+					Vars:   ResourceDescriptor{Digest: map[string]string{digestSHA256: varsDigest}},
+					Flags:  run.Flags,
+				},
+				InternalParameters: InternalParameters{OS: runtime.GOOS, Arch: runtime.GOARCH},
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					ID:      BuilderID,
+					Version: map[string]string{"onyx": helper.ToolVersion},
+				},
+				Metadata: Metadata{
+					StartedOn:  run.StartedOn.UTC().Format(time.RFC3339),
+					FinishedOn: run.FinishedOn.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}
+	for _, app := range sortedApps(run.Apps) {
+		statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, ResourceDescriptor{
+			Name: app.Name,
+			URI:  purl(app),
+			Digest: map[string]string{
+				digestSHA256: app.Checksum,
+			},
+			Annotations: map[string]string{
+				"repository": app.Repository,
+				"version":    app.Version,
+			},
+		})
+	}
+	return statement, nil
+}
+
+// varsDigest hashes the variables as JSON with sorted keys, so the digest does not depend on the formatting of the vars file
+func varsDigest(vars map[string]string) (string, error) {
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(vars); err != nil {
+		return "", errors.Wrap(err, "failed to marshal vars")
+	}
+	return digest(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+func digest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+func fileDigest(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read '%s'", path)
+	}
+	return digest(content), nil
+}
+
+func sortedApps(apps []App) []App {
+	sorted := append([]App(nil), apps...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Repository != sorted[j].Repository {
+			return sorted[i].Repository < sorted[j].Repository
+		}
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// WriteFile writes v as indented JSON to path
+func WriteFile(v interface{}, path string) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal document")
+	}
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write '%s'", path)
+	}
+	return nil
+}
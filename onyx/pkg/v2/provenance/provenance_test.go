@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package provenance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testApps = []App{
+	{Repository: "repo-b", Name: "sharepoint", Version: "1.2.0", Checksum: "bbb"},
+	{Repository: "repo-a", Name: "jira", Version: "0.4.1", Checksum: "aaa"},
+}
+
+func TestNew(t *testing.T) {
+	resultFile := filepath.Join(t.TempDir(), "qg-result.yaml")
+	require.NoError(t, os.WriteFile(resultFile, []byte("overallStatus: GREEN\n"), 0644))
+	helper.ToolVersion = "1.0.0"
+	startedOn := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	statement, err := New(Run{
+		Config:     []byte("metadata:\n  version: v2\n"),
+		Vars:       map[string]string{"B": "2", "A": "1"},
+		Apps:       testApps,
+		Flags:      map[string]string{"check-timeout": "60"},
+		ResultFile: resultFile,
+		StartedOn:  startedOn,
+		FinishedOn: startedOn.Add(time.Minute),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, PredicateType, statement.PredicateType)
+	assert.Equal(t, []Subject{{
+		Name:   "qg-result.yaml",
+		Digest: map[string]string{"sha256": digest([]byte("overallStatus: GREEN\n"))},
+	}}, statement.Subject)
+	definition := statement.Predicate.BuildDefinition
+	assert.Equal(t, digest([]byte("metadata:\n  version: v2\n")), definition.ExternalParameters.Config.Digest["sha256"])
+	assert.Equal(t, digest([]byte(`{"A":"1","B":"2"}`)), definition.ExternalParameters.Vars.Digest["sha256"])
+	assert.Equal(t, map[string]string{"check-timeout": "60"}, definition.ExternalParameters.Flags)
+	assert.Equal(t, InternalParameters{OS: runtime.GOOS, Arch: runtime.GOARCH}, definition.InternalParameters)
+	assert.Equal(t, []ResourceDescriptor{
+		{
+			Name:        "jira",
+			URI:         "pkg:generic/jira@0.4.1?repository=repo-a",
+			Digest:      map[string]string{"sha256": "aaa"},
+			Annotations: map[string]string{"repository": "repo-a", "version": "0.4.1"},
+		},
+		{
+			Name:        "sharepoint",
+			URI:         "pkg:generic/sharepoint@1.2.0?repository=repo-b",
+			Digest:      map[string]string{"sha256": "bbb"},
+			Annotations: map[string]string{"repository": "repo-b", "version": "1.2.0"},
+		},
+	}, definition.ResolvedDependencies)
+	assert.Equal(t, RunDetails{
+		Builder:  Builder{ID: BuilderID, Version: map[string]string{"onyx": "1.0.0"}},
+		Metadata: Metadata{StartedOn: "2024-06-01T12:00:00Z", FinishedOn: "2024-06-01T12:01:00Z"},
+	}, statement.Predicate.RunDetails)
+}
+
+func TestNewFailsWithoutResultFile(t *testing.T) {
+	_, err := New(Run{ResultFile: filepath.Join(t.TempDir(), "missing.yaml")})
+
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestNewSBOM(t *testing.T) {
+	helper.ToolVersion = "1.0.0"
+
+	bom := NewSBOM(testApps, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.True(t, strings.HasPrefix(bom.SerialNumber, "urn:uuid:"))
+	assert.Equal(t, "2024-06-01T12:00:00Z", bom.Metadata.Timestamp)
+	assert.Equal(t, []Component{{Type: "application", Name: "onyx", Version: "1.0.0"}}, bom.Metadata.Tools.Components)
+	require.Len(t, bom.Components, 2)
+	assert.Equal(t, Component{
+		Type:       "application",
+		BOMRef:     "pkg:generic/jira@0.4.1?repository=repo-a",
+		Name:       "jira",
+		Version:    "0.4.1",
+		PURL:       "pkg:generic/jira@0.4.1?repository=repo-a",
+		Hashes:     []Hash{{Alg: "SHA-256", Content: "aaa"}},
+		Properties: []Property{{Name: "onyx:repository", Value: "repo-a"}},
+	}, bom.Components[0])
+
+	content, err := json.Marshal(NewSBOM(nil, time.Now()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"components":[]`)
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package provenance
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/google/uuid"
+)
+
+// BOM is a CycloneDX 1.5 software bill of materials of the installed apps
+type BOM struct {
+	BOMFormat    string      `json:"bomFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	SerialNumber string      `json:"serialNumber"`
+	Version      int         `json:"version"`
+	Metadata     BOMMetadata `json:"metadata"`
+	Components   []Component `json:"components"`
+}
+
+type BOMMetadata struct {
+	Timestamp string   `json:"timestamp"`
+	Tools     BOMTools `json:"tools"`
+}
+
+type BOMTools struct {
+	Components []Component `json:"components"`
+}
+
+type Component struct {
+	Type       string     `json:"type"`
+	BOMRef     string     `json:"bom-ref,omitempty"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	PURL       string     `json:"purl,omitempty"`
+	Hashes     []Hash     `json:"hashes,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+type Hash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewSBOM lists the installed apps of a run
+func NewSBOM(apps []App, timestamp time.Time) *BOM {
+	bom := &BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: BOMMetadata{
+			Timestamp: timestamp.UTC().Format(time.RFC3339),
+			Tools: BOMTools{Components: []Component{{
+				Type:    "application",
+				Name:    "onyx",
+				Version: helper.ToolVersion,
+			}}},
+		},
+		Components: []Component{},
+	}
+	for _, app := range sortedApps(apps) {
+		bom.Components = append(bom.Components, Component{
+			Type:    "application",
+			BOMRef:  purl(app),
+			Name:    app.Name,
+			Version: app.Version,
+			PURL:    purl(app),
+			Hashes:  []Hash{{Alg: "SHA-256", Content: app.Checksum}},
+			Properties: []Property{
+				{Name: "onyx:repository", Value: app.Repository},
+			},
+		})
+	}
+	return bom
+}
+
+// purl identifies an app as generic package url, qualified by the repository it was installed from
+func purl(app App) string {
+	return fmt.Sprintf("pkg:generic/%s@%s?repository=%s",
+		url.PathEscape(app.Name), url.PathEscape(app.Version), url.QueryEscape(app.Repository))
+}
@@ -117,6 +117,26 @@ func (r *Runner) replaceInitialExecutionPlan(varType string) {
 	if r.ep.Finalize != nil {
 		r.replaceFinalizeItem(r.ep.Finalize, varType)
 	}
+
+	for i := range r.ep.Services {
+		r.replaceService(&r.ep.Services[i], varType)
+	}
+
+	for i := range r.ep.Setup {
+		r.replaceFinalizeItem(&r.ep.Setup[i], varType)
+	}
+
+	for i := range r.ep.Teardown {
+		r.replaceFinalizeItem(&r.ep.Teardown[i], varType)
+	}
+
+	for i := range r.ep.Finalizers {
+		r.replaceFinalizeItem(&r.ep.Finalizers[i], varType)
+	}
+
+	for i := range r.ep.Notifications {
+		r.replaceNotification(&r.ep.Notifications[i], varType)
+	}
 }
 
 func (r *Runner) replaceManualItem(item *model.ManualCheck, varType string) {
@@ -277,6 +297,55 @@ func (r *Runner) replaceFinalizeItem(item *model.Finalize, varType string) {
 	}
 }
 
+func (r *Runner) replaceService(item *model.Service, varType string) {
+	// ${{ env.VAR }} variables do only get replaced by global env variables
+	if e := r.replacer.Env(&item.Env, buildEnvironmentList(*r.variables)); e != nil {
+		err := fmt.Errorf("error replacing variables in Service.Env: %w", e)
+		r.logger.UserError(err.Error())
+	}
+	var serviceEnv map[string]string
+	if varType == "env" {
+		serviceEnv = buildEnvironment(*r.variables, item.Env)
+	} else {
+		serviceEnv = *r.variables
+	}
+	if e := r.replacer.Map(&item.Env, serviceEnv); e != nil {
+		err := fmt.Errorf("error replacing '%s' in Service '%s': %w", varType, item.Name, e)
+		r.logger.UserError(err.Error())
+	}
+	for _, field := range []*string{&item.Run, &item.HealthCheck} {
+		replaced, e := r.replacer.String(*field, serviceEnv)
+		if e != nil {
+			err := fmt.Errorf("error replacing '%s' in Service '%s': %w", varType, item.Name, e)
+			r.logger.UserError(err.Error())
+		}
+		*field = replaced
+	}
+}
+
+func (r *Runner) replaceNotification(item *model.Notification, varType string) {
+	// ${{ env.VAR }} variables do only get replaced by global env variables
+	if e := r.replacer.Struct(item, *r.variables); e != nil {
+		err := fmt.Errorf("error replacing '%s' in Notification '%s': %w", varType, item.Name, e)
+		r.logger.UserError(err.Error())
+	}
+	if item.SMTP == nil {
+		return
+	}
+	if e := r.replacer.Struct(item.SMTP, *r.variables); e != nil {
+		err := fmt.Errorf("error replacing '%s' in Notification '%s': %w", varType, item.Name, e)
+		r.logger.UserError(err.Error())
+	}
+	for i, to := range item.SMTP.To {
+		replaced, e := r.replacer.String(to, *r.variables)
+		if e != nil {
+			err := fmt.Errorf("error replacing '%s' in Notification '%s': %w", varType, item.Name, e)
+			r.logger.UserError(err.Error())
+		}
+		item.SMTP.To[i] = replaced
+	}
+}
+
 // TODO(YANI): until now config contents were replaced at this point. I will continue it for the ease but we may rework this.
 func (r *Runner) replaceConfigValues(varType string) {
 	r.logger.Info(fmt.Sprintf("replacing '%s' variables in execution plan", varType))
@@ -323,18 +392,34 @@ func (r *Runner) replaceConfigValues(varType string) {
 	}
 
 	if r.ep.Finalize != nil {
-		var finalizeEnv map[string]string
-		if varType == "env" {
-			finalizeEnv = buildEnvironment(*r.variables, r.ep.Finalize.Env)
-		} else {
-			finalizeEnv = *r.variables
-		}
+		r.replaceFinalizeConfigValues(r.ep.Finalize, varType)
+	}
 
-		// replace Config values in Finalize
-		if e := r.replaceConfig(varType, &r.ep.Finalize.Configs, finalizeEnv); e != nil {
-			err := fmt.Errorf("error replacing '%s' in Finalize.Config: %w", varType, e)
-			r.logger.UserError(err.Error())
-		}
+	for i := range r.ep.Setup {
+		r.replaceFinalizeConfigValues(&r.ep.Setup[i], varType)
+	}
+
+	for i := range r.ep.Teardown {
+		r.replaceFinalizeConfigValues(&r.ep.Teardown[i], varType)
+	}
+
+	for i := range r.ep.Finalizers {
+		r.replaceFinalizeConfigValues(&r.ep.Finalizers[i], varType)
+	}
+}
+
+func (r *Runner) replaceFinalizeConfigValues(item *model.Finalize, varType string) {
+	var finalizeEnv map[string]string
+	if varType == "env" {
+		finalizeEnv = buildEnvironment(*r.variables, item.Env)
+	} else {
+		finalizeEnv = *r.variables
+	}
+
+	// replace Config values in Finalize
+	if e := r.replaceConfig(varType, &item.Configs, finalizeEnv); e != nil {
+		err := fmt.Errorf("error replacing '%s' in Finalize.Config: %w", varType, e)
+		r.logger.UserError(err.Error())
 	}
 }
 
@@ -237,6 +237,41 @@ func TestReplaceRun(t *testing.T) {
 	assert.Equal(t, `sharepoint-finalizer --config-file=config-file,config-file --output-dir=output-dir,output-dir override-finalize-env`, executionPlan.Finalize.Run, "finalize run should be equal")
 }
 
+func TestReplaceRunNotifications(t *testing.T) {
+	executionPlan := simpleExecPlan()
+	executionPlan.Notifications = []model.Notification{
+		{
+			Name:    "hook",
+			Type:    "webhook",
+			URL:     "https://example.com/${{ vars.VAR1 }}",
+			Headers: map[string]string{"Authorization": "Bearer ${{ secrets.SECRET1 }}"},
+		},
+		{
+			Name: "mail",
+			Type: "smtp",
+			SMTP: &model.SMTP{
+				Host:     "smtp.example.com",
+				Username: "${{ secrets.GITHUB_USERNAME }}",
+				Password: "${{ secrets.GITHUB_PASSWORD }}",
+				From:     "qg@example.com",
+				To:       []string{"${{ vars.VAR2 }}@example.com"},
+			},
+		},
+	}
+
+	Run(executionPlan, varsContent, secretsContent, Initial)
+
+	assert.Equal(t, "https://example.com/vars_value1", executionPlan.Notifications[0].URL)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer secrets_value1"}, executionPlan.Notifications[0].Headers)
+	assert.Equal(t, &model.SMTP{
+		Host:     "smtp.example.com",
+		Username: "github_username",
+		Password: "github_password",
+		From:     "qg@example.com",
+		To:       []string{"vars_value2@example.com"},
+	}, executionPlan.Notifications[1].SMTP)
+}
+
 func TestReplaceRunWithoutFinalizer(t *testing.T) {
 	executionPlan := simpleExecPlan()
 	executionPlan.Finalize = nil
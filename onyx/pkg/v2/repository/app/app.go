@@ -13,14 +13,12 @@ import (
 	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/repository/app"
 	"github.com/B-S-F/yaku/onyx/pkg/repository/registry"
-	"github.com/B-S-F/yaku/onyx/pkg/tempdir"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
 	"github.com/pkg/errors"
 )
 
-var APP_DIRECTORY = tempdir.GetPath("apps")
-
-func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry) error {
+// Initialize links the apps of the autopilots, setup and teardown steps and finalizers from the registry into their own folder in appDirectory.
+func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry, appDirectory string) error {
 	for i := range ep.AutopilotChecks {
 		autopilotItem := &ep.AutopilotChecks[i]
 		for _, configAppReference := range autopilotItem.AppReferences {
@@ -36,7 +34,7 @@ func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry) error {
 
 			appExecutablePath := app.ExecutablePath()
 			checkReference := fmt.Sprintf("%s_%s_%s", autopilotItem.Chapter.Id, autopilotItem.Requirement.Id, autopilotItem.Check.Id)
-			checkAppDirectory := filepath.Join(APP_DIRECTORY, checkReference)
+			checkAppDirectory := filepath.Join(appDirectory, checkReference)
 			err = os.MkdirAll(checkAppDirectory, 0755)
 			if err != nil {
 				return errors.Wrapf(err, "error creating directory for app %s for check %s", app.Reference(), checkReference)
@@ -51,6 +49,50 @@ func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry) error {
 			autopilotItem.AppPath = checkAppDirectory
 		}
 	}
+	for i := range ep.Setup {
+		if err := initializeFinalize(&ep.Setup[i], "setup", appRegistry, appDirectory); err != nil {
+			return err
+		}
+	}
+	for i := range ep.Teardown {
+		if err := initializeFinalize(&ep.Teardown[i], "teardown", appRegistry, appDirectory); err != nil {
+			return err
+		}
+	}
+	for i := range ep.Finalizers {
+		if err := initializeFinalize(&ep.Finalizers[i], "finalizer", appRegistry, appDirectory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeFinalize links the apps of a named finalizer, setup or teardown step into '<kind>_<name>' in appDirectory.
+func initializeFinalize(finalizer *model.Finalize, kind string, appRegistry *registry.Registry, appDirectory string) error {
+	for _, configAppReference := range finalizer.AppReferences {
+		appReference := &app.Reference{
+			Repository: configAppReference.Repository,
+			Name:       configAppReference.Name,
+			Version:    configAppReference.Version,
+		}
+		app, err := appRegistry.Get(appReference)
+		if err != nil {
+			return errors.Wrap(err, "error getting app")
+		}
+
+		finalizerAppDirectory := filepath.Join(appDirectory, kind+"_"+finalizer.Name)
+		err = os.MkdirAll(finalizerAppDirectory, 0755)
+		if err != nil {
+			return errors.Wrapf(err, "error creating directory for app %s for %s %s", app.Reference(), kind, finalizer.Name)
+		}
+		logger.Get().Infof("configured app %s with checksum %s for %s %s", app.Reference(), app.Checksum(), kind, finalizer.Name)
+
+		err = helper.CreateSymlinks(app.ExecutablePath(), finalizerAppDirectory, app.PossibleReferences())
+		if err != nil {
+			return errors.Wrap(err, "error creating symlinks")
+		}
+		finalizer.AppPath = finalizerAppDirectory
+	}
 	return nil
 }
 
@@ -65,5 +107,15 @@ func AppReferences(ep *model.ExecutionPlan) []*app.Reference {
 			})
 		}
 	}
+	finalizers := append(append(append([]model.Finalize{}, ep.Setup...), ep.Teardown...), ep.Finalizers...)
+	for _, finalizer := range finalizers {
+		for _, itemAppReference := range finalizer.AppReferences {
+			appReferences = append(appReferences, &app.Reference{
+				Repository: itemAppReference.Repository,
+				Name:       itemAppReference.Name,
+				Version:    itemAppReference.Version,
+			})
+		}
+	}
 	return appReferences
 }
@@ -46,6 +46,22 @@ func TestAppReferences(t *testing.T) {
 					Repository: "test",
 				},
 			}},
+		{
+			name: "should return app references of finalizers",
+			ep: &model.ExecutionPlan{Finalizers: []model.Finalize{{
+				Name: "notify",
+				AppReferences: []*configuration.AppReference{
+					{
+						Name:    "notifier",
+						Version: "1.0.0",
+					},
+				}}}},
+			expected: []*app.Reference{
+				{
+					Name:    "notifier",
+					Version: "1.0.0",
+				},
+			}},
 		{
 			name:     "should return nil",
 			ep:       &model.ExecutionPlan{ManualChecks: []model.ManualCheck{model.ManualCheck{}}},
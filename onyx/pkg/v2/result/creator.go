@@ -9,7 +9,10 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/B-S-F/yaku/onyx/pkg/configuration"
@@ -24,6 +27,7 @@ import (
 
 const (
 	errorStatus       = "ERROR"
+	failedStatus      = "FAILED"
 	redStatus         = "RED"
 	yellowStatus      = "YELLOW"
 	greenStatus       = "GREEN"
@@ -35,11 +39,14 @@ const (
 )
 
 type Creator struct {
-	logger logger.Logger
+	logger      logger.Logger
+	logMode     LogMode
+	evidenceDir string
+	outputLines bool
 }
 
 func New(logger logger.Logger) *Creator {
-	return &Creator{logger: logger}
+	return &Creator{logger: logger, logMode: LogModeFull}
 }
 
 func (c *Creator) Create(ep model.ExecutionPlan, runResult model.RunResult) (*Result, error) {
@@ -55,16 +62,6 @@ func (c *Creator) Create(ep model.ExecutionPlan, runResult model.RunResult) (*Re
 		c.logger.Debug("Add manual-check to result", zap.Any("manual-check", m))
 
 		c.addManualResult(res.Chapters, m)
-
-		switch m.Result.Status {
-		case unansweredStatus:
-			res.Statistics.CountUnansweredChecks++
-		case skippedStatus:
-			res.Statistics.CountSkippedChecks++
-		}
-
-		res.Statistics.CountManualChecks++
-		res.Statistics.CountChecks++
 	}
 
 	for _, a := range runResult.Autopilots {
@@ -74,24 +71,14 @@ func (c *Creator) Create(ep model.ExecutionPlan, runResult model.RunResult) (*Re
 		if err != nil {
 			return nil, err
 		}
-
-		if a.Result.EvaluateResult.Status == skippedStatus {
-			res.Statistics.CountSkippedChecks++
-		}
-
-		res.Statistics.CountAutomatedChecks++
-		res.Statistics.CountChecks++
 	}
 
 	for _, chap := range res.Chapters {
 		calculateChapterStatus(chap)
-		res.OverallStatus = getPriorityStatus(res.OverallStatus, chap.Status)
+		res.OverallStatus = PriorityStatus(res.OverallStatus, chap.Status)
 	}
 
-	if res.Statistics.CountChecks > 0 {
-		res.Statistics.PercentageAutomated = getPercentage(res.Statistics.CountAutomatedChecks, res.Statistics.CountChecks)
-		res.Statistics.PercentageDone = getPercentage(res.Statistics.CountChecks-res.Statistics.CountUnansweredChecks, res.Statistics.CountChecks)
-	}
+	addStatistics(&res)
 
 	return &res, nil
 }
@@ -102,14 +89,18 @@ func (c *Creator) AppendFinalizeResult(res *Result, finalizeResult model.Finaliz
 		configs = append(configs, cfg)
 	}
 
-	logs, err := c.marshalLogs(finalizeResult.Logs)
+	logs, logsRef, err := c.logs(finalizeResult.Logs, "finalize")
 	if err != nil {
 		return err
 	}
 
+	warnings := c.extractLogs(finalizeResult.Logs, jsonLogWarningKey)
+	warnings = append(warnings, c.enrich(res, "finalize", finalizeResult.Logs)...)
+
 	res.Finalize = &Finalize{
 		Logs:        logs,
-		Warnings:    c.extractLogs(finalizeResult.Logs, jsonLogWarningKey),
+		LogsRef:     logsRef,
+		Warnings:    warnings,
 		Messages:    c.extractLogs(finalizeResult.Logs, jsonLogMessageKey),
 		ConfigFiles: configs,
 		ExitCode:    finalizeResult.ExitCode,
@@ -118,9 +109,108 @@ func (c *Creator) AppendFinalizeResult(res *Result, finalizeResult model.Finaliz
 	return nil
 }
 
+func (c *Creator) AppendFinalizerRun(res *Result, run model.FinalizerRun) error {
+	finalizer, err := c.finalizerRun(run, path.Join("finalizers", run.Finalize.Name))
+	if err != nil {
+		return err
+	}
+	if run.Result != nil {
+		finalizer.Warnings = append(finalizer.Warnings, c.enrich(res, run.Finalize.Name, run.Result.Logs)...)
+	}
+
+	res.Finalizers = append(res.Finalizers, finalizer)
+	return nil
+}
+
+func (c *Creator) AppendSetupRun(res *Result, run model.FinalizerRun) error {
+	setup, err := c.finalizerRun(run, path.Join("setup", run.Finalize.Name))
+	if err != nil {
+		return err
+	}
+	res.Setup = append(res.Setup, setup)
+	return nil
+}
+
+func (c *Creator) AppendTeardownRun(res *Result, run model.FinalizerRun) error {
+	teardown, err := c.finalizerRun(run, path.Join("teardown", run.Finalize.Name))
+	if err != nil {
+		return err
+	}
+	res.Teardown = append(res.Teardown, teardown)
+	return nil
+}
+
+func (c *Creator) finalizerRun(run model.FinalizerRun, logsDir string) (Finalizer, error) {
+	finalizer := Finalizer{
+		Name:   run.Finalize.Name,
+		If:     run.Finalize.If,
+		Status: run.Status,
+		Reason: run.Reason,
+	}
+	for cfg := range run.Finalize.Configs {
+		finalizer.ConfigFiles = append(finalizer.ConfigFiles, cfg)
+	}
+	sort.Strings(finalizer.ConfigFiles)
+
+	if run.Result != nil {
+		logs, logsRef, err := c.logs(run.Result.Logs, logsDir)
+		if err != nil {
+			return Finalizer{}, err
+		}
+		finalizer.Logs = logs
+		finalizer.LogsRef = logsRef
+		finalizer.Warnings = c.extractLogs(run.Result.Logs, jsonLogWarningKey)
+		finalizer.Messages = c.extractLogs(run.Result.Logs, jsonLogMessageKey)
+		finalizer.ExitCode = run.Result.ExitCode
+	}
+	return finalizer, nil
+}
+
+func (c *Creator) AppendServiceRun(res *Result, run model.ServiceRun) {
+	res.Services = append(res.Services, Service{
+		Name:     run.Service.Name,
+		Status:   run.Status,
+		Reason:   run.Reason,
+		Restarts: run.Restarts,
+		LogFile:  run.LogFile,
+	})
+}
+
+func (c *Creator) AppendNotificationRun(res *Result, run model.NotificationRun) {
+	res.Notifications = append(res.Notifications, Notification{
+		Name:   run.Notification.Name,
+		Type:   run.Notification.Type,
+		Status: run.Status,
+		Reason: run.Reason,
+	})
+}
+
+// StatusVariables returns the statuses of the result in a form that can be used
+// to evaluate conditions, e.g. `status == 'RED'` or `chapters.1.status == 'GREEN'`.
+func StatusVariables(res *Result) map[string]interface{} {
+	chapters := make(map[string]interface{}, len(res.Chapters))
+	for chapID, chap := range res.Chapters {
+		requirements := make(map[string]interface{}, len(chap.Requirements))
+		for reqID, req := range chap.Requirements {
+			requirements[reqID] = map[string]interface{}{"status": req.Status}
+		}
+		chapters[chapID] = map[string]interface{}{
+			"status":       chap.Status,
+			"requirements": requirements,
+		}
+	}
+	return map[string]interface{}{
+		"status":   res.OverallStatus,
+		"chapters": chapters,
+	}
+}
+
 func (c *Creator) marshalLogs(logs []model.LogEntry) ([]string, error) {
 	var result []string
 	for _, log := range logs {
+		if c.outputLines && log.Line != "" {
+			log.Text = log.Line
+		}
 		logLine, err := json.Marshal(log)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to json marshal log entry")
@@ -145,7 +235,52 @@ func (c *Creator) extractLogs(logs []model.LogEntry, jsonLogKey string) []string
 	return results
 }
 
+func evidenceReferences(evidence []model.EvidenceReference) []EvidenceReference {
+	var references []EvidenceReference
+	for _, ref := range evidence {
+		references = append(references, EvidenceReference{
+			Path:    ref.Path,
+			Pointer: ref.Pointer,
+			Size:    ref.Size,
+			SHA256:  ref.SHA256,
+		})
+	}
+	return references
+}
+
+// previousHashes returns the hashes of a result under the previous IDs of its check
+func previousHashes(hashFields helper.HashFields, previousIds []string) []string {
+	var hashes []string
+	for _, id := range previousIds {
+		hashFields.Check = id
+		hashes = append(hashes, helper.GenerateCheckResultIdHash(hashFields))
+	}
+	return hashes
+}
+
+func destinations(destinations []model.Destination) []Destination {
+	var result []Destination
+	for _, d := range destinations {
+		result = append(result, Destination{
+			Host:     d.Host,
+			Port:     d.Port,
+			Requests: d.Requests,
+			Denied:   d.Denied,
+		})
+	}
+	return result
+}
+
 func (c *Creator) WriteResultFile(res Result, path string) error {
+	return c.writeFile(res, path)
+}
+
+// WriteV1ResultFile writes the result in the format of the v1 results
+func (c *Creator) WriteV1ResultFile(res Result, path string) error {
+	return c.writeFile(ToV1(res), path)
+}
+
+func (c *Creator) writeFile(res interface{}, path string) error {
 	c.logger.Info(fmt.Sprintf("storing results in result file '%s'", filepath.Base(path)))
 	resYaml, err := yaml.Marshal(res)
 	if err != nil {
@@ -196,7 +331,8 @@ func (c *Creator) addAutopilotResult(chapters map[string]*Chapter, a model.Autop
 			}
 		}
 
-		steps, err := c.createSteps(a.Result.StepResults, stepsByID)
+		checkDir := strings.Join([]string{a.AutopilotCheck.Chapter.Id, a.AutopilotCheck.Requirement.Id, a.AutopilotCheck.Check.Id}, "_")
+		steps, err := c.createSteps(a.Result.StepResults, stepsByID, checkDir)
 		if err != nil {
 			return err
 		}
@@ -212,26 +348,32 @@ func (c *Creator) addAutopilotResult(chapters map[string]*Chapter, a model.Autop
 				Chapter:       a.AutopilotCheck.Chapter.Id,
 				Requirement:   a.AutopilotCheck.Requirement.Id,
 				Check:         a.AutopilotCheck.Check.Id,
+				ID:            result.ID,
 				Criterion:     result.Criterion,
 				Justification: result.Justification,
 			}
 			evaluationResults = append(evaluationResults, EvaluationResult{
-				Hash:          helper.GenerateCheckResultIdHash(hashFields),
-				Criterion:     common.MultilineString(result.Criterion),
-				Fulfilled:     result.Fulfilled,
-				Justification: common.MultilineString(result.Justification),
-				Metadata:      result.Metadata,
+				Hash:           helper.GenerateCheckResultIdHash(hashFields),
+				PreviousHashes: previousHashes(hashFields, a.AutopilotCheck.PreviousIds),
+				ID:             result.ID,
+				Criterion:      common.MultilineString(result.Criterion),
+				Fulfilled:      result.Fulfilled,
+				Justification:  common.MultilineString(result.Justification),
+				Metadata:       result.Metadata,
+				Evidence:       evidenceReferences(result.Evidence),
 			})
 		}
 
-		evaluateLogs, err := c.marshalLogs(a.Result.EvaluateResult.Logs)
+		evaluateLogs, evaluateLogsRef, err := c.logs(a.Result.EvaluateResult.Logs, path.Join(checkDir, "evaluation"))
 		if err != nil {
 			return err
 		}
 
 		requirement.Checks[a.AutopilotCheck.Check.Id] = &Check{
-			Title: a.AutopilotCheck.Check.Title,
-			Type:  "automation",
+			Title:       a.AutopilotCheck.Check.Title,
+			Type:        "automation",
+			Labels:      a.AutopilotCheck.Labels,
+			PreviousIds: a.AutopilotCheck.PreviousIds,
 			Autopilots: []Autopilot{
 				{
 					Name:  a.AutopilotCheck.Autopilot.Name,
@@ -244,17 +386,19 @@ func (c *Creator) addAutopilotResult(chapters map[string]*Chapter, a model.Autop
 				ConfigFiles: evaluationCfgs,
 				Results:     evaluationResults,
 				Logs:        evaluateLogs,
+				LogsRef:     evaluateLogsRef,
 				Warnings:    c.extractLogs(a.Result.EvaluateResult.Logs, jsonLogWarningKey),
 				Messages:    c.extractLogs(a.Result.EvaluateResult.Logs, jsonLogMessageKey),
 				ExitCode:    a.Result.EvaluateResult.ExitCode,
 			},
+			Duration: roundSeconds(a.Result.Duration),
 		}
 	}
 
 	return nil
 }
 
-func (c *Creator) createSteps(stepResults []model.StepResult, stepsByID map[string]model.Step) ([]Step, error) {
+func (c *Creator) createSteps(stepResults []model.StepResult, stepsByID map[string]model.Step, checkDir string) ([]Step, error) {
 	var steps []Step
 	for _, s := range stepResults {
 		stepModel, ok := stepsByID[s.ID]
@@ -267,23 +411,25 @@ func (c *Creator) createSteps(stepResults []model.StepResult, stepsByID map[stri
 			cfgs = append(cfgs, cfgFilename)
 		}
 
-		logs, err := c.marshalLogs(s.Logs)
+		logs, logsRef, err := c.logs(s.Logs, path.Join(checkDir, "steps", s.ID))
 		if err != nil {
 			return nil, err
 		}
 
 		steps = append(steps, Step{
-			Title:       stepModel.Title,
-			Id:          s.ID,
-			Depends:     stepModel.Depends,
-			ConfigFiles: cfgs,
-			InputDirs:   s.InputDirs,
-			OutputDir:   s.OutputDir,
-			ResultFile:  s.ResultFile,
-			Logs:        logs,
-			Warnings:    c.extractLogs(s.Logs, jsonLogWarningKey),
-			Messages:    c.extractLogs(s.Logs, jsonLogMessageKey),
-			ExitCode:    s.ExitCode,
+			Title:        stepModel.Title,
+			Id:           s.ID,
+			Depends:      stepModel.Depends,
+			ConfigFiles:  cfgs,
+			InputDirs:    s.InputDirs,
+			OutputDir:    s.OutputDir,
+			ResultFile:   s.ResultFile,
+			Logs:         logs,
+			LogsRef:      logsRef,
+			Warnings:     c.extractLogs(s.Logs, jsonLogWarningKey),
+			Messages:     c.extractLogs(s.Logs, jsonLogMessageKey),
+			ExitCode:     s.ExitCode,
+			Destinations: destinations(s.Destinations),
 		})
 	}
 
@@ -306,12 +452,15 @@ func (c *Creator) addManualResult(chapters map[string]*Chapter, m model.ManualRu
 	_, ok = requirement.Checks[m.ManualCheck.Check.Id]
 	if !ok {
 		requirement.Checks[m.ManualCheck.Check.Id] = &Check{
-			Title: m.ManualCheck.Check.Title,
-			Type:  "manual",
+			Title:       m.ManualCheck.Check.Title,
+			Type:        "manual",
+			Labels:      m.ManualCheck.Labels,
+			PreviousIds: m.ManualCheck.PreviousIds,
 			Evaluation: Evaluation{
 				Status: m.Result.Status,
 				Reason: m.Result.Reason,
 			},
+			Expires: m.ManualCheck.Manual.Expires,
 		}
 	}
 }
@@ -335,21 +484,26 @@ func mapRequirement(req configuration.Requirement) *Requirement {
 func calculateChapterStatus(chap *Chapter) {
 	for _, req := range chap.Requirements {
 		calculateRequirementStatus(req)
-		chap.Status = getPriorityStatus(chap.Status, req.Status)
+		chap.Status = PriorityStatus(chap.Status, req.Status)
 	}
 }
 
 func calculateRequirementStatus(req *Requirement) {
 	for _, check := range req.Checks {
-		req.Status = getPriorityStatus(req.Status, check.Evaluation.Status)
+		req.Status = PriorityStatus(req.Status, check.Evaluation.Status)
 	}
 }
 
-func getPriorityStatus(statusA, statusB string) string {
+// PriorityStatus returns the more severe of both statuses, ERROR being the most severe one.
+func PriorityStatus(statusA, statusB string) string {
 	switch {
 	case statusA == errorStatus || statusB == errorStatus:
 		return errorStatus
 
+	// only autopilots of legacy checks can fail
+	case statusA == failedStatus || statusB == failedStatus:
+		return failedStatus
+
 	case statusA == redStatus || statusB == redStatus:
 		return redStatus
 
@@ -373,3 +527,8 @@ func getPriorityStatus(statusA, statusB string) string {
 func getPercentage(numerator, denominator uint) float64 {
 	return math.Round(float64(numerator)*10000.0/float64(denominator)) / 100.0
 }
+
+// roundSeconds returns the duration in seconds rounded to milliseconds
+func roundSeconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
@@ -50,7 +50,7 @@ func TestCreator_Create(t *testing.T) {
 				Chapters: map[string]*Chapter{
 					"1": simpleManualChapter(),
 				},
-				Statistics: Statistics{CountChecks: 1, CountManualChecks: 1, PercentageDone: 100},
+				Statistics: Statistics{CountChecks: 1, CountManualChecks: 1, PercentageDone: 100, CountChecksByStatus: map[string]uint{"GREEN": 1}},
 			}},
 		},
 		"return_result_when_multiple_manual_runs_with_same_status": {
@@ -90,7 +90,7 @@ func TestCreator_Create(t *testing.T) {
 						},
 					},
 				},
-				Statistics: Statistics{CountChecks: 2, CountManualChecks: 2, PercentageDone: 100},
+				Statistics: Statistics{CountChecks: 2, CountManualChecks: 2, PercentageDone: 100, CountChecksByStatus: map[string]uint{"GREEN": 2}},
 			}},
 		},
 		"return_result_when_multiple_manual_runs_with_different_statuses": {
@@ -151,7 +151,7 @@ func TestCreator_Create(t *testing.T) {
 						},
 					},
 				},
-				Statistics: Statistics{CountChecks: 2, CountManualChecks: 2, PercentageDone: 100},
+				Statistics: Statistics{CountChecks: 2, CountManualChecks: 2, PercentageDone: 100, CountChecksByStatus: map[string]uint{"GREEN": 1, "YELLOW": 1}},
 			}},
 		},
 		"return_result_when_single_autopilot_run": {
@@ -168,7 +168,7 @@ func TestCreator_Create(t *testing.T) {
 				Chapters: map[string]*Chapter{
 					"1": simpleAutomationChapter(),
 				},
-				Statistics: Statistics{CountChecks: 1, CountAutomatedChecks: 1, PercentageDone: 100, PercentageAutomated: 100},
+				Statistics: Statistics{CountChecks: 1, CountAutomatedChecks: 1, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 1}, CountCriteria: 1},
 			}},
 		},
 		"return_result_when_multiple_autopilot_runs": {
@@ -191,7 +191,54 @@ func TestCreator_Create(t *testing.T) {
 						return c
 					}(),
 				},
-				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100},
+				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 2}, CountCriteria: 2},
+			}},
+		},
+		"return_result_when_autopilot_runs_are_na_or_skipped": {
+			args: args{
+				ep: *simpleExecPlan(),
+				runResult: model.RunResult{
+					Autopilots: []model.AutopilotRun{
+						newAutopilotRunBuilder().status("NA").reason("not applicable").get(),
+						newAutopilotRunBuilder().chapterID("2").status("SKIPPED").reason("skipped").get(),
+					},
+					Manuals: []model.ManualRun{
+						newManualRunBuilder().chapterID("3").status("NA").reason("not applicable").get(),
+					},
+				},
+			},
+			want: want{result: &Result{
+				Metadata:      Metadata{Version: "v2"},
+				Header:        Header{Version: "1.0", Name: "test"},
+				OverallStatus: "SKIPPED",
+				Chapters: map[string]*Chapter{
+					"1": func() *Chapter {
+						c := simpleAutomationChapter()
+						c.Status = "NA"
+						c.Requirements["1"].Status = "NA"
+						c.Requirements["1"].Checks["1"].Evaluation.Status = "NA"
+						c.Requirements["1"].Checks["1"].Evaluation.Reason = "not applicable"
+						return c
+					}(),
+					"2": func() *Chapter {
+						c := simpleAutomationChapter()
+						c.Status = "SKIPPED"
+						c.Requirements["1"].Status = "SKIPPED"
+						c.Requirements["1"].Checks["1"].Evaluation.Status = "SKIPPED"
+						c.Requirements["1"].Checks["1"].Evaluation.Reason = "skipped"
+						c.Requirements["1"].Checks["1"].Evaluation.Results[0].Hash = "dc34b8e77c1e77c63c75a34e80d2f47a0b9fde6cb314d566cb7e3bff2a2b30a2"
+						return c
+					}(),
+					"3": func() *Chapter {
+						c := simpleManualChapter()
+						c.Status = "NA"
+						c.Requirements["1"].Status = "NA"
+						c.Requirements["1"].Checks["1"].Evaluation.Status = "NA"
+						c.Requirements["1"].Checks["1"].Evaluation.Reason = "not applicable"
+						return c
+					}(),
+				},
+				Statistics: Statistics{CountChecks: 3, CountAutomatedChecks: 2, CountManualChecks: 1, CountSkippedChecks: 1, CountNAChecks: 2, PercentageDone: 100, PercentageAutomated: 66.67, CountChecksByStatus: map[string]uint{"NA": 2, "SKIPPED": 1}, CountCriteria: 2},
 			}},
 		},
 		"return_result_when_multiple_autopilot_runs_with_different_statuses_for_different_chapters": {
@@ -218,7 +265,40 @@ func TestCreator_Create(t *testing.T) {
 						return c
 					}(),
 				},
-				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100},
+				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 1, "RED": 1}, CountCriteria: 2},
+			}},
+		},
+		"return_result_with_result_id_and_previous_check_ids": {
+			args: args{
+				ep: *simpleExecPlan(),
+				runResult: model.RunResult{Autopilots: []model.AutopilotRun{
+					func() model.AutopilotRun {
+						run := newAutopilotRunBuilder().get()
+						run.AutopilotCheck.PreviousIds = []string{"0", "old"}
+						run.Result.EvaluateResult.Results[0].ID = "finding-1"
+						return run
+					}(),
+				}},
+			},
+			want: want{result: &Result{
+				Metadata:      Metadata{Version: "v2"},
+				Header:        Header{Version: "1.0", Name: "test"},
+				OverallStatus: "GREEN",
+				Chapters: map[string]*Chapter{
+					"1": func() *Chapter {
+						c := simpleAutomationChapter()
+						check := c.Requirements["1"].Checks["1"]
+						check.PreviousIds = []string{"0", "old"}
+						check.Evaluation.Results[0].ID = "finding-1"
+						check.Evaluation.Results[0].Hash = "62b8d2c5abc01f1f1563b96dc9c77b27c18168152f163aaf81dfedb7d0a982ed"
+						check.Evaluation.Results[0].PreviousHashes = []string{
+							"a9f43312b59abeb8b942170378bbfd59ed20e90a54df1c619ee48082b3088a18",
+							"7de99be5c5daa8beb6aa9fb079fc3486d1cdafbac0062682397bd32b260d7763",
+						}
+						return c
+					}(),
+				},
+				Statistics: Statistics{CountChecks: 1, CountAutomatedChecks: 1, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 1}, CountCriteria: 1},
 			}},
 		},
 		"return_result_when_multiple_autopilot_runs_with_different_statuses_for_different_requirements": {
@@ -246,7 +326,7 @@ func TestCreator_Create(t *testing.T) {
 						return c
 					}(),
 				},
-				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100},
+				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 1, "RED": 1}, CountCriteria: 2},
 			}},
 		},
 		"return_result_when_multiple_autopilot_runs_with_different_statuses_for_different_checks": {
@@ -273,7 +353,7 @@ func TestCreator_Create(t *testing.T) {
 						return c
 					}(),
 				},
-				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100},
+				Statistics: Statistics{CountChecks: 2, CountAutomatedChecks: 2, PercentageDone: 100, PercentageAutomated: 100, CountChecksByStatus: map[string]uint{"GREEN": 1, "RED": 1}, CountCriteria: 2},
 			}},
 		},
 	}
@@ -296,12 +376,23 @@ func TestCreator_Create(t *testing.T) {
 			assert.Equal(t, tt.want.result.Header.ToolVersion, got.Header.ToolVersion)
 			assert.Equal(t, tt.want.result.OverallStatus, got.OverallStatus)
 			assert.Equal(t, tt.want.result.Statistics, got.Statistics)
-			assert.Equal(t, tt.want.result.Chapters, got.Chapters)
+			// statistics of chapters and requirements are covered by TestAddStatistics
+			assert.Equal(t, tt.want.result.Chapters, withoutStatistics(got.Chapters))
 			assert.Equal(t, tt.want.result.Finalize, got.Finalize)
 		})
 	}
 }
 
+func withoutStatistics(chapters map[string]*Chapter) map[string]*Chapter {
+	for _, chapter := range chapters {
+		chapter.Statistics = nil
+		for _, requirement := range chapter.Requirements {
+			requirement.Statistics = nil
+		}
+	}
+	return chapters
+}
+
 type autopilotRunBuilder struct {
 	autopilotRun model.AutopilotRun
 }
@@ -139,6 +139,94 @@ func TestCreator_AppendFinalizeResult(t *testing.T) {
 	}
 }
 
+func TestCreator_AppendFinalizerRun(t *testing.T) {
+	tests := map[string]struct {
+		run  model.FinalizerRun
+		want []Finalizer
+	}{
+		"should_append_executed_finalizer": {
+			run: model.FinalizerRun{
+				Finalize: model.Finalize{Name: "tickets", If: "status == 'RED'", Configs: map[string]string{"b.yaml": "", "a.yaml": ""}},
+				Status:   "SUCCEEDED",
+				Result: &model.FinalizeResult{
+					Logs: []model.LogEntry{
+						{Source: "stdout", Text: "created 2 tickets"},
+						{Source: "stdout", Json: map[string]interface{}{"warning": "ticket already exists"}},
+					},
+				},
+			},
+			want: []Finalizer{{
+				Name:        "tickets",
+				If:          "status == 'RED'",
+				Status:      "SUCCEEDED",
+				ConfigFiles: []string{"a.yaml", "b.yaml"},
+				Logs: []string{
+					"{\"source\":\"stdout\",\"text\":\"created 2 tickets\"}",
+					"{\"source\":\"stdout\",\"json\":{\"warning\":\"ticket already exists\"}}",
+				},
+				Warnings: []string{"ticket already exists"},
+			}},
+		},
+		"should_append_skipped_finalizer": {
+			run: model.FinalizerRun{
+				Finalize: model.Finalize{Name: "publish", If: "status == 'GREEN'"},
+				Status:   "SKIPPED",
+				Reason:   "condition 'status == 'GREEN'' evaluated to false",
+			},
+			want: []Finalizer{{
+				Name:   "publish",
+				If:     "status == 'GREEN'",
+				Status: "SKIPPED",
+				Reason: "condition 'status == 'GREEN'' evaluated to false",
+			}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Creator{logger: logger.NewAutopilot()}
+			res := &Result{}
+			err := c.AppendFinalizerRun(res, tt.run)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, res.Finalizers)
+		})
+	}
+}
+
+func TestCreator_AppendNotificationRun(t *testing.T) {
+	c := &Creator{logger: logger.NewAutopilot()}
+	res := &Result{}
+
+	c.AppendNotificationRun(res, model.NotificationRun{Notification: model.Notification{Name: "team", Type: "slack"}, Status: "SENT", Reason: "overall status is 'RED'"})
+	c.AppendNotificationRun(res, model.NotificationRun{Notification: model.Notification{Name: "mail", Type: "smtp"}, Status: "SKIPPED", Reason: "no trigger condition is met"})
+
+	assert.Equal(t, []Notification{
+		{Name: "team", Type: "slack", Status: "SENT", Reason: "overall status is 'RED'"},
+		{Name: "mail", Type: "smtp", Status: "SKIPPED", Reason: "no trigger condition is met"},
+	}, res.Notifications)
+}
+
+func TestStatusVariables(t *testing.T) {
+	res := &Result{
+		OverallStatus: "RED",
+		Chapters: map[string]*Chapter{
+			"1": {Status: "RED", Requirements: map[string]*Requirement{"1.1": {Status: "RED"}}},
+		},
+	}
+
+	vars := StatusVariables(res)
+
+	assert.Equal(t, map[string]interface{}{
+		"status": "RED",
+		"chapters": map[string]interface{}{
+			"1": map[string]interface{}{
+				"status":       "RED",
+				"requirements": map[string]interface{}{"1.1": map[string]interface{}{"status": "RED"}},
+			},
+		},
+	}, vars)
+}
+
 func simpleResultYAML() string {
 	return `
 metadata:
@@ -155,6 +243,7 @@ statistics:
     counted-manual-check: 1
     counted-unanswered-checks: 0
     counted-skipped-checks: 0
+    counted-na-checks: 0
     degree-of-automation: 50
     degree-of-completion: 100
 chapters:
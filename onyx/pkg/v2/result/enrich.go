@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	jsonLogAnnotationKey = "annotation"
+	jsonLogLinkKey       = "link"
+	jsonLogStatusKey     = "status"
+)
+
+// target identifies the level of the result an annotation or link is attached to.
+// An empty chapter targets the run, a chapter without check targets the chapter.
+type target struct {
+	Chapter     string `json:"chapter"`
+	Requirement string `json:"requirement"`
+	Check       string `json:"check"`
+}
+
+// enrich merges the annotations and links emitted by a finalizer into the result.
+// Invalid entries are not merged but returned as warnings.
+func (c *Creator) enrich(res *Result, source string, logs []model.LogEntry) []string {
+	var warnings []string
+	for _, log := range logs {
+		if _, ok := log.Json[jsonLogStatusKey]; ok {
+			warnings = append(warnings, fmt.Sprintf("finalizer '%s' is not allowed to set a status, the status is ignored", source))
+		}
+		if v, ok := log.Json[jsonLogAnnotationKey]; ok {
+			if err := c.addAnnotation(res, source, v); err != nil {
+				warnings = append(warnings, fmt.Sprintf("finalizer '%s' provided an invalid annotation: %s", source, err.Error()))
+			}
+		}
+		if v, ok := log.Json[jsonLogLinkKey]; ok {
+			if err := c.addLink(res, source, v); err != nil {
+				warnings = append(warnings, fmt.Sprintf("finalizer '%s' provided an invalid link: %s", source, err.Error()))
+			}
+		}
+	}
+	for _, w := range warnings {
+		c.logger.Warn(w)
+	}
+	return warnings
+}
+
+func (c *Creator) addAnnotation(res *Result, source string, v interface{}) error {
+	var raw struct {
+		target
+		Key   string      `json:"key"`
+		Value interface{} `json:"value"`
+	}
+	if err := decode(v, &raw); err != nil {
+		return err
+	}
+	if raw.Key == "" {
+		return errors.New("'key' is required")
+	}
+	if raw.Value == nil {
+		return errors.New("'value' is required")
+	}
+	value, err := stringify(raw.Value)
+	if err != nil {
+		return err
+	}
+	annotation := Annotation{Key: raw.Key, Value: value, Source: source}
+
+	switch lvl, err := res.resolve(raw.target); {
+	case err != nil:
+		return err
+	case lvl.check != nil:
+		lvl.check.Annotations = append(lvl.check.Annotations, annotation)
+	case lvl.chapter != nil:
+		lvl.chapter.Annotations = append(lvl.chapter.Annotations, annotation)
+	default:
+		res.Annotations = append(res.Annotations, annotation)
+	}
+	return nil
+}
+
+func (c *Creator) addLink(res *Result, source string, v interface{}) error {
+	var raw struct {
+		target
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+	if err := decode(v, &raw); err != nil {
+		return err
+	}
+	if raw.URL == "" {
+		return errors.New("'url' is required")
+	}
+	u, err := url.Parse(raw.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("'url' must be an absolute http(s) URL but is '%s'", raw.URL)
+	}
+	link := Link{Title: raw.Title, URL: raw.URL, Source: source}
+
+	switch lvl, err := res.resolve(raw.target); {
+	case err != nil:
+		return err
+	case lvl.check != nil:
+		lvl.check.Links = append(lvl.check.Links, link)
+	case lvl.chapter != nil:
+		lvl.chapter.Links = append(lvl.chapter.Links, link)
+	default:
+		res.Links = append(res.Links, link)
+	}
+	return nil
+}
+
+type level struct {
+	chapter *Chapter
+	check   *Check
+}
+
+func (r *Result) resolve(t target) (level, error) {
+	if t.Chapter == "" {
+		if t.Requirement != "" || t.Check != "" {
+			return level{}, errors.New("'chapter' is required if 'requirement' or 'check' is set")
+		}
+		return level{}, nil
+	}
+	chapter, ok := r.Chapters[t.Chapter]
+	if !ok {
+		return level{}, errors.Errorf("chapter '%s' does not exist", t.Chapter)
+	}
+	if t.Requirement == "" && t.Check == "" {
+		return level{chapter: chapter}, nil
+	}
+	if t.Requirement == "" || t.Check == "" {
+		return level{}, errors.New("'requirement' and 'check' must be set together")
+	}
+	requirement, ok := chapter.Requirements[t.Requirement]
+	if !ok {
+		return level{}, errors.Errorf("requirement '%s' does not exist in chapter '%s'", t.Requirement, t.Chapter)
+	}
+	check, ok := requirement.Checks[t.Check]
+	if !ok {
+		return level{}, errors.Errorf("check '%s' does not exist in requirement '%s' of chapter '%s'", t.Check, t.Requirement, t.Chapter)
+	}
+	return level{chapter: chapter, check: check}, nil
+}
+
+func decode(v interface{}, out interface{}) error {
+	if _, ok := v.(map[string]interface{}); !ok {
+		return errors.New("expected an object")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal")
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Wrap(err, "unexpected structure")
+	}
+	return nil
+}
+
+func stringify(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal value")
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprintf("%v", value), nil
+	}
+}
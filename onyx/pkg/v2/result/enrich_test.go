@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreator_enrich(t *testing.T) {
+	tests := map[string]struct {
+		logs         []model.LogEntry
+		want         func(res *Result)
+		wantWarnings []string
+	}{
+		"should_add_annotation_and_link_on_run_level": {
+			logs: []model.LogEntry{
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"key": "approval", "value": "APPROVAL-1"}}},
+				{Source: "stdout", Json: map[string]interface{}{"link": map[string]interface{}{"title": "Report", "url": "https://example.com/report.html"}}},
+			},
+			want: func(res *Result) {
+				res.Annotations = []Annotation{{Key: "approval", Value: "APPROVAL-1", Source: "publish"}}
+				res.Links = []Link{{Title: "Report", URL: "https://example.com/report.html", Source: "publish"}}
+			},
+		},
+		"should_add_annotation_on_chapter_and_check_level": {
+			logs: []model.LogEntry{
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"chapter": "1", "key": "owner", "value": "team-a"}}},
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"chapter": "2", "requirement": "1", "check": "1", "key": "ticket", "value": map[string]interface{}{"id": 42.0}}}},
+				{Source: "stdout", Json: map[string]interface{}{"link": map[string]interface{}{"chapter": "2", "requirement": "1", "check": "1", "url": "https://jira.example.com/browse/QG-42"}}},
+			},
+			want: func(res *Result) {
+				res.Chapters["1"].Annotations = []Annotation{{Key: "owner", Value: "team-a", Source: "publish"}}
+				res.Chapters["2"].Requirements["1"].Checks["1"].Annotations = []Annotation{{Key: "ticket", Value: `{"id":42}`, Source: "publish"}}
+				res.Chapters["2"].Requirements["1"].Checks["1"].Links = []Link{{URL: "https://jira.example.com/browse/QG-42", Source: "publish"}}
+			},
+		},
+		"should_ignore_invalid_entries_and_status": {
+			logs: []model.LogEntry{
+				{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}},
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"value": "no key"}}},
+				{Source: "stdout", Json: map[string]interface{}{"annotation": "not an object"}},
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"chapter": "3", "key": "k", "value": "v"}}},
+				{Source: "stdout", Json: map[string]interface{}{"annotation": map[string]interface{}{"chapter": "1", "check": "1", "key": "k", "value": "v"}}},
+				{Source: "stdout", Json: map[string]interface{}{"link": map[string]interface{}{"url": "file:///etc/passwd"}}},
+			},
+			want: func(res *Result) {},
+			wantWarnings: []string{
+				"finalizer 'publish' is not allowed to set a status, the status is ignored",
+				"finalizer 'publish' provided an invalid annotation: 'key' is required",
+				"finalizer 'publish' provided an invalid annotation: expected an object",
+				"finalizer 'publish' provided an invalid annotation: chapter '3' does not exist",
+				"finalizer 'publish' provided an invalid annotation: 'requirement' and 'check' must be set together",
+				"finalizer 'publish' provided an invalid link: 'url' must be an absolute http(s) URL but is 'file:///etc/passwd'",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Creator{logger: logger.NewAutopilot()}
+			res := &Result{OverallStatus: "RED", Chapters: map[string]*Chapter{"1": simpleManualChapter(), "2": simpleAutomationChapter()}}
+			want := &Result{OverallStatus: "RED", Chapters: map[string]*Chapter{"1": simpleManualChapter(), "2": simpleAutomationChapter()}}
+			tt.want(want)
+
+			warnings := c.enrich(res, "publish", tt.logs)
+
+			assert.Equal(t, tt.wantWarnings, warnings)
+			assert.Equal(t, want, res)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/B-S-F/yaku/onyx/pkg/result/v1"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+)
+
+const (
+	v1ResultVersion  = "v1"
+	v1DateFormat     = "2006-01-02 15:04"
+	v1ManualType     = "Manual"
+	v1AutomationType = "Automation"
+	jsonLogOutputKey = "output"
+	stdOutSource     = "stdout"
+	stdErrSource     = "stderr"
+)
+
+// ToV1 converts a result into the result format of the v0 and v1 configs.
+// It is used to provide results of legacy configs, which are run on the v2 engine, to consumers still
+// relying on the old format.
+func ToV1(res Result) *v1.Result {
+	legacy := &v1.Result{
+		Metadata: v1.Metadata{Version: v1ResultVersion},
+		Header: v1.Header{
+			Name:        res.Header.Name,
+			Version:     res.Header.Version,
+			Date:        toV1Date(res.Header.Date),
+			ToolVersion: res.Header.ToolVersion,
+		},
+		OverallStatus: res.OverallStatus,
+		Chapters:      make(map[string]*v1.Chapter, len(res.Chapters)),
+	}
+
+	for chapterId, chapter := range res.Chapters {
+		legacyChapter := &v1.Chapter{
+			Title:        chapter.Title,
+			Text:         chapter.Text,
+			Status:       chapter.Status,
+			Requirements: make(map[string]*v1.Requirement, len(chapter.Requirements)),
+		}
+		for requirementId, requirement := range chapter.Requirements {
+			legacyRequirement := &v1.Requirement{
+				Title:  requirement.Title,
+				Text:   requirement.Text,
+				Status: requirement.Status,
+				Checks: make(map[string]*v1.Check, len(requirement.Checks)),
+			}
+			for checkId, check := range requirement.Checks {
+				evidencePath := strings.Join([]string{chapterId, requirementId, checkId}, "_")
+				legacyCheck := toV1Check(check, evidencePath)
+				addV1Statistics(&legacy.Statistics, legacyCheck)
+				legacyRequirement.Checks[checkId] = legacyCheck
+			}
+			legacyChapter.Requirements[requirementId] = legacyRequirement
+		}
+		legacy.Chapters[chapterId] = legacyChapter
+	}
+
+	if legacy.Statistics.CountChecks > 0 {
+		legacy.Statistics.PercentageAutomated = getPercentage(legacy.Statistics.CountAutomatedChecks, legacy.Statistics.CountChecks)
+		legacy.Statistics.PercentageDone = getPercentage(legacy.Statistics.CountChecks-legacy.Statistics.CountUnansweredChecks, legacy.Statistics.CountChecks)
+	}
+
+	if res.Finalize != nil {
+		logs, errLogs := toV1Logs(res.Finalize.Logs)
+		legacy.Finalize = &v1.Finalize{
+			Execution: v1.ExecutionInformation{
+				Logs:         logs,
+				ErrorLogs:    errLogs,
+				EvidencePath: ".",
+				ExitCode:     res.Finalize.ExitCode,
+			},
+		}
+	}
+
+	return legacy
+}
+
+// WithOutputLines keeps the output lines of json log entries as their text.
+// Results in the v1 format show the logs as they were written by the autopilots, see ToV1.
+func (c *Creator) WithOutputLines() *Creator {
+	c.outputLines = true
+	return c
+}
+
+func toV1Check(check *Check, evidencePath string) *v1.Check {
+	legacyCheck := &v1.Check{
+		Title:  check.Title,
+		Status: check.Evaluation.Status,
+		Type:   v1ManualType,
+		Evaluation: v1.CheckResult{
+			Status: check.Evaluation.Status,
+			Reason: check.Evaluation.Reason,
+		},
+	}
+	if check.Type == "manual" {
+		return legacyCheck
+	}
+
+	legacyCheck.Type = v1AutomationType
+	if len(check.Autopilots) > 0 {
+		legacyCheck.Evaluation.Autopilot = check.Autopilots[0].Name
+	}
+	for _, r := range check.Evaluation.Results {
+		legacyCheck.Evaluation.Results = append(legacyCheck.Evaluation.Results, v1.AutopilotResult{
+			Hash:          r.Hash,
+			Criterion:     r.Criterion,
+			Fulfilled:     r.Fulfilled,
+			Justification: r.Justification,
+			Metadata:      r.Metadata,
+		})
+	}
+
+	// v1 autopilots had no steps, the logs of the steps are combined with the logs of the evaluation
+	var logs []string
+	for _, autopilot := range check.Autopilots {
+		for _, step := range autopilot.Steps {
+			logs = append(logs, step.Logs...)
+		}
+	}
+	logs = append(logs, check.Evaluation.Logs...)
+
+	legacyCheck.Evaluation.Outputs = toV1Outputs(logs)
+	legacyCheck.Evaluation.Execution.Logs, legacyCheck.Evaluation.Execution.ErrorLogs = toV1Logs(logs)
+	legacyCheck.Evaluation.Execution.ExitCode = check.Evaluation.ExitCode
+	legacyCheck.Evaluation.Execution.EvidencePath = evidencePath
+	return legacyCheck
+}
+
+func addV1Statistics(statistics *v1.Statistics, check *v1.Check) {
+	statistics.CountChecks++
+	if check.Type == v1AutomationType {
+		statistics.CountAutomatedChecks++
+		return
+	}
+	switch check.Evaluation.Status {
+	case unansweredStatus:
+		statistics.CountUnansweredChecks++
+	case skippedStatus:
+		statistics.CountSkippedChecks++
+	default:
+		// v1 results count only answered manual checks
+		statistics.CountManualChecks++
+	}
+}
+
+// toV1Logs splits the json encoded log entries into the plain logs and error logs of a v1 result.
+// Json entries are shown with their output line if it was kept, see WithOutputLines.
+func toV1Logs(logs []string) ([]string, []string) {
+	var stdout, stderr []string
+	for _, entry := range decodeLogs(logs) {
+		line := entry.Text
+		if entry.Json != nil && line == "" {
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(entry.Json); err != nil {
+				continue
+			}
+			line = strings.TrimSuffix(buf.String(), "\n")
+		}
+		switch entry.Source {
+		case stdErrSource:
+			stderr = append(stderr, line)
+		default:
+			stdout = append(stdout, line)
+		}
+	}
+	return stdout, stderr
+}
+
+// toV1Outputs collects the '{"output": {...}}' lines which were supported by v1 autopilots
+func toV1Outputs(logs []string) map[string]string {
+	outputs := make(map[string]string)
+	for _, entry := range decodeLogs(logs) {
+		if entry.Source != stdOutSource {
+			continue
+		}
+		values, ok := entry.Json[jsonLogOutputKey].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range values {
+			outputs[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	return outputs
+}
+
+func decodeLogs(logs []string) []model.LogEntry {
+	var entries []model.LogEntry
+	for _, log := range logs {
+		var entry model.LogEntry
+		decoder := json.NewDecoder(bytes.NewReader([]byte(log)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&entry); err != nil {
+			entry = model.LogEntry{Source: stdOutSource, Text: log}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func toV1Date(date string) string {
+	parsed, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date
+	}
+	return parsed.Format(v1DateFormat)
+}
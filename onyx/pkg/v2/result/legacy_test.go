@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	v1 "github.com/B-S-F/yaku/onyx/pkg/result/v1"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToV1(t *testing.T) {
+	res := Result{
+		Metadata:      Metadata{Version: "v2"},
+		Header:        Header{Name: "test", Version: "1.0", Date: "2024-06-01T12:30:00Z", ToolVersion: "0.1.0"},
+		OverallStatus: "RED",
+		Chapters: map[string]*Chapter{
+			"1": {
+				Title:  "chapter 1",
+				Status: "RED",
+				Requirements: map[string]*Requirement{
+					"1": {
+						Title:  "requirement 1",
+						Status: "RED",
+						Checks: map[string]*Check{
+							"1": {
+								Title:      "automated",
+								Type:       "automation",
+								Autopilots: []Autopilot{{Name: "checker"}},
+								Evaluation: Evaluation{
+									Status: "RED",
+									Reason: "criterion not fulfilled",
+									Results: []EvaluationResult{
+										{Hash: "123", Criterion: "criterion", Justification: "justification"},
+									},
+									Logs: []string{
+										`{"source":"stdout","text":"log1"}`,
+										`{"source":"stdout","json":{"output":{"count":3,"name":"out"}}}`,
+										`{"source":"stderr","text":"error1"}`,
+										`{"source":"stdout","json":{"reason":"some reason"},"text":"{\"reason\": \"some reason\"}"}`,
+									},
+									ExitCode: 1,
+								},
+							},
+							"2": {
+								Title:      "answered",
+								Type:       "manual",
+								Evaluation: Evaluation{Status: "GREEN", Reason: "reviewed"},
+							},
+							"3": {
+								Title:      "unanswered",
+								Type:       "manual",
+								Evaluation: Evaluation{Status: "UNANSWERED", Reason: "not answered"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Finalize: &Finalize{
+			Logs:     []string{`{"source":"stdout","text":"finalized"}`},
+			ExitCode: 0,
+		},
+	}
+
+	legacy := ToV1(res)
+
+	assert.Equal(t, v1.Metadata{Version: "v1"}, legacy.Metadata)
+	assert.Equal(t, v1.Header{Name: "test", Version: "1.0", Date: "2024-06-01 12:30", ToolVersion: "0.1.0"}, legacy.Header)
+	assert.Equal(t, "RED", legacy.OverallStatus)
+	assert.Equal(t, v1.Statistics{
+		CountChecks:           3,
+		CountAutomatedChecks:  1,
+		CountManualChecks:     1,
+		CountUnansweredChecks: 1,
+		PercentageAutomated:   33.33,
+		PercentageDone:        66.67,
+	}, legacy.Statistics)
+	checks := legacy.Chapters["1"].Requirements["1"].Checks
+	assert.Equal(t, &v1.Check{
+		Title:  "automated",
+		Status: "RED",
+		Type:   "Automation",
+		Evaluation: v1.CheckResult{
+			Autopilot: "checker",
+			Status:    "RED",
+			Reason:    "criterion not fulfilled",
+			Results:   []v1.AutopilotResult{{Hash: "123", Criterion: "criterion", Justification: "justification"}},
+			Outputs:   map[string]string{"count": "3", "name": "out"},
+			Execution: v1.ExecutionInformation{
+				Logs:         []string{"log1", `{"output":{"count":3,"name":"out"}}`, `{"reason": "some reason"}`},
+				ErrorLogs:    []string{"error1"},
+				EvidencePath: "1_1_1",
+				ExitCode:     1,
+			},
+		},
+	}, checks["1"])
+	assert.Equal(t, &v1.Check{
+		Title:      "answered",
+		Status:     "GREEN",
+		Type:       "Manual",
+		Evaluation: v1.CheckResult{Status: "GREEN", Reason: "reviewed"},
+	}, checks["2"])
+	assert.Equal(t, "UNANSWERED", checks["3"].Status)
+	assert.Equal(t, &v1.Finalize{Execution: v1.ExecutionInformation{Logs: []string{"finalized"}, EvidencePath: "."}}, legacy.Finalize)
+}
+
+func TestCreatorWithOutputLines(t *testing.T) {
+	logs := []model.LogEntry{
+		{Source: "stdout", Text: "log1"},
+		{Source: "stdout", Json: map[string]interface{}{"status": "FAILED", "reason": "some reason"}, Line: `{"status": "FAILED", "reason": "some reason"}`},
+	}
+
+	lines, err := New(logger.Get()).WithOutputLines().marshalLogs(logs)
+	require.NoError(t, err)
+	legacyLogs, _ := toV1Logs(lines)
+
+	assert.Equal(t, []string{"log1", `{"status": "FAILED", "reason": "some reason"}`}, legacyLogs)
+}
+
+func TestPriorityStatusFailed(t *testing.T) {
+	assert.Equal(t, "FAILED", PriorityStatus("RED", "FAILED"))
+	assert.Equal(t, "FAILED", PriorityStatus("FAILED", "GREEN"))
+	assert.Equal(t, "ERROR", PriorityStatus("FAILED", "ERROR"))
+}
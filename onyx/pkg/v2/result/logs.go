@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+// LogMode determines how logs are provided in the result
+type LogMode string
+
+const (
+	// LogModeFull embeds the logs in the result
+	LogModeFull LogMode = "full"
+	// LogModeReference stores the logs as files in the evidence and references them in the result
+	LogModeReference LogMode = "reference"
+	// LogModeNone leaves out the logs, warnings and messages are still part of the result
+	LogModeNone LogMode = "none"
+
+	logsFile = "logs.jsonl"
+)
+
+// ParseLogMode returns the log mode with the given name, an empty name is the full mode
+func ParseLogMode(mode string) (LogMode, error) {
+	switch LogMode(mode) {
+	case "", LogModeFull:
+		return LogModeFull, nil
+	case LogModeReference, LogModeNone:
+		return LogMode(mode), nil
+	}
+	return "", errors.Errorf("result-logs should be one of '%s', '%s' or '%s'", LogModeFull, LogModeReference, LogModeNone)
+}
+
+// WithLogs sets how logs are provided in the result, referenced logs are stored in evidenceDir
+func (c *Creator) WithLogs(mode LogMode, evidenceDir string) *Creator {
+	c.logMode = mode
+	c.evidenceDir = evidenceDir
+	return c
+}
+
+// logs returns the logs to embed in the result or the reference to the file the logs were stored in.
+// dir is the slash separated directory of the file relative to the evidence directory.
+func (c *Creator) logs(logs []model.LogEntry, dir string) ([]string, *LogsReference, error) {
+	lines, err := c.marshalLogs(logs)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch c.logMode {
+	case LogModeNone:
+		return nil, nil, nil
+	case LogModeReference:
+		if len(lines) == 0 {
+			return nil, nil, nil
+		}
+		ref, err := c.writeLogs(lines, path.Join(dir, logsFile))
+		return nil, ref, err
+	}
+	return lines, nil, nil
+}
+
+func (c *Creator) writeLogs(lines []string, name string) (*LogsReference, error) {
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	target := filepath.Join(c.evidenceDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory for logs '%s'", name)
+	}
+	if err := os.WriteFile(target, content, 0644); err != nil {
+		return nil, errors.Wrapf(err, "failed to write logs '%s'", name)
+	}
+	hash := sha256.Sum256(content)
+	return &LogsReference{
+		Path:   name,
+		Size:   int64(len(content)),
+		SHA256: hex.EncodeToString(hash[:]),
+	}, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogMode(t *testing.T) {
+	testCases := map[string]struct {
+		mode    string
+		want    LogMode
+		wantErr string
+	}{
+		"should default to full":  {mode: "", want: LogModeFull},
+		"should accept reference": {mode: "reference", want: LogModeReference},
+		"should accept none":      {mode: "none", want: LogModeNone},
+		"should reject unknown modes": {
+			mode:    "partial",
+			wantErr: "result-logs should be one of 'full', 'reference' or 'none'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mode, err := ParseLogMode(tc.mode)
+
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, mode)
+		})
+	}
+}
+
+func TestCreatorLogModes(t *testing.T) {
+	run := model.FinalizerRun{
+		Finalize: model.Finalize{Name: "tickets"},
+		Status:   "SUCCEEDED",
+		Result: &model.FinalizeResult{
+			Logs: []model.LogEntry{
+				{Source: "stdout", Text: "created 2 tickets"},
+				{Source: "stdout", Json: map[string]interface{}{"warning": "ticket already exists"}},
+			},
+		},
+	}
+	logs := "{\"source\":\"stdout\",\"text\":\"created 2 tickets\"}\n{\"source\":\"stdout\",\"json\":{\"warning\":\"ticket already exists\"}}\n"
+	testCases := map[string]struct {
+		mode        LogMode
+		wantLogs    []string
+		wantLogsRef *LogsReference
+	}{
+		"should embed logs": {
+			mode: LogModeFull,
+			wantLogs: []string{
+				"{\"source\":\"stdout\",\"text\":\"created 2 tickets\"}",
+				"{\"source\":\"stdout\",\"json\":{\"warning\":\"ticket already exists\"}}",
+			},
+		},
+		"should reference logs": {
+			mode: LogModeReference,
+			wantLogsRef: &LogsReference{
+				Path:   "finalizers/tickets/logs.jsonl",
+				Size:   int64(len(logs)),
+				SHA256: "acc43051f9e51569d672d7d999192c5410992c992eed4bd0e0af05341dad4d12",
+			},
+		},
+		"should leave out logs": {
+			mode: LogModeNone,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			c := New(logger.NewAutopilot()).WithLogs(tc.mode, dir)
+			res := &Result{}
+
+			err := c.AppendFinalizerRun(res, run)
+
+			require.NoError(t, err)
+			finalizer := res.Finalizers[0]
+			assert.Equal(t, tc.wantLogs, finalizer.Logs)
+			assert.Equal(t, []string{"ticket already exists"}, finalizer.Warnings)
+			if tc.wantLogsRef == nil {
+				assert.Nil(t, finalizer.LogsRef)
+				assert.NoFileExists(t, filepath.Join(dir, "finalizers", "tickets", "logs.jsonl"))
+				return
+			}
+			assert.Equal(t, tc.wantLogsRef, finalizer.LogsRef)
+			content, err := os.ReadFile(filepath.Join(dir, finalizer.LogsRef.Path))
+			require.NoError(t, err)
+			assert.Equal(t, logs, string(content))
+		})
+	}
+}
@@ -13,13 +13,29 @@ type Result struct {
 	// Header of the result
 	Header Header `yaml:"header" json:"header" jsonschema:"required"`
 	// Overall status of the result (is composed of the status of the chapters)
-	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=SKIPPED,enum=UNANSWERED,enum=ERROR"`
 	// Statistics of the result
 	Statistics Statistics `yaml:"statistics" json:"statistics" jsonschema:"required"`
+	// Statistics of the checks by their labels
+	LabelStatistics map[string]*Statistics `yaml:"labelStatistics,omitempty" json:"labelStatistics" jsonschema:"optional"`
 	// Chapters containing requirements and checks
 	Chapters map[string]*Chapter `yaml:"chapters" json:"chapters" jsonschema:"required"`
+	// Outcomes of the background services in order of their start
+	Services []Service `yaml:"services,omitempty" json:"services" jsonschema:"optional"`
+	// Outcomes of the setup steps in order of execution
+	Setup []Finalizer `yaml:"setup,omitempty" json:"setup" jsonschema:"optional"`
 	// Finalize step
 	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize" jsonschema:"optional"`
+	// Outcomes of the named finalizers in order of execution
+	Finalizers []Finalizer `yaml:"finalizers,omitempty" json:"finalizers" jsonschema:"optional"`
+	// Outcomes of the teardown steps in order of execution
+	Teardown []Finalizer `yaml:"teardown,omitempty" json:"teardown" jsonschema:"optional"`
+	// Annotations added to the run by finalizers
+	Annotations []Annotation `yaml:"annotations,omitempty" json:"annotations" jsonschema:"optional"`
+	// Links added to the run by finalizers
+	Links []Link `yaml:"links,omitempty" json:"links" jsonschema:"optional"`
+	// Outcomes of the notifications
+	Notifications []Notification `yaml:"notifications,omitempty" json:"notifications" jsonschema:"optional"`
 }
 
 // Contains the metadata of the result
@@ -43,6 +59,15 @@ type Header struct {
 	// Version of the onyx cli tool
 	// Example "0.1.0"
 	ToolVersion string `yaml:"toolVersion" json:"toolVersion" jsonschema:"required"`
+	// Path of the provenance of the run in the evidence
+	// Example "provenance.json"
+	Provenance string `yaml:"provenance,omitempty" json:"provenance" jsonschema:"optional"`
+	// Path of the CycloneDX SBOM of the installed apps in the evidence
+	// Example "sbom.cdx.json"
+	SBOM string `yaml:"sbom,omitempty" json:"sbom" jsonschema:"optional"`
+	// Path of the RFC 3161 timestamp token of the evidence manifest in the evidence
+	// Example "evidence-manifest.tsr"
+	Timestamp string `yaml:"timestamp,omitempty" json:"timestamp" jsonschema:"optional"`
 }
 
 // Contains statistics about the result
@@ -57,10 +82,21 @@ type Statistics struct {
 	CountUnansweredChecks uint `yaml:"counted-unanswered-checks" json:"counted-unanswered-checks" jsonschema:"required"`
 	// Number of skipped checks
 	CountSkippedChecks uint `yaml:"counted-skipped-checks" json:"counted-skipped-checks" jsonschema:"required"`
+	// Number of not applicable checks
+	CountNAChecks uint `yaml:"counted-na-checks" json:"counted-na-checks" jsonschema:"required"`
 	// Percentage of automated checks
 	PercentageAutomated float64 `yaml:"degree-of-automation" json:"degree-of-automation" jsonschema:"required"`
 	// Percentage of answered checks
 	PercentageDone float64 `yaml:"degree-of-completion" json:"degree-of-completion" jsonschema:"required"`
+	// Number of checks by their status
+	// Example
+	// 	GREEN: 12
+	// 	RED: 1
+	CountChecksByStatus map[string]uint `yaml:"counted-checks-by-status,omitempty" json:"counted-checks-by-status" jsonschema:"optional"`
+	// Number of criteria evaluated by autopilots
+	CountCriteria uint `yaml:"counted-criteria,omitempty" json:"counted-criteria" jsonschema:"optional"`
+	// Number of criteria evaluated by autopilots which are not fulfilled
+	CountFailedCriteria uint `yaml:"counted-failed-criteria,omitempty" json:"counted-failed-criteria" jsonschema:"optional"`
 }
 
 // Contains information about a chapter
@@ -73,9 +109,15 @@ type Chapter struct {
 	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
 	// Status of the chapter (is composed of the status of the requirements)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=SKIPPED,enum=UNANSWERED,enum=ERROR"`
+	// Statistics of the checks of the chapter
+	Statistics *Statistics `yaml:"statistics,omitempty" json:"statistics" jsonschema:"optional"`
 	// Requirements to answer the chapter
 	Requirements map[string]*Requirement `yaml:"requirements" json:"requirements" jsonschema:"required"`
+	// Annotations added to the chapter by finalizers
+	Annotations []Annotation `yaml:"annotations,omitempty" json:"annotations" jsonschema:"optional"`
+	// Links added to the chapter by finalizers
+	Links []Link `yaml:"links,omitempty" json:"links" jsonschema:"optional"`
 }
 
 // Contains information about a requirement
@@ -88,7 +130,9 @@ type Requirement struct {
 	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
 	// Status of the requirement (is composed of the status of the checks)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=SKIPPED,enum=UNANSWERED,enum=ERROR"`
+	// Statistics of the checks of the requirement
+	Statistics *Statistics `yaml:"statistics,omitempty" json:"statistics" jsonschema:"optional"`
 	// Checks to answer the requirement
 	Checks map[string]*Check `yaml:"checks,omitempty" json:"checks" jsonschema:"required"`
 }
@@ -101,10 +145,26 @@ type Check struct {
 	// Type of the check
 	// Example "autopilot"
 	Type string `yaml:"type" json:"type" jsonschema:"required,enum=automation,enum=manual"`
+	// Labels of the check
+	// Example ["security", "release-blocker"]
+	Labels []string `yaml:"labels,omitempty" json:"labels" jsonschema:"optional"`
+	// Former IDs of the check as given in the configuration
+	// Example ["1", "sbom-check"]
+	PreviousIds []string `yaml:"previousIds,omitempty" json:"previousIds" jsonschema:"optional"`
 	// Evaluation of the check containing the result
 	Autopilots []Autopilot `yaml:"autopilots,omitempty" json:"autopilots" jsonschema:"optional"`
 	// Evaluation of the autopilot
 	Evaluation Evaluation `yaml:"evaluation" json:"evaluation" jsonschema:"required"`
+	// Date after which the manual answer has to be reviewed again, only set for manual checks
+	// Example "2024-12-31"
+	Expires string `yaml:"expires,omitempty" json:"expires" jsonschema:"optional"`
+	// Annotations added to the check by finalizers
+	Annotations []Annotation `yaml:"annotations,omitempty" json:"annotations" jsonschema:"optional"`
+	// Links added to the check by finalizers
+	Links []Link `yaml:"links,omitempty" json:"links" jsonschema:"optional"`
+	// Duration of the autopilot run in seconds, only set for automated checks
+	// Example 12.5
+	Duration float64 `yaml:"duration,omitempty" json:"duration" jsonschema:"optional"`
 }
 
 // Contains the results of a check
@@ -130,6 +190,8 @@ type Step struct {
 	// - '{"source": "stdout", "json": {"message": "I am a message"}}'
 	// - '{"source": "stderr", "text": "some error log"}'
 	Logs []string `yaml:"logs" json:"logs" jsonschema:"required"`
+	// Reference to the logs stored in the evidence, only set if the logs are not embedded
+	LogsRef *LogsReference `yaml:"logsRef,omitempty" json:"logsRef" jsonschema:"optional"`
 	// Warning messages of the Step execution, derived from the generated structured logs
 	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
 	// General info messages of the Step execution, derived from the generated structured logs
@@ -144,13 +206,15 @@ type Step struct {
 	InputDirs []string `yaml:"inputDirs" json:"inputDirs" jsonschema:"optional"`
 	// Exit code of the step
 	ExitCode int `yaml:"exitCode" json:"exitCode" jsonschema:"required"`
+	// Hosts the step connected to through the egress proxy
+	Destinations []Destination `yaml:"destinations,omitempty" json:"destinations" jsonschema:"optional"`
 }
 
 // Contains the evaluation of an autopilot
 type Evaluation struct {
 	// Status of the autopilot
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=SKIPPED,enum=UNANSWERED,enum=ERROR"`
 	// Reason associated with the status
 	// Example "This is my reason"
 	Reason string `yaml:"reason" json:"reason" jsonschema:"required"`
@@ -163,6 +227,8 @@ type Evaluation struct {
 	// - '{"source": "stdout", "json": {"message": "I am a message"}}'
 	// - '{"source": "stderr", "text": "some error log"}'
 	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"required"`
+	// Reference to the logs stored in the evidence, only set if the logs are not embedded
+	LogsRef *LogsReference `yaml:"logsRef,omitempty" json:"logsRef" jsonschema:"optional"`
 	// Warning messages of the evaluation execution, derived from the generated structured logs
 	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
 	// General info messages of the evaluation execution, derived from the generated structured logs
@@ -175,9 +241,15 @@ type Evaluation struct {
 
 // Contains one of potentially many results reported by an autopilot
 type EvaluationResult struct {
-	// Unique identifier for the Result, created from the Chapter, Requirement, Check, Criterion, and Justification.
+	// Unique identifier for the Result, created from the Chapter, Requirement, Check, and the ID of the result.
+	// Results without ID are identified by their Criterion and Justification instead.
 	// Example "9319a093d48e7488ef34cd74ccfe5e2f23a00b32eede2ba30d39676f2029a528"
 	Hash string `yaml:"hash" json:"hash" jsonschema:"required"`
+	// Hashes of the result under the previous IDs of the check, to keep the history of renamed checks
+	PreviousHashes []string `yaml:"previousHashes,omitempty" json:"previousHashes" jsonschema:"optional"`
+	// Stable identifier of the result provided by the autopilot, e.g. the ID of a finding
+	// Example "CVE-2024-1234"
+	ID string `yaml:"id,omitempty" json:"id" jsonschema:"optional"`
 	// Criterion that was evaluated by the autopilot
 	// Example "My Criterion"
 	Criterion common.MultilineString `yaml:"criterion" json:"criterion" jsonschema:"required"`
@@ -192,6 +264,8 @@ type EvaluationResult struct {
 	// 	- "foo": "bar"
 	// 	- "baz": "qux"
 	Metadata common.StringMap `yaml:"metadata,omitempty" json:"metadata" jsonschema:"optional"`
+	// Files in the evidence which prove the result
+	Evidence []EvidenceReference `yaml:"evidence,omitempty" json:"evidence" jsonschema:"optional"`
 }
 
 // Contains information about the finalization
@@ -204,6 +278,8 @@ type Finalize struct {
 	// - '{"source": "stdout", "json": {"message": "I am a message"}}'
 	// - '{"source": "stderr", "text": "some error log"}'
 	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"optional"`
+	// Reference to the logs stored in the evidence, only set if the logs are not embedded
+	LogsRef *LogsReference `yaml:"logsRef,omitempty" json:"logsRef" jsonschema:"optional"`
 	// Warning messages of the Finalize execution, derived from the generated structured logs
 	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
 	// General info messages of the Finalize execution, derived from the generated structured logs
@@ -214,6 +290,137 @@ type Finalize struct {
 	ExitCode int `yaml:"exitCode" json:"exitCode" jsonschema:"required"`
 }
 
+// Contains the outcome of a named finalizer
+type Finalizer struct {
+	// Name of the finalizer
+	// Example "create-tickets"
+	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Condition under which the finalizer is executed
+	// Example "status == 'RED'"
+	If string `yaml:"if,omitempty" json:"if" jsonschema:"optional"`
+	// Status of the finalizer execution
+	// Example "SUCCEEDED"
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=SUCCEEDED,enum=FAILED,enum=SKIPPED,enum=ERROR"`
+	// Reason associated with the status
+	// Example "condition 'status == 'RED'' evaluated to false"
+	Reason string `yaml:"reason,omitempty" json:"reason" jsonschema:"optional"`
+	// Structured logs from the execution of the finalizer
+	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"optional"`
+	// Reference to the logs stored in the evidence, only set if the logs are not embedded
+	LogsRef *LogsReference `yaml:"logsRef,omitempty" json:"logsRef" jsonschema:"optional"`
+	// Warning messages of the finalizer execution, derived from the generated structured logs
+	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
+	// General info messages of the finalizer execution, derived from the generated structured logs
+	Messages []string `yaml:"messages,omitempty" json:"messages" jsonschema:"optional"`
+	// Configuration files of the finalizer
+	ConfigFiles []string `yaml:"configFiles,omitempty" json:"configFiles" jsonschema:"optional"`
+	// Exit code of the finalizer
+	ExitCode int `yaml:"exitCode" json:"exitCode" jsonschema:"required"`
+}
+
+// Contains a key value pair added by a finalizer, e.g. the ID of a created ticket
+type Annotation struct {
+	// Key of the annotation
+	// Example "jira-ticket"
+	Key string `yaml:"key" json:"key" jsonschema:"required,minLength=1"`
+	// Value of the annotation
+	// Example "QG-1234"
+	Value string `yaml:"value" json:"value" jsonschema:"required"`
+	// Name of the finalizer which added the annotation
+	// Example "create-tickets"
+	Source string `yaml:"source" json:"source" jsonschema:"required"`
+}
+
+// Contains a link added by a finalizer, e.g. to an uploaded report
+type Link struct {
+	// Title of the link
+	// Example "Uploaded report"
+	Title string `yaml:"title,omitempty" json:"title" jsonschema:"optional"`
+	// Absolute http(s) URL
+	// Example "https://sharepoint.com/sites/qg/report.html"
+	URL string `yaml:"url" json:"url" jsonschema:"required,format=uri"`
+	// Name of the finalizer which added the link
+	// Example "publish-report"
+	Source string `yaml:"source" json:"source" jsonschema:"required"`
+}
+
+// Contains the outcome of a notification
+type Notification struct {
+	// Name of the notification
+	// Example "team-channel"
+	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Type of the notification
+	// Example "slack"
+	Type string `yaml:"type" json:"type" jsonschema:"required,enum=smtp,enum=webhook,enum=teams,enum=slack"`
+	// Status of the notification
+	// Example "SENT"
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=SENT,enum=SKIPPED,enum=FAILED"`
+	// Reason associated with the status
+	// Example "overall status is 'RED'"
+	Reason string `yaml:"reason,omitempty" json:"reason" jsonschema:"optional"`
+}
+
+type Service struct {
+	// Name of the service
+	// Example "mock-server"
+	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Status of the service at the end of the run
+	// Example "STOPPED"
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=STOPPED,enum=CRASHED,enum=FAILED"`
+	// Reason associated with the status
+	// Example "service 'mock-server' exited with exit code 1"
+	Reason string `yaml:"reason,omitempty" json:"reason" jsonschema:"optional"`
+	// Number of restarts during the run
+	Restarts int `yaml:"restarts,omitempty" json:"restarts" jsonschema:"optional"`
+	// Path of the service logs in the evidence
+	// Example "service_mock-server.log"
+	LogFile string `yaml:"logFile" json:"logFile" jsonschema:"required"`
+}
+
 func (r *Result) version() string {
 	return "v2"
 }
+
+// Reference to a file in the evidence which proves a result
+type EvidenceReference struct {
+	// Path of the file relative to the evidence
+	// Example "1_2_3/steps/fetch/files/report.json"
+	Path string `yaml:"path" json:"path" jsonschema:"required"`
+	// JSON pointer to the relevant part of the file
+	// Example "/items/3"
+	Pointer string `yaml:"pointer,omitempty" json:"pointer" jsonschema:"optional"`
+	// Size of the file in bytes
+	// Example 2048
+	Size int64 `yaml:"size" json:"size" jsonschema:"required"`
+	// SHA-256 hash of the file
+	// Example "9319a093d48e7488ef34cd74ccfe5e2f23a00b32eede2ba30d39676f2029a528"
+	SHA256 string `yaml:"sha256" json:"sha256" jsonschema:"required"`
+}
+
+// Reference to logs which are stored as file in the evidence, one JSON log entry per line
+type LogsReference struct {
+	// Path of the file relative to the evidence
+	// Example "1_2_3/steps/fetch/logs.jsonl"
+	Path string `yaml:"path" json:"path" jsonschema:"required"`
+	// Size of the file in bytes
+	// Example 2048
+	Size int64 `yaml:"size" json:"size" jsonschema:"required"`
+	// SHA-256 hash of the file
+	// Example "9319a093d48e7488ef34cd74ccfe5e2f23a00b32eede2ba30d39676f2029a528"
+	SHA256 string `yaml:"sha256" json:"sha256" jsonschema:"required"`
+}
+
+// Host a step connected to through the egress proxy
+type Destination struct {
+	// Host name or IP address
+	// Example "api.github.com"
+	Host string `yaml:"host" json:"host" jsonschema:"required"`
+	// Port
+	// Example 443
+	Port int `yaml:"port" json:"port" jsonschema:"required"`
+	// Number of requests and tunnels to the destination
+	// Example 3
+	Requests int `yaml:"requests" json:"requests" jsonschema:"required"`
+	// Set if the destination was rejected because it is not allowed by the network settings of the autopilot
+	Denied bool `yaml:"denied,omitempty" json:"denied" jsonschema:"optional"`
+}
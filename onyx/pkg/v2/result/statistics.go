@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+// addStatistics calculates the statistics of the requirements, chapters and labels and of the whole result from its checks
+func addStatistics(res *Result) {
+	res.Statistics = Statistics{}
+	labels := make(map[string]*Statistics)
+	for _, chapter := range res.Chapters {
+		chapterStatistics := Statistics{}
+		for _, requirement := range chapter.Requirements {
+			requirementStatistics := Statistics{}
+			for _, check := range requirement.Checks {
+				requirementStatistics.addCheck(check)
+				for _, label := range check.Labels {
+					if labels[label] == nil {
+						labels[label] = &Statistics{}
+					}
+					labels[label].addCheck(check)
+				}
+			}
+			requirementStatistics.calculatePercentages()
+			requirement.Statistics = &requirementStatistics
+			chapterStatistics.add(requirementStatistics)
+		}
+		chapterStatistics.calculatePercentages()
+		chapter.Statistics = &chapterStatistics
+		res.Statistics.add(chapterStatistics)
+	}
+	res.Statistics.calculatePercentages()
+	for _, statistics := range labels {
+		statistics.calculatePercentages()
+	}
+	if len(labels) > 0 {
+		res.LabelStatistics = labels
+	}
+}
+
+// addCheck counts the check, unanswered checks can only be manual checks
+func (s *Statistics) addCheck(check *Check) {
+	status := check.Evaluation.Status
+	s.CountChecks++
+	if check.Type == "manual" {
+		s.CountManualChecks++
+		if status == unansweredStatus {
+			s.CountUnansweredChecks++
+		}
+	} else {
+		s.CountAutomatedChecks++
+	}
+	switch status {
+	case skippedStatus:
+		s.CountSkippedChecks++
+	case naStatus:
+		s.CountNAChecks++
+	}
+	if status != "" {
+		if s.CountChecksByStatus == nil {
+			s.CountChecksByStatus = make(map[string]uint)
+		}
+		s.CountChecksByStatus[status]++
+	}
+	for _, result := range check.Evaluation.Results {
+		s.CountCriteria++
+		if !result.Fulfilled {
+			s.CountFailedCriteria++
+		}
+	}
+}
+
+// add adds the counts of other, the percentages have to be calculated afterwards
+func (s *Statistics) add(other Statistics) {
+	s.CountChecks += other.CountChecks
+	s.CountAutomatedChecks += other.CountAutomatedChecks
+	s.CountManualChecks += other.CountManualChecks
+	s.CountUnansweredChecks += other.CountUnansweredChecks
+	s.CountSkippedChecks += other.CountSkippedChecks
+	s.CountNAChecks += other.CountNAChecks
+	s.CountCriteria += other.CountCriteria
+	s.CountFailedCriteria += other.CountFailedCriteria
+	for status, count := range other.CountChecksByStatus {
+		if s.CountChecksByStatus == nil {
+			s.CountChecksByStatus = make(map[string]uint)
+		}
+		s.CountChecksByStatus[status] += count
+	}
+}
+
+func (s *Statistics) calculatePercentages() {
+	if s.CountChecks == 0 {
+		return
+	}
+	s.PercentageAutomated = getPercentage(s.CountAutomatedChecks, s.CountChecks)
+	s.PercentageDone = getPercentage(s.CountChecks-s.CountUnansweredChecks, s.CountChecks)
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStatistics(t *testing.T) {
+	check := func(checkType string, status string, fulfilled []bool, labels ...string) *Check {
+		c := &Check{Type: checkType, Labels: labels, Evaluation: Evaluation{Status: status}}
+		for _, f := range fulfilled {
+			c.Evaluation.Results = append(c.Evaluation.Results, EvaluationResult{Fulfilled: f})
+		}
+		return c
+	}
+	res := &Result{Chapters: map[string]*Chapter{
+		"1": {Requirements: map[string]*Requirement{
+			"1": {Checks: map[string]*Check{
+				"1": check("automation", "GREEN", []bool{true, true}, "security"),
+				"2": check("automation", "RED", []bool{true, false}, "security", "release"),
+			}},
+			"2": {Checks: map[string]*Check{
+				"1": check("manual", "UNANSWERED", nil, "release"),
+			}},
+		}},
+		"2": {Requirements: map[string]*Requirement{
+			"1": {Checks: map[string]*Check{
+				"1": check("automation", "NA", nil),
+				"2": check("manual", "SKIPPED", nil),
+			}},
+		}},
+	}}
+
+	addStatistics(res)
+
+	assert.Equal(t, Statistics{
+		CountChecks:           5,
+		CountAutomatedChecks:  3,
+		CountManualChecks:     2,
+		CountUnansweredChecks: 1,
+		CountSkippedChecks:    1,
+		CountNAChecks:         1,
+		PercentageAutomated:   60,
+		PercentageDone:        80,
+		CountChecksByStatus:   map[string]uint{"GREEN": 1, "RED": 1, "UNANSWERED": 1, "NA": 1, "SKIPPED": 1},
+		CountCriteria:         4,
+		CountFailedCriteria:   1,
+	}, res.Statistics)
+	assert.Equal(t, &Statistics{
+		CountChecks:          2,
+		CountAutomatedChecks: 2,
+		PercentageAutomated:  100,
+		PercentageDone:       100,
+		CountChecksByStatus:  map[string]uint{"GREEN": 1, "RED": 1},
+		CountCriteria:        4,
+		CountFailedCriteria:  1,
+	}, res.Chapters["1"].Requirements["1"].Statistics)
+	assert.Equal(t, &Statistics{
+		CountChecks:           3,
+		CountAutomatedChecks:  2,
+		CountManualChecks:     1,
+		CountUnansweredChecks: 1,
+		PercentageAutomated:   66.67,
+		PercentageDone:        66.67,
+		CountChecksByStatus:   map[string]uint{"GREEN": 1, "RED": 1, "UNANSWERED": 1},
+		CountCriteria:         4,
+		CountFailedCriteria:   1,
+	}, res.Chapters["1"].Statistics)
+	assert.Equal(t, &Statistics{
+		CountChecks:          2,
+		CountAutomatedChecks: 1,
+		CountManualChecks:    1,
+		CountSkippedChecks:   1,
+		CountNAChecks:        1,
+		PercentageAutomated:  50,
+		PercentageDone:       100,
+		CountChecksByStatus:  map[string]uint{"NA": 1, "SKIPPED": 1},
+	}, res.Chapters["2"].Statistics)
+	assert.Equal(t, map[string]*Statistics{
+		"security": {
+			CountChecks:          2,
+			CountAutomatedChecks: 2,
+			PercentageAutomated:  100,
+			PercentageDone:       100,
+			CountChecksByStatus:  map[string]uint{"GREEN": 1, "RED": 1},
+			CountCriteria:        4,
+			CountFailedCriteria:  1,
+		},
+		"release": {
+			CountChecks:           2,
+			CountAutomatedChecks:  1,
+			CountManualChecks:     1,
+			CountUnansweredChecks: 1,
+			PercentageAutomated:   50,
+			PercentageDone:        50,
+			CountChecksByStatus:   map[string]uint{"RED": 1, "UNANSWERED": 1},
+			CountCriteria:         2,
+			CountFailedCriteria:   1,
+		},
+	}, res.LabelStatistics)
+}
+
+func TestAddStatisticsWithoutLabels(t *testing.T) {
+	res := &Result{Chapters: map[string]*Chapter{
+		"1": {Requirements: map[string]*Requirement{
+			"1": {Checks: map[string]*Check{"1": {Type: "automation", Evaluation: Evaluation{Status: "GREEN"}}}},
+		}},
+	}}
+
+	addStatistics(res)
+
+	assert.Nil(t, res.LabelStatistics)
+	assert.Equal(t, uint(1), res.Statistics.CountChecks)
+}
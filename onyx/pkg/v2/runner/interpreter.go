@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/netflix/go-iomux"
+	errs "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// killTimeout is the time external commands get to exit after they were interrupted before they are killed
+const killTimeout = 2 * time.Second
+
+// Interpreter runs scripts with an embedded shell interpreter instead of /bin/bash.
+// Only the script itself is interpreted, external commands are executed as usual.
+type Interpreter struct {
+	logger logger.Logger
+}
+
+func NewInterpreter(logger logger.Logger) *Interpreter {
+	return &Interpreter{
+		logger: logger,
+	}
+}
+
+// ParseScript parses a bash script, the returned error contains the line and column of a syntax error
+func ParseScript(script, name string) (*syntax.File, error) {
+	return syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(script), name)
+}
+
+func (i *Interpreter) Execute(input *Input, timeout time.Duration) (*Output, error) {
+	if len(input.Args) < 2 || input.Args[0] != "-c" {
+		return nil, errs.Errorf("the embedded shell can only run scripts passed with '-c', got arguments %v", input.Args)
+	}
+	out := &Output{WorkDir: input.WorkDir}
+	script, err := ParseScript(input.Args[1], "run")
+	if err != nil {
+		// bash also exits with 2 if a script can't be parsed
+		out.Logs = append(out.Logs, model.LogEntry{Source: stdErrSourceType, Text: err.Error()})
+		out.ExitCode = 2
+		return out, nil
+	}
+
+	if timeout <= 0 {
+		i.logger.Warnf("Timeout is set to '%s'. Please make sure this is intended.", timeout)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mux := iomux.NewMuxUnixGram[string]()
+	defer mux.Close()
+	stdout, _ := mux.Tag(stdOutSourceType)
+	stderr, _ := mux.Tag(stdErrSourceType)
+
+	environ := os.Environ()
+	for k, v := range input.Env {
+		environ = append(environ, fmt.Sprintf("%s=%s", k, v))
+	}
+	shell, err := newShell(input.WorkDir, environ, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	i.logger.Debug("Starting embedded shell", zap.Strings("args", input.Args))
+	chunks, err := mux.ReadWhile(func() error {
+		out.ExitCode = i.run(ctx, shell, script)
+		return nil
+	})
+	if err != nil {
+		return nil, errs.Wrap(err, "Failed to read command response")
+	}
+
+	demuxLogs(input, out, chunks)
+
+	if out.ExitCode == 124 {
+		out.Logs = append(out.Logs, model.LogEntry{Source: stdErrSourceType, Text: fmt.Sprintf("Command timed out after %s", timeout)})
+	}
+
+	return out, nil
+}
+
+// RunScript runs a script which is not run as autopilot, e.g. of a service, until it exits or ctx is done.
+// It returns the exit code of the script, its output and syntax errors are written to out.
+func (i *Interpreter) RunScript(ctx context.Context, script, dir string, environ []string, out io.Writer) int {
+	parsed, err := ParseScript(script, "run")
+	if err != nil {
+		fmt.Fprintln(out, err.Error())
+		return 2
+	}
+	shell, err := newShell(dir, environ, out, out)
+	if err != nil {
+		fmt.Fprintln(out, err.Error())
+		return -1
+	}
+	return i.run(ctx, shell, parsed)
+}
+
+// newShell returns an embedded shell which runs scripts in dir with the environment environ
+func newShell(dir string, environ []string, stdout, stderr io.Writer) (*interp.Runner, error) {
+	options := []interp.RunnerOption{
+		interp.Env(expand.ListEnviron(environ...)),
+		interp.StdIO(nil, stdout, stderr),
+		interp.ExecHandler(interp.DefaultExecHandler(killTimeout)),
+	}
+	if dir != "" {
+		options = append(options, interp.Dir(dir))
+	}
+	shell, err := interp.New(options...)
+	if err != nil {
+		return nil, errs.Wrap(err, "Failed to initialize embedded shell")
+	}
+	return shell, nil
+}
+
+func (i *Interpreter) run(ctx context.Context, shell *interp.Runner, script *syntax.File) int {
+	err := shell.Run(ctx, script)
+	if ctx.Err() == context.DeadlineExceeded {
+		i.logger.Debug("Embedded shell timed out")
+		return 124
+	}
+	if ctx.Err() == context.Canceled {
+		// like bash terminated by SIGTERM
+		i.logger.Debug("Embedded shell was canceled")
+		return 143
+	}
+	i.logger.Debug("Embedded shell finished", zap.Error(err))
+	if err != nil {
+		if status, ok := interp.IsExitStatus(err); ok {
+			return int(status)
+		}
+		i.logger.Errorf("Unknown error while executing script: %s", err)
+		return -1
+	}
+	return 0
+}
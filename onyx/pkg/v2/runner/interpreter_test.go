@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpreterExecute(t *testing.T) {
+	tmpDir := t.TempDir()
+	testCases := map[string]struct {
+		input   *Input
+		timeout time.Duration
+		want    *Output
+	}{
+		"should return output with logs and json data": {
+			input: &Input{
+				Args:    []string{"-c", "echo hello $NAME\necho '{\"status\": \"GREEN\"}'"},
+				Env:     map[string]string{"NAME": "world"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				Logs: []model.LogEntry{
+					{Source: "stdout", Text: "hello world"},
+					{Source: "stdout", Json: map[string]interface{}{"status": "GREEN"}, Line: `{"status": "GREEN"}`},
+				},
+				JsonData: []map[string]interface{}{{"status": "GREEN"}},
+				WorkDir:  tmpDir,
+			},
+		},
+		"should return output with error logs": {
+			input: &Input{
+				Args:    []string{"-c", "1>&2 echo hello world"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				Logs:    []model.LogEntry{{Source: "stderr", Text: "hello world"}},
+				WorkDir: tmpDir,
+			},
+		},
+		"should hide secrets": {
+			input: &Input{
+				Args:    []string{"-c", "echo $TOKEN"},
+				Env:     map[string]string{"TOKEN": "secret-value"},
+				Secrets: map[string]string{"TOKEN": "secret-value"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				Logs:    []model.LogEntry{{Source: "stdout", Text: "***TOKEN***"}},
+				WorkDir: tmpDir,
+			},
+		},
+		"should run external commands in the work dir": {
+			input: &Input{
+				Args:    []string{"-c", "touch created.txt\nls"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				Logs:    []model.LogEntry{{Source: "stdout", Text: "created.txt"}},
+				WorkDir: tmpDir,
+			},
+		},
+		"should stop at the first failing command with set -e": {
+			input: &Input{
+				Args:    []string{"-c", "set -e\nfalse\necho unreachable"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				ExitCode: 1,
+				WorkDir:  tmpDir,
+			},
+		},
+		"should return exit code": {
+			input: &Input{
+				Args:    []string{"-c", "exit 3"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				ExitCode: 3,
+				WorkDir:  tmpDir,
+			},
+		},
+		"should return exit code 2 for syntax errors": {
+			input: &Input{
+				Args:    []string{"-c", "if true; then\necho missing fi"},
+				WorkDir: tmpDir,
+			},
+			timeout: time.Minute,
+			want: &Output{
+				Logs:     []model.LogEntry{{Source: "stderr", Text: "run:1:1: if statement must end with \"fi\""}},
+				ExitCode: 2,
+				WorkDir:  tmpDir,
+			},
+		},
+		"should time out": {
+			input: &Input{
+				Args:    []string{"-c", "sleep 5"},
+				WorkDir: tmpDir,
+			},
+			timeout: 100 * time.Millisecond,
+			want: &Output{
+				Logs:     []model.LogEntry{{Source: "stderr", Text: "Command timed out after 100ms"}},
+				ExitCode: 124,
+				WorkDir:  tmpDir,
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			i := NewInterpreter(nopLogger)
+			got, err := i.Execute(tc.input, tc.timeout)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			_ = os.Remove(filepath.Join(tmpDir, "created.txt"))
+		})
+	}
+}
+
+func TestInterpreterExecuteRequiresScript(t *testing.T) {
+	i := NewInterpreter(nopLogger)
+	_, err := i.Execute(&Input{Cmd: "/bin/bash", Args: []string{"script.sh"}}, time.Minute)
+	assert.ErrorContains(t, err, "the embedded shell can only run scripts passed with '-c'")
+}
+
+func TestInterpreterRunScript(t *testing.T) {
+	testCases := map[string]struct {
+		script   string
+		cancel   bool
+		exitCode int
+		output   string
+	}{
+		"should write output and return exit code": {
+			script:   "echo hello $NAME\necho error >&2\nexit 3",
+			exitCode: 3,
+			output:   "hello world\nerror\n",
+		},
+		"should write syntax errors": {
+			script:   "echo $(",
+			exitCode: 2,
+			output:   "run:1:6: reached EOF without matching ( with )\n",
+		},
+		"should stop canceled script": {
+			script:   "sleep 30",
+			cancel:   true,
+			exitCode: 143,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				time.AfterFunc(100*time.Millisecond, cancel)
+			}
+			var out bytes.Buffer
+
+			exitCode := NewInterpreter(nopLogger).RunScript(ctx, tc.script, t.TempDir(), append(os.Environ(), "NAME=world"), &out)
+
+			assert.Equal(t, tc.exitCode, exitCode)
+			assert.Equal(t, tc.output, out.String())
+		})
+	}
+}
@@ -7,6 +7,7 @@ package runner
 import (
 	"time"
 
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
 	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
 )
 
@@ -33,3 +34,18 @@ type Output struct {
 type Runner interface {
 	Execute(input *Input, timeout time.Duration) (*Output, error)
 }
+
+const (
+	// ShellBash runs scripts with /bin/bash
+	ShellBash = "bash"
+	// ShellEmbedded runs scripts with the embedded shell interpreter
+	ShellEmbedded = "embedded"
+)
+
+// New returns the runner for the given shell, /bin/bash is used if no shell is given
+func New(shell string, logger logger.Logger) Runner {
+	if shell == ShellEmbedded {
+		return NewInterpreter(logger)
+	}
+	return NewSubprocess(logger)
+}
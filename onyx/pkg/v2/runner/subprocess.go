@@ -51,7 +51,7 @@ func (s *Subprocess) Execute(input *Input, timeout time.Duration) (*Output, erro
 		return nil, errs.Wrap(err, "Failed to read command response")
 	}
 
-	s.demuxLogs(input, out, chunks)
+	demuxLogs(input, out, chunks)
 
 	if out.ExitCode == 124 {
 		out.Logs = append(out.Logs, model.LogEntry{Source: stdErrSourceType, Text: fmt.Sprintf("Command timed out after %s", timeout)})
@@ -88,7 +88,8 @@ func (p *streamParser) end() (bool, string) {
 	return false, ""
 }
 
-func (s *Subprocess) demuxLogs(in *Input, out *Output, chunks []*iomux.TaggedData[string]) {
+// demuxLogs splits the output of a command into log entries in the order they were written, secrets are hidden
+func demuxLogs(in *Input, out *Output, chunks []*iomux.TaggedData[string]) {
 	demuxed := map[string][]string{
 		stdOutSourceType: {},
 		stdErrSourceType: {},
@@ -128,6 +129,7 @@ func (s *Subprocess) demuxLogs(in *Input, out *Output, chunks []*iomux.TaggedDat
 		}
 		entry := model.LogEntry{Source: stream}
 		if json.Valid([]byte(line)) {
+			entry.Line = line
 			decoder := json.NewDecoder(strings.NewReader(line))
 			decoder.UseNumber()
 			_ = decoder.Decode(&entry.Json)
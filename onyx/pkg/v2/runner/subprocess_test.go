@@ -115,7 +115,7 @@ func TestExecute(t *testing.T) {
 			},
 			timeout: 10 * time.Minute,
 			want: &Output{
-				Logs:     []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"key1": "***SECRET1***"}}},
+				Logs:     []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"key1": "***SECRET1***"}, Line: `{"key1": "***SECRET1***"}`}},
 				JsonData: []map[string]interface{}{{"key1": "***SECRET1***"}},
 				WorkDir:  tmpDir,
 			},
@@ -311,10 +311,6 @@ func TestRunCommand(t *testing.T) {
 }
 
 func TestDemuxLogs(t *testing.T) {
-	s := &Subprocess{
-		logger: nopLogger,
-	}
-
 	testCases := map[string]struct {
 		outStr string
 		errStr string
@@ -325,8 +321,8 @@ func TestDemuxLogs(t *testing.T) {
 			errStr: "error message",
 			want: &Output{
 				Logs: []model.LogEntry{
-					{Source: "stdout", Json: map[string]interface{}{"key1": "value1"}},
-					{Source: "stdout", Json: map[string]interface{}{"key2": "value2"}},
+					{Source: "stdout", Json: map[string]interface{}{"key1": "value1"}, Line: `{"key1": "value1"}`},
+					{Source: "stdout", Json: map[string]interface{}{"key2": "value2"}, Line: `{"key2": "value2"}`},
 					{Source: "stderr", Text: "error message"},
 				},
 				JsonData: []map[string]interface{}{
@@ -340,8 +336,8 @@ func TestDemuxLogs(t *testing.T) {
 			errStr: "",
 			want: &Output{
 				Logs: []model.LogEntry{
-					{Source: "stdout", Json: map[string]interface{}{"key1": "value1"}},
-					{Source: "stdout", Json: map[string]interface{}{"key2": "value2"}},
+					{Source: "stdout", Json: map[string]interface{}{"key1": "value1"}, Line: `{"key1": "value1"}`},
+					{Source: "stdout", Json: map[string]interface{}{"key2": "value2"}, Line: `{"key2": "value2"}`},
 				},
 				JsonData: []map[string]interface{}{
 					{"key1": "value1"},
@@ -354,13 +350,13 @@ func TestDemuxLogs(t *testing.T) {
 			errStr: "",
 			want: &Output{
 				Logs: []model.LogEntry{
-					{Source: "stdout", Json: map[string]interface{}{"key1": json.Number("1")}},
-					{Source: "stdout", Json: map[string]interface{}{"key2": json.Number("2.0")}},
-					{Source: "stdout", Json: map[string]interface{}{"key3": json.Number("201872326")}},
-					{Source: "stdout", Json: map[string]interface{}{"key4": json.Number("201872326.0")}},
-					{Source: "stdout", Json: map[string]interface{}{"key5": json.Number("-201872326")}},
-					{Source: "stdout", Json: map[string]interface{}{"key6": json.Number("-201872326.1")}},
-					{Source: "stdout", Json: map[string]interface{}{"key7": json.Number("0")}},
+					{Source: "stdout", Json: map[string]interface{}{"key1": json.Number("1")}, Line: `{"key1": 1}`},
+					{Source: "stdout", Json: map[string]interface{}{"key2": json.Number("2.0")}, Line: `{"key2": 2.0}`},
+					{Source: "stdout", Json: map[string]interface{}{"key3": json.Number("201872326")}, Line: `{"key3": 201872326}`},
+					{Source: "stdout", Json: map[string]interface{}{"key4": json.Number("201872326.0")}, Line: `{"key4": 201872326.0}`},
+					{Source: "stdout", Json: map[string]interface{}{"key5": json.Number("-201872326")}, Line: `{"key5": -201872326}`},
+					{Source: "stdout", Json: map[string]interface{}{"key6": json.Number("-201872326.1")}, Line: `{"key6": -201872326.1}`},
+					{Source: "stdout", Json: map[string]interface{}{"key7": json.Number("0")}, Line: `{"key7": 0}`},
 				},
 				JsonData: []map[string]interface{}{
 					{"key1": json.Number("1")},
@@ -377,7 +373,7 @@ func TestDemuxLogs(t *testing.T) {
 			outStr: "{\"key1\": \"2021-01-01T00:00:00Z\"}",
 			errStr: "",
 			want: &Output{
-				Logs: []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"key1": "2021-01-01T00:00:00Z"}}},
+				Logs: []model.LogEntry{{Source: "stdout", Json: map[string]interface{}{"key1": "2021-01-01T00:00:00Z"}, Line: `{"key1": "2021-01-01T00:00:00Z"}`}},
 				JsonData: []map[string]interface{}{
 					{"key1": "2021-01-01T00:00:00Z"},
 				},
@@ -403,7 +399,7 @@ func TestDemuxLogs(t *testing.T) {
 			outStr: "",
 			errStr: "{\"context\":\"some-context\", \"errMsg\":\"err-msg\"}",
 			want: &Output{
-				Logs:     []model.LogEntry{{Source: "stderr", Json: map[string]interface{}{"context": "some-context", "errMsg": "err-msg"}}},
+				Logs:     []model.LogEntry{{Source: "stderr", Json: map[string]interface{}{"context": "some-context", "errMsg": "err-msg"}, Line: `{"context":"some-context", "errMsg":"err-msg"}`}},
 				JsonData: nil,
 			},
 		},
@@ -459,7 +455,7 @@ func TestDemuxLogs(t *testing.T) {
 
 			// act
 			chunks, err := mux.ReadUntil(ctx)
-			s.demuxLogs(&Input{}, out, chunks)
+			demuxLogs(&Input{}, out, chunks)
 
 			// assert
 			if assert.NoError(t, err) {
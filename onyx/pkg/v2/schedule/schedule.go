@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package schedule orders automated checks by their expected duration.
+// Starting the longest checks first keeps the total run time short if
+// only a limited number of checks can run at the same time.
+package schedule
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// number of latest history entries the expected duration is averaged over
+const historyWindow = 5
+
+// Durations contains the expected durations of checks, the key is '<chapter>_<requirement>_<check>'
+type Durations map[string]time.Duration
+
+// Key returns the key of a check in Durations
+func Key(check model.AutopilotCheck) string {
+	return history.CheckKey(check.Chapter.Id, check.Requirement.Id, check.Check.Id)
+}
+
+// Known returns the durations of a project known from previous runs.
+// Durations of the previous result file take precedence over the ones of the history.
+// Either source is skipped if it is empty, the durations read so far are returned on errors.
+func Known(historyDir, project, previousResult string) (Durations, error) {
+	known := Durations{}
+	if previousResult != "" {
+		durations, err := FromResult(previousResult)
+		if err != nil {
+			return known, err
+		}
+		known = durations
+	}
+	if historyDir != "" {
+		entries, err := history.New(historyDir).Entries(project)
+		if err != nil {
+			return known, err
+		}
+		for key, duration := range FromHistory(entries) {
+			if _, ok := known[key]; !ok {
+				known[key] = duration
+			}
+		}
+	}
+	return known, nil
+}
+
+// FromHistory returns the average durations of the checks in the latest history entries
+func FromHistory(entries []history.Entry) Durations {
+	if len(entries) > historyWindow {
+		entries = entries[len(entries)-historyWindow:]
+	}
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		for key, seconds := range entry.Durations {
+			sums[key] += seconds
+			counts[key]++
+		}
+	}
+	durations := Durations{}
+	for key, sum := range sums {
+		durations[key] = fromSeconds(sum / float64(counts[key]))
+	}
+	return durations
+}
+
+// resultDurations is the part of a result file containing the durations, it is the same for all result versions
+type resultDurations struct {
+	Chapters map[string]struct {
+		Requirements map[string]struct {
+			Checks map[string]struct {
+				Duration float64 `yaml:"duration"`
+			} `yaml:"checks"`
+		} `yaml:"requirements"`
+	} `yaml:"chapters"`
+}
+
+// FromResult returns the durations of the checks in a result file
+func FromResult(path string) (Durations, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read result file '%s'", path)
+	}
+	var res resultDurations
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse result file '%s'", path)
+	}
+	durations := Durations{}
+	for chapID, chap := range res.Chapters {
+		for reqID, req := range chap.Requirements {
+			for checkID, check := range req.Checks {
+				if check.Duration > 0 {
+					durations[history.CheckKey(chapID, reqID, checkID)] = fromSeconds(check.Duration)
+				}
+			}
+		}
+	}
+	return durations, nil
+}
+
+// Expected returns the expected durations of the checks.
+// A configured expected duration takes precedence over the known durations, checks without any are left out.
+func Expected(checks []model.AutopilotCheck, known Durations) Durations {
+	expected := Durations{}
+	for _, check := range checks {
+		key := Key(check)
+		if check.ExpectedDuration > 0 {
+			expected[key] = check.ExpectedDuration
+		} else if duration, ok := known[key]; ok {
+			expected[key] = duration
+		}
+	}
+	return expected
+}
+
+// Order sorts the checks by their expected duration, longest first.
+// Checks without expected duration come last, ties are ordered by key.
+func Order(checks []model.AutopilotCheck, expected Durations) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		keyI, keyJ := Key(checks[i]), Key(checks[j])
+		durationI, okI := expected[keyI]
+		durationJ, okJ := expected[keyJ]
+		if okI != okJ {
+			return okI
+		}
+		if durationI != durationJ {
+			return durationI > durationJ
+		}
+		return keyI < keyJ
+	})
+}
+
+// Estimate predicts the total run time of checks started in the given order if at most
+// parallelism of them run at the same time. The parallelism is unlimited if it is not positive.
+func Estimate(durations []time.Duration, parallelism int) time.Duration {
+	if parallelism <= 0 || parallelism > len(durations) {
+		parallelism = len(durations)
+	}
+	if parallelism == 0 {
+		return 0
+	}
+	// every check is started on the slot which becomes free first
+	slots := make([]time.Duration, parallelism)
+	for _, duration := range durations {
+		next := 0
+		for i := range slots {
+			if slots[i] < slots[next] {
+				next = i
+			}
+		}
+		slots[next] += duration
+	}
+	var total time.Duration
+	for _, finished := range slots {
+		if finished > total {
+			total = finished
+		}
+	}
+	return total
+}
+
+func fromSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/configuration"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/history"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func check(id string, expected time.Duration) model.AutopilotCheck {
+	return model.AutopilotCheck{
+		Item: model.Item{
+			Chapter:     configuration.Chapter{Id: "1"},
+			Requirement: configuration.Requirement{Id: "1"},
+			Check:       configuration.Check{Id: id},
+		},
+		ExpectedDuration: expected,
+	}
+}
+
+func keys(checks []model.AutopilotCheck) []string {
+	var keys []string
+	for _, check := range checks {
+		keys = append(keys, Key(check))
+	}
+	return keys
+}
+
+func TestOrder(t *testing.T) {
+	checks := []model.AutopilotCheck{check("d", 0), check("a", 0), check("b", 0), check("c", time.Minute)}
+	expected := Expected(checks, Durations{"1_1_a": 10 * time.Second, "1_1_b": 40 * time.Minute, "1_1_c": time.Hour})
+
+	Order(checks, expected)
+
+	assert.Equal(t, Durations{"1_1_a": 10 * time.Second, "1_1_b": 40 * time.Minute, "1_1_c": time.Minute}, expected)
+	assert.Equal(t, []string{"1_1_b", "1_1_c", "1_1_a", "1_1_d"}, keys(checks))
+}
+
+func TestEstimate(t *testing.T) {
+	durations := []time.Duration{40 * time.Minute, 20 * time.Minute, 15 * time.Minute, 10 * time.Minute, 5 * time.Minute}
+	testCases := map[string]struct {
+		durations   []time.Duration
+		parallelism int
+		want        time.Duration
+	}{
+		"should sum up durations without parallelism": {
+			durations:   durations,
+			parallelism: 1,
+			want:        90 * time.Minute,
+		},
+		"should fill the slot which is free first": {
+			durations:   durations,
+			parallelism: 2,
+			want:        45 * time.Minute,
+		},
+		"should return the longest duration if unlimited": {
+			durations:   durations,
+			parallelism: 0,
+			want:        40 * time.Minute,
+		},
+		"should take longer if the longest check is started last": {
+			durations:   []time.Duration{5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 20 * time.Minute, 40 * time.Minute},
+			parallelism: 2,
+			want:        60 * time.Minute,
+		},
+		"should return zero without checks": {
+			parallelism: 2,
+			want:        0,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Estimate(tc.durations, tc.parallelism))
+		})
+	}
+}
+
+func TestFromHistory(t *testing.T) {
+	var entries []history.Entry
+	for i := 1; i <= 7; i++ {
+		entries = append(entries, history.Entry{Durations: map[string]float64{"1_1_1": float64(i * 10)}})
+	}
+	entries = append(entries, history.Entry{Durations: map[string]float64{"1_1_2": 1.5}})
+
+	durations := FromHistory(entries)
+
+	assert.Equal(t, Durations{"1_1_1": 55 * time.Second, "1_1_2": 1500 * time.Millisecond}, durations)
+}
+
+func TestKnown(t *testing.T) {
+	dir := t.TempDir()
+	store := history.New(filepath.Join(dir, "history"))
+	require.NoError(t, store.Append(history.Entry{Project: "project", Durations: map[string]float64{"1_1_1": 60, "1_1_2": 120}}))
+	resultFile := filepath.Join(dir, "qg-result.yaml")
+	content := "chapters:\n  '1':\n    requirements:\n      '1':\n        checks:\n          '1':\n            duration: 30.5\n          '3':\n            evaluation:\n              status: GREEN\n"
+	require.NoError(t, os.WriteFile(resultFile, []byte(content), 0644))
+
+	known, err := Known(filepath.Join(dir, "history"), "project", resultFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, Durations{"1_1_1": 30500 * time.Millisecond, "1_1_2": 2 * time.Minute}, known)
+
+	_, err = Known("", "project", filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read result file")
+}
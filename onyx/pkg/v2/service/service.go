@@ -0,0 +1,321 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	StatusStopped = "STOPPED"
+	StatusCrashed = "CRASHED"
+	StatusFailed  = "FAILED"
+)
+
+const (
+	serviceHost        = "localhost"
+	healthCheckTimeout = 5 * time.Second
+	pollInterval       = 200 * time.Millisecond
+	restartDelay       = 500 * time.Millisecond
+	stopTimeout        = 10 * time.Second
+)
+
+// Manager runs background services for the duration of a run
+type Manager struct {
+	rootWorkDir string
+	shell       shell
+	logger      logger.Logger
+	processes   []*process
+}
+
+// New returns a manager which runs the services and health checks with the given shell, see runner.New
+func New(rootWorkDir string, shell string, logger logger.Logger) *Manager {
+	return &Manager{
+		rootWorkDir: rootWorkDir,
+		shell:       newShell(shell, logger),
+		logger:      logger,
+	}
+}
+
+// Start starts the services one after another and waits until each of them is healthy.
+// It returns the environment variables which expose the started services to the checks.
+// If a service does not become healthy, the remaining services are not started.
+func (m *Manager) Start(services []model.Service, env, secrets map[string]string) (map[string]string, error) {
+	exposed := make(map[string]string)
+	for _, service := range services {
+		m.logger.Infof("starting service '%s'", service.Name)
+		p, err := m.newProcess(service, env, secrets)
+		if err != nil {
+			return exposed, err
+		}
+		m.processes = append(m.processes, p)
+		go p.supervise()
+
+		if err := p.waitUntilHealthy(); err != nil {
+			p.stop()
+			p.fail(err.Error())
+			return exposed, err
+		}
+		m.logger.Infof("service '%s' is healthy", service.Name)
+
+		prefix := model.ServiceEnvPrefix(service.Name)
+		exposed[prefix+"HOST"] = serviceHost
+		if service.Port != 0 {
+			exposed[prefix+"PORT"] = strconv.Itoa(service.Port)
+		}
+	}
+	return exposed, nil
+}
+
+// Stop stops all services in reverse order and returns their outcomes in the order they were started
+func (m *Manager) Stop() []model.ServiceRun {
+	for i := len(m.processes) - 1; i >= 0; i-- {
+		m.logger.Infof("stopping service '%s'", m.processes[i].service.Name)
+		m.processes[i].stop()
+	}
+	runs := make([]model.ServiceRun, 0, len(m.processes))
+	for _, p := range m.processes {
+		runs = append(runs, p.run())
+	}
+	m.processes = nil
+	return runs
+}
+
+func (m *Manager) newProcess(service model.Service, env, secrets map[string]string) (*process, error) {
+	logFile := fmt.Sprintf("service_%s.log", service.Name)
+	file, err := os.OpenFile(filepath.Join(m.rootWorkDir, logFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create log file of service '%s'", service.Name)
+	}
+	environ := os.Environ()
+	for key, value := range helper.MergeMaps(env, service.Env) {
+		environ = append(environ, fmt.Sprintf("%s=%s", key, value))
+	}
+	// secrets are copied because the log of the service is written concurrently to later changes of the secrets
+	return &process{
+		service: service,
+		shell:   m.shell,
+		workDir: m.rootWorkDir,
+		environ: environ,
+		secrets: helper.MergeMaps(secrets),
+		logger:  m.logger,
+		logFile: logFile,
+		log:     file,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+type process struct {
+	service model.Service
+	shell   shell
+	workDir string
+	environ []string
+	secrets map[string]string
+	logger  logger.Logger
+	logFile string
+	log     *os.File
+	logs    sync.WaitGroup
+	done    chan struct{}
+
+	mu sync.Mutex
+	// script is the running script of the service, it is nil while the service is not running
+	script   running
+	stopping bool
+	restarts int
+	status   string
+	reason   string
+}
+
+// supervise runs the service and restarts it according to its restart policy until it is stopped
+func (p *process) supervise() {
+	defer close(p.done)
+	for {
+		script, err := p.start()
+		if err != nil {
+			p.fail(fmt.Sprintf("service '%s' could not be started: %s", p.service.Name, err.Error()))
+			return
+		}
+		if script == nil {
+			return
+		}
+		exitCode := script.wait()
+
+		p.mu.Lock()
+		p.script = nil
+		if p.stopping {
+			p.mu.Unlock()
+			return
+		}
+		if !p.restartAllowed(exitCode) {
+			p.status = StatusCrashed
+			p.reason = fmt.Sprintf("service '%s' exited with exit code %d", p.service.Name, exitCode)
+			if p.restarts > 0 {
+				p.reason += fmt.Sprintf(" after %d restarts", p.restarts)
+			}
+			p.mu.Unlock()
+			p.logger.UserErrorf("%s", p.reason)
+			return
+		}
+		p.restarts++
+		p.mu.Unlock()
+		p.logger.Warnf("service '%s' exited with exit code %d, restarting it (%d/%d)", p.service.Name, exitCode, p.restarts, p.service.MaxRestarts)
+		time.Sleep(restartDelay)
+	}
+}
+
+// start starts the service, it returns no script if the service is already stopping
+func (p *process) start() (running, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopping {
+		return nil, nil
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pipe for logs")
+	}
+	script, err := p.shell.start(p.service.Run, p.workDir, p.environ, writer)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	p.script = script
+	p.logs.Add(1)
+	go p.copyLogs(reader)
+	return script, nil
+}
+
+// copyLogs writes the output of the service into its log file, secrets are masked
+func (p *process) copyLogs(reader *os.File) {
+	defer p.logs.Done()
+	defer reader.Close()
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := helper.HideSecretsInString(scanner.Text(), p.secrets)
+		if _, err := fmt.Fprintln(p.log, line); err != nil {
+			p.logger.Warnf("failed to write log of service '%s': %s", p.service.Name, err.Error())
+			return
+		}
+	}
+}
+
+func (p *process) restartAllowed(exitCode int) bool {
+	if p.restarts >= p.service.MaxRestarts {
+		return false
+	}
+	switch p.service.Restart {
+	case "always":
+		return true
+	case "on-failure":
+		return exitCode != 0
+	default:
+		return false
+	}
+}
+
+// waitUntilHealthy polls the port or the health check of the service until it succeeds or the startup timeout is reached
+func (p *process) waitUntilHealthy() error {
+	if p.service.Port == 0 && p.service.HealthCheck == "" {
+		return nil
+	}
+	deadline := time.Now().Add(p.service.StartupTimeout)
+	for {
+		select {
+		case <-p.done:
+			return errors.Errorf("service '%s' exited before it became healthy: %s", p.service.Name, p.run().Reason)
+		default:
+		}
+		if p.healthy() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("service '%s' did not become healthy within %s", p.service.Name, p.service.StartupTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func (p *process) healthy() bool {
+	if p.service.Port != 0 {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(serviceHost, strconv.Itoa(p.service.Port)), pollInterval)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+	if p.service.HealthCheck != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+		if err := p.shell.run(ctx, p.service.HealthCheck, p.workDir, p.environ); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// stop terminates the service and waits until it has exited, it is killed if it does not exit within the stop timeout
+func (p *process) stop() {
+	p.mu.Lock()
+	if !p.stopping {
+		p.stopping = true
+		if p.status == "" {
+			p.status = StatusStopped
+		}
+		if p.script != nil {
+			if err := p.script.terminate(); err != nil {
+				p.logger.Debugf("failed to terminate service '%s': %s", p.service.Name, err.Error())
+			}
+		}
+	}
+	script := p.script
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+	case <-time.After(stopTimeout):
+		p.logger.Warnf("service '%s' did not stop within %s, killing it", p.service.Name, stopTimeout)
+		if script != nil {
+			if err := script.kill(); err != nil {
+				p.logger.Debugf("failed to kill service '%s': %s", p.service.Name, err.Error())
+			}
+		}
+		<-p.done
+	}
+	p.logs.Wait()
+	p.log.Close()
+}
+
+func (p *process) fail(reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = StatusFailed
+	p.reason = reason
+}
+
+func (p *process) run() model.ServiceRun {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return model.ServiceRun{
+		Service:  p.service,
+		Status:   p.status,
+		Reason:   p.reason,
+		Restarts: p.restarts,
+		LogFile:  p.logFile,
+	}
+}
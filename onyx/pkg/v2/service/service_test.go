@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package service
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	tests := map[string]struct {
+		service      model.Service
+		wantErr      string
+		wantEnv      map[string]string
+		wantStatus   string
+		wantReason   string
+		wantRestarts int
+		wantLogs     string
+	}{
+		"should start service and stop it at the end": {
+			service: model.Service{
+				Name:        "mock-server",
+				Run:         "echo \"started with $MODE and $TOKEN\"; touch ready; sleep 30",
+				Env:         map[string]string{"MODE": "mock"},
+				HealthCheck: "test -f ready",
+			},
+			wantEnv:    map[string]string{"SERVICE_MOCK_SERVER_HOST": "localhost"},
+			wantStatus: StatusStopped,
+			wantLogs:   "started with mock and ***TOKEN***\n",
+		},
+		"should fail if service does not become healthy": {
+			service: model.Service{
+				Name:        "slow",
+				Run:         "sleep 30",
+				HealthCheck: "exit 1",
+			},
+			wantErr:    "service 'slow' did not become healthy within 500ms",
+			wantEnv:    map[string]string{},
+			wantStatus: StatusFailed,
+			wantReason: "service 'slow' did not become healthy within 500ms",
+		},
+		"should restart crashed service according to policy": {
+			service: model.Service{
+				Name:        "flaky",
+				Run:         "echo run; exit 3",
+				Restart:     "on-failure",
+				MaxRestarts: 1,
+			},
+			wantEnv:      map[string]string{"SERVICE_FLAKY_HOST": "localhost"},
+			wantStatus:   StatusCrashed,
+			wantReason:   "service 'flaky' exited with exit code 3 after 1 restarts",
+			wantRestarts: 1,
+			wantLogs:     "run\nrun\n",
+		},
+		"should not restart service which exited successfully on failure policy": {
+			service: model.Service{
+				Name:        "oneshot",
+				Run:         "exit 0",
+				Restart:     "on-failure",
+				MaxRestarts: 3,
+			},
+			wantEnv:    map[string]string{"SERVICE_ONESHOT_HOST": "localhost"},
+			wantStatus: StatusCrashed,
+			wantReason: "service 'oneshot' exited with exit code 0",
+		},
+	}
+	for _, shell := range []string{runner.ShellBash, runner.ShellEmbedded} {
+		for name, tt := range tests {
+			t.Run(shell+"/"+name, func(t *testing.T) {
+				rootWorkDir := t.TempDir()
+				tt.service.StartupTimeout = 500 * time.Millisecond
+				m := New(rootWorkDir, shell, logger.Get())
+
+				env, err := m.Start([]model.Service{tt.service}, map[string]string{"TOKEN": "s3cr3t"}, map[string]string{"TOKEN": "s3cr3t"})
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+				assert.Equal(t, tt.wantEnv, env)
+				if tt.wantStatus == StatusCrashed {
+					time.Sleep(1500 * time.Millisecond)
+				}
+
+				runs := m.Stop()
+
+				require.Len(t, runs, 1)
+				assert.Equal(t, tt.wantStatus, runs[0].Status)
+				assert.Equal(t, tt.wantReason, runs[0].Reason)
+				assert.Equal(t, tt.wantRestarts, runs[0].Restarts)
+				assert.Equal(t, "service_"+tt.service.Name+".log", runs[0].LogFile)
+				logs, err := os.ReadFile(filepath.Join(rootWorkDir, runs[0].LogFile))
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantLogs, string(logs))
+			})
+		}
+	}
+}
+
+func TestManagerPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+	m := New(t.TempDir(), runner.ShellBash, logger.Get())
+
+	env, err := m.Start([]model.Service{{Name: "db", Run: "sleep 30", Port: port, StartupTimeout: time.Second}}, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"SERVICE_DB_HOST": "localhost", "SERVICE_DB_PORT": strconv.Itoa(port)}, env)
+	runs := m.Stop()
+	assert.Equal(t, StatusStopped, runs[0].Status)
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/runner"
+	"github.com/pkg/errors"
+)
+
+// shell runs the scripts of services and their health checks
+type shell interface {
+	// start starts the script, its output is written to out which is closed when the script exits
+	start(script, dir string, environ []string, out *os.File) (running, error)
+	// run runs the script until it exits or ctx is done and fails if it does not succeed
+	run(ctx context.Context, script, dir string, environ []string) error
+}
+
+// running is a started script
+type running interface {
+	// wait waits until the script has exited and returns its exit code
+	wait() int
+	terminate() error
+	kill() error
+}
+
+// newShell returns the shell with the given name, /bin/bash is used if no shell is given
+func newShell(name string, logger logger.Logger) shell {
+	if name == runner.ShellEmbedded {
+		return &embeddedShell{interpreter: runner.NewInterpreter(logger)}
+	}
+	return &bashShell{}
+}
+
+type bashShell struct{}
+
+func (b *bashShell) start(script, dir string, environ []string, out *os.File) (running, error) {
+	cmd := exec.Command("/bin/bash", "-c", script)
+	cmd.Dir = dir
+	cmd.Env = environ
+	cmd.Stdout = out
+	cmd.Stderr = out
+	setProcessGroup(cmd)
+	err := cmd.Start()
+	out.Close()
+	if err != nil {
+		return nil, err
+	}
+	return &bashProcess{cmd: cmd}, nil
+}
+
+func (b *bashShell) run(ctx context.Context, script, dir string, environ []string) error {
+	cmd := exec.CommandContext(ctx, "/bin/bash", "-c", script)
+	cmd.Dir = dir
+	cmd.Env = environ
+	return cmd.Run()
+}
+
+type bashProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p *bashProcess) wait() int {
+	_ = p.cmd.Wait()
+	return p.cmd.ProcessState.ExitCode()
+}
+
+func (p *bashProcess) terminate() error {
+	return terminate(p.cmd)
+}
+
+func (p *bashProcess) kill() error {
+	return kill(p.cmd)
+}
+
+// embeddedShell runs the scripts with the embedded shell interpreter, only external commands are started as processes
+type embeddedShell struct {
+	interpreter *runner.Interpreter
+}
+
+func (e *embeddedShell) start(script, dir string, environ []string, out *os.File) (running, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &embeddedProcess{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		defer out.Close()
+		p.exitCode = e.interpreter.RunScript(ctx, script, dir, environ, out)
+	}()
+	return p, nil
+}
+
+func (e *embeddedShell) run(ctx context.Context, script, dir string, environ []string) error {
+	if exitCode := e.interpreter.RunScript(ctx, script, dir, environ, io.Discard); exitCode != 0 {
+		return errors.Errorf("exit code %d", exitCode)
+	}
+	return nil
+}
+
+type embeddedProcess struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	exitCode int
+}
+
+func (p *embeddedProcess) wait() int {
+	<-p.done
+	return p.exitCode
+}
+
+// terminate cancels the script, running external commands are interrupted and killed if they do not exit in time
+func (p *embeddedProcess) terminate() error {
+	p.cancel()
+	return nil
+}
+
+func (p *embeddedProcess) kill() error {
+	p.cancel()
+	return nil
+}
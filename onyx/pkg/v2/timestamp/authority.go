@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package timestamp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LocalPolicy is the policy of the tokens issued by a local authority
+var LocalPolicy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+
+// localValidity is how long the certificates of a local authority are valid
+const localValidity = 48 * time.Hour
+
+// Authority is a minimal time-stamping authority which signs the requests it receives with an ECDSA key.
+// It is meant as local stand-in for a real TSA, e.g. in tests.
+type Authority struct {
+	ca   *x509.Certificate
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	serial int64
+}
+
+// NewLocalAuthority creates an authority whose certificate is issued by an ephemeral CA.
+// The CA has to be trusted to verify the tokens of the authority.
+func NewLocalAuthority() (*Authority, error) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key of the CA")
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "onyx local TSA CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(localValidity),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	ca, err := createCertificate(caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key of the TSA")
+	}
+	cert, err := createCertificate(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "onyx local TSA"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(localValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Authority{ca: ca, cert: cert, key: key}, nil
+}
+
+// CA returns the certificate of the CA which issued the certificate of the authority
+func (a *Authority) CA() *x509.Certificate {
+	return a.ca
+}
+
+func createCertificate(template, parent *x509.Certificate, key *ecdsa.PublicKey, parentKey *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key, parentKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create certificate '%s'", template.Subject.CommonName)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate '%s'", template.Subject.CommonName)
+	}
+	return cert, nil
+}
+
+// ServeHTTP answers a time-stamp query with a reply as described in RFC 3161 section 3.4
+func (a *Authority) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	query, err := io.ReadAll(io.LimitReader(req.Body, maxReplySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply, err := a.Reply(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeReply)
+	_, _ = w.Write(reply)
+}
+
+// Reply creates the DER encoded reply to a DER encoded time-stamp query, invalid queries are rejected in the reply
+func (a *Authority) Reply(query []byte) ([]byte, error) {
+	var tsq timeStampReq
+	if _, err := asn1.Unmarshal(query, &tsq); err != nil {
+		return asn1.Marshal(timeStampResp{Status: pkiStatusInfo{Status: statusRejection, StatusString: []string{"malformed request"}}})
+	}
+	if _, ok := hashOf(tsq.MessageImprint.HashAlgorithm); !ok {
+		return asn1.Marshal(timeStampResp{Status: pkiStatusInfo{Status: statusRejection, StatusString: []string{"unsupported hash algorithm"}}})
+	}
+	token, err := a.sign(tsq)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(timeStampResp{
+		Status:         pkiStatusInfo{Status: statusGranted},
+		TimeStampToken: asn1.RawValue{FullBytes: token},
+	})
+}
+
+func (a *Authority) sign(tsq timeStampReq) ([]byte, error) {
+	a.mu.Lock()
+	a.serial++
+	serial := big.NewInt(a.serial)
+	a.mu.Unlock()
+	now := time.Now().UTC().Truncate(time.Second)
+	content, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         LocalPolicy,
+		MessageImprint: tsq.MessageImprint,
+		SerialNumber:   serial,
+		GenTime:        now,
+		Nonce:          tsq.Nonce,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal timestamp info")
+	}
+	contentDigest := sha256.Sum256(content)
+	attributes, err := marshalAttributes([]attributeValue{
+		{oid: oidContentType, value: oidTSTInfo},
+		{oid: oidSigningTime, value: now},
+		{oid: oidMessageDigest, value: contentDigest[:]},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sid, err := asn1.Marshal(issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: a.cert.RawIssuer}, SerialNumber: a.cert.SerialNumber})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signer")
+	}
+	signedAttributes, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attributes})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signed attributes")
+	}
+	attributesDigest := sha256.Sum256(signedAttributes)
+	signature, err := a.key.Sign(rand.Reader, attributesDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign timestamp")
+	}
+	sha256Algorithm := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	sd := signedData{
+		Version:          3,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Algorithm},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: oidTSTInfo, EContent: content},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    sha256Algorithm,
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attributes},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+			Signature:          signature,
+		}},
+	}
+	if tsq.CertReq {
+		sd.Certificates = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: a.cert.Raw}
+	}
+	signed, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signed data")
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signed},
+	})
+}
+
+type attributeValue struct {
+	oid   asn1.ObjectIdentifier
+	value interface{}
+}
+
+// marshalAttributes encodes the attributes in the order required by DER for the elements of a SET OF
+func marshalAttributes(values []attributeValue) ([]byte, error) {
+	var encoded [][]byte
+	for _, v := range values {
+		encodedValue, err := asn1.Marshal(v.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal attribute %s", v.oid)
+		}
+		attr, err := asn1.Marshal(attribute{
+			Type:   v.oid,
+			Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: encodedValue},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal attribute %s", v.oid)
+		}
+		encoded = append(encoded, attr)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil), nil
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+// Package timestamp requests and verifies RFC 3161 timestamp tokens.
+// A token is issued by a time-stamping authority (TSA) and proves that a digest existed at the time in the token.
+package timestamp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	contentTypeQuery = "application/timestamp-query"
+	contentTypeReply = "application/timestamp-reply"
+	// maxReplySize limits the size of a reply, tokens are a few kilobytes
+	maxReplySize = 1 << 20
+)
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Token is a parsed timestamp token whose signature was checked with the certificate of the signer
+type Token struct {
+	// Time at which the token was issued
+	GenTime      time.Time
+	SerialNumber *big.Int
+	Policy       asn1.ObjectIdentifier
+	// Hash algorithm and digest the token was issued for
+	HashAlgorithm crypto.Hash
+	HashedMessage []byte
+	Nonce         *big.Int
+	// Certificate of the TSA which signed the token
+	Signer *x509.Certificate
+	// Certificates contained in the token, including the one of the signer
+	Certificates []*x509.Certificate
+}
+
+// Request requests a token for a SHA-256 digest from the TSA at tsaURL and returns it DER encoded
+func Request(tsaURL string, digest []byte) ([]byte, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	query, err := asn1.Marshal(timeStampReq{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+			HashedMessage: digest,
+		},
+		Nonce:   nonce,
+		CertReq: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create timestamp request")
+	}
+	resp, err := client.Post(tsaURL, contentTypeQuery, bytes.NewReader(query))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request timestamp from '%s'", tsaURL)
+	}
+	defer resp.Body.Close()
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, maxReplySize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read timestamp reply of '%s'", tsaURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to request timestamp from '%s': %s", tsaURL, resp.Status)
+	}
+	token, err := parseReply(reply)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid timestamp reply of '%s'", tsaURL)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid timestamp reply of '%s'", tsaURL)
+	}
+	if !bytes.Equal(parsed.HashedMessage, digest) {
+		return nil, errors.Errorf("timestamp of '%s' was issued for another digest", tsaURL)
+	}
+	if parsed.Nonce == nil || parsed.Nonce.Cmp(nonce) != 0 {
+		return nil, errors.Errorf("timestamp of '%s' does not contain the nonce of the request", tsaURL)
+	}
+	return token, nil
+}
+
+// parseReply returns the token of a reply which was granted by the TSA
+func parseReply(reply []byte) ([]byte, error) {
+	var resp timeStampResp
+	rest, err := asn1.Unmarshal(reply, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse reply")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after reply")
+	}
+	if resp.Status.Status != statusGranted && resp.Status.Status != statusGrantedWithMods {
+		reason := strings.Join(resp.Status.StatusString, ", ")
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return nil, errors.Errorf("timestamp was rejected with status %d: %s", resp.Status.Status, reason)
+	}
+	if len(resp.TimeStampToken.FullBytes) == 0 {
+		return nil, errors.New("reply does not contain a token")
+	}
+	return resp.TimeStampToken.FullBytes, nil
+}
+
+// Parse parses a DER encoded token and checks its signature with the certificate of the signer contained in the token.
+// The certificate itself is not verified, see Verify.
+func Parse(token []byte) (*Token, error) {
+	var info contentInfo
+	if _, err := asn1.Unmarshal(token, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to parse token")
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, errors.Errorf("token has content type %s instead of signed data", info.ContentType)
+	}
+	// the raw value of an explicitly tagged field still contains the tag, the signed data is its content
+	var sd signedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &sd); err != nil {
+		return nil, errors.Wrap(err, "failed to parse signed data of token")
+	}
+	if !sd.EncapContentInfo.EContentType.Equal(oidTSTInfo) {
+		return nil, errors.Errorf("token contains %s instead of timestamp info", sd.EncapContentInfo.EContentType)
+	}
+	var tst tstInfo
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent, &tst); err != nil {
+		return nil, errors.Wrap(err, "failed to parse timestamp info of token")
+	}
+	hash, ok := hashOf(tst.MessageImprint.HashAlgorithm)
+	if !ok {
+		return nil, errors.Errorf("unsupported hash algorithm %s of message imprint", tst.MessageImprint.HashAlgorithm.Algorithm)
+	}
+	var certificates []*x509.Certificate
+	if len(sd.Certificates.Bytes) > 0 {
+		var err error
+		certificates, err = x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificates of token")
+		}
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, errors.Errorf("token has %d signers instead of one", len(sd.SignerInfos))
+	}
+	signer, err := checkSignature(sd.SignerInfos[0], sd.EncapContentInfo.EContent, certificates)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{
+		GenTime:       tst.GenTime,
+		SerialNumber:  tst.SerialNumber,
+		Policy:        tst.Policy,
+		HashAlgorithm: hash,
+		HashedMessage: tst.MessageImprint.HashedMessage,
+		Nonce:         tst.Nonce,
+		Signer:        signer,
+		Certificates:  certificates,
+	}, nil
+}
+
+// Verify checks that the token was issued for the SHA-256 digest and that the certificate of the signer is a valid
+// time-stamping certificate issued by one of the roots at the time of the token. If roots is nil, the system roots are used.
+func (t *Token) Verify(digest []byte, roots *x509.CertPool) error {
+	if t.HashAlgorithm != crypto.SHA256 || !bytes.Equal(t.HashedMessage, digest) {
+		return errors.New("timestamp was issued for another digest")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range t.Certificates {
+		if cert != t.Signer {
+			intermediates.AddCert(cert)
+		}
+	}
+	_, err := t.Signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   t.GenTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "certificate of TSA '%s' is not trusted", t.Signer.Subject.String())
+	}
+	return nil
+}
+
+// checkSignature returns the certificate of the signer after checking the signature of the signed attributes
+// and that the attributes belong to the content
+func checkSignature(si signerInfo, content []byte, certificates []*x509.Certificate) (*x509.Certificate, error) {
+	signer, err := findSigner(si.SID, certificates)
+	if err != nil {
+		return nil, err
+	}
+	hash, ok := hashOf(si.DigestAlgorithm)
+	if !ok {
+		return nil, errors.Errorf("unsupported digest algorithm %s of signer", si.DigestAlgorithm.Algorithm)
+	}
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return nil, errors.New("token has no signed attributes")
+	}
+	attributes, err := parseAttributes(si.SignedAttrs.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	var contentType asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(attributes[oidContentType.String()], &contentType); err != nil || !contentType.Equal(oidTSTInfo) {
+		return nil, errors.New("signed attributes of token do not contain the content type of a timestamp")
+	}
+	var messageDigest []byte
+	if _, err := asn1.Unmarshal(attributes[oidMessageDigest.String()], &messageDigest); err != nil {
+		return nil, errors.New("signed attributes of token do not contain a message digest")
+	}
+	h := hash.New()
+	h.Write(content)
+	if !bytes.Equal(h.Sum(nil), messageDigest) {
+		return nil, errors.New("message digest of token does not match its content")
+	}
+	algorithm := signatureAlgorithm(hash, si.SignatureAlgorithm)
+	if algorithm == x509.UnknownSignatureAlgorithm {
+		return nil, errors.Errorf("unsupported signature algorithm %s of signer", si.SignatureAlgorithm.Algorithm)
+	}
+	// the signature is calculated over the DER encoding of the attributes as SET OF instead of the implicit tag
+	signed := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+	if err := signer.CheckSignature(algorithm, signed, si.Signature); err != nil {
+		return nil, errors.Wrap(err, "invalid signature of token")
+	}
+	return signer, nil
+}
+
+// findSigner returns the certificate identified by issuer and serial number or by subject key identifier
+func findSigner(sid asn1.RawValue, certificates []*x509.Certificate) (*x509.Certificate, error) {
+	for _, cert := range certificates {
+		switch {
+		case sid.Class == asn1.ClassUniversal && sid.Tag == asn1.TagSequence:
+			var ias issuerAndSerialNumber
+			if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+				return nil, errors.Wrap(err, "failed to parse signer of token")
+			}
+			if bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) && cert.SerialNumber.Cmp(ias.SerialNumber) == 0 {
+				return cert, nil
+			}
+		case sid.Class == asn1.ClassContextSpecific && sid.Tag == 0:
+			if bytes.Equal(cert.SubjectKeyId, sid.Bytes) {
+				return cert, nil
+			}
+		}
+	}
+	return nil, errors.New("token does not contain the certificate of its signer")
+}
+
+// parseAttributes returns the DER encoded first value of each attribute by its type
+func parseAttributes(content []byte) (map[string][]byte, error) {
+	attributes := make(map[string][]byte)
+	for len(content) > 0 {
+		var attr attribute
+		rest, err := asn1.Unmarshal(content, &attr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse signed attributes of token")
+		}
+		var value asn1.RawValue
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse signed attribute %s of token", attr.Type)
+		}
+		attributes[attr.Type.String()] = value.FullBytes
+		content = rest
+	}
+	return attributes, nil
+}
+
+// String describes the token for humans
+func (t *Token) String() string {
+	return fmt.Sprintf("issued at %s by '%s' with serial number %s", t.GenTime.UTC().Format(time.RFC3339), t.Signer.Subject.String(), t.SerialNumber.String())
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package timestamp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startAuthority(t *testing.T) (*Authority, *httptest.Server) {
+	authority, err := NewLocalAuthority()
+	require.NoError(t, err)
+	server := httptest.NewServer(authority)
+	t.Cleanup(server.Close)
+	return authority, server
+}
+
+func trust(authority *Authority) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(authority.CA())
+	return pool
+}
+
+func TestRequestAndVerify(t *testing.T) {
+	authority, server := startAuthority(t)
+	digest := sha256.Sum256([]byte("evidence"))
+
+	token, err := Request(server.URL, digest[:])
+	require.NoError(t, err)
+
+	parsed, err := Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, digest[:], parsed.HashedMessage)
+	assert.Equal(t, LocalPolicy, parsed.Policy)
+	assert.Equal(t, "onyx local TSA", parsed.Signer.Subject.CommonName)
+	assert.WithinDuration(t, time.Now(), parsed.GenTime, time.Minute)
+	assert.NoError(t, parsed.Verify(digest[:], trust(authority)))
+}
+
+func TestVerifyFails(t *testing.T) {
+	authority, server := startAuthority(t)
+	digest := sha256.Sum256([]byte("evidence"))
+	token, err := Request(server.URL, digest[:])
+	require.NoError(t, err)
+	parsed, err := Parse(token)
+	require.NoError(t, err)
+
+	t.Run("for another digest", func(t *testing.T) {
+		other := sha256.Sum256([]byte("modified evidence"))
+		assert.EqualError(t, parsed.Verify(other[:], trust(authority)), "timestamp was issued for another digest")
+	})
+	t.Run("for an untrusted TSA", func(t *testing.T) {
+		other, err := NewLocalAuthority()
+		require.NoError(t, err)
+		assert.ErrorContains(t, parsed.Verify(digest[:], trust(other)), "certificate of TSA 'CN=onyx local TSA' is not trusted")
+	})
+}
+
+func TestParseFailsForModifiedToken(t *testing.T) {
+	_, server := startAuthority(t)
+	digest := sha256.Sum256([]byte("evidence"))
+	token, err := Request(server.URL, digest[:])
+	require.NoError(t, err)
+
+	// the token contains the digest once, in the message imprint of the signed content
+	modified := bytes.Replace(token, digest[:], make([]byte, len(digest)), 1)
+	require.NotEqual(t, token, modified)
+	_, err = Parse(modified)
+	assert.EqualError(t, err, "message digest of token does not match its content")
+}
+
+func TestRequestFails(t *testing.T) {
+	digest := sha256.Sum256([]byte("evidence"))
+	testCases := map[string]struct {
+		handler http.HandlerFunc
+		err     string
+	}{
+		"http error": {
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+			err: "failed to request timestamp from '%s': 503 Service Unavailable",
+		},
+		"rejected": {
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				reply, _ := asn1.Marshal(timeStampResp{Status: pkiStatusInfo{Status: statusRejection, StatusString: []string{"bad request"}}})
+				_, _ = w.Write(reply)
+			},
+			err: "invalid timestamp reply of '%s': timestamp was rejected with status 2: bad request",
+		},
+		"no reply": {
+			handler: func(w http.ResponseWriter, req *http.Request) {},
+			err:     "invalid timestamp reply of '%s': failed to parse reply: asn1: syntax error: sequence truncated",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+			_, err := Request(server.URL, digest[:])
+			assert.EqualError(t, err, fmt.Sprintf(tc.err, server.URL))
+		})
+	}
+}
+
+func TestAuthorityRejectsMalformedQuery(t *testing.T) {
+	authority, err := NewLocalAuthority()
+	require.NoError(t, err)
+	reply, err := authority.Reply([]byte("no query"))
+	require.NoError(t, err)
+	_, err = parseReply(reply)
+	assert.EqualError(t, err, "timestamp was rejected with status 2: malformed request")
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/expression"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	notApplicableStatus = "NA"
+	notValidStatus      = "SKIPPED"
+)
+
+type applicability struct {
+	vars map[string]string
+	date string
+}
+
+// NewApplicability answers checks which are not applicable according to the conditions of their chapter, requirement or check.
+// Checks whose condition is false become NA, checks outside of their validity period on the given run date become SKIPPED.
+func NewApplicability(vars map[string]string, now time.Time) Transformer {
+	return &applicability{
+		vars: vars,
+		date: now.Format(time.DateOnly),
+	}
+}
+
+func (a applicability) Transform(ep *model.ExecutionPlan) error {
+	for index := range ep.AutopilotChecks {
+		autopilotItem := &ep.AutopilotChecks[index]
+		result, err := a.evaluate(autopilotItem.Item)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			continue
+		}
+		autopilotItem.NotApplicable = result
+		// apps are not needed if the autopilot is not executed
+		autopilotItem.AppReferences = nil
+	}
+	for index := range ep.ManualChecks {
+		manualItem := &ep.ManualChecks[index]
+		result, err := a.evaluate(manualItem.Item)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			continue
+		}
+		manualItem.Manual.Status = result.Status
+		manualItem.Manual.Reason = result.Reason
+		manualItem.Manual.Expires = ""
+	}
+	return nil
+}
+
+// evaluate returns the answer of the item if one of its conditions is not met and nil if the item is applicable
+func (a applicability) evaluate(item model.Item) (*model.ManualResult, error) {
+	for _, condition := range item.Conditions {
+		// dates have the format YYYY-MM-DD and can be compared as strings
+		if condition.ValidFrom != "" && a.date < condition.ValidFrom {
+			return a.answer(item, notValidStatus, fmt.Sprintf("%s is only valid from %s", condition.Level, condition.ValidFrom)), nil
+		}
+		if condition.ValidUntil != "" && a.date > condition.ValidUntil {
+			return a.answer(item, notValidStatus, fmt.Sprintf("%s was only valid until %s", condition.Level, condition.ValidUntil)), nil
+		}
+		if condition.When == "" {
+			continue
+		}
+		ok, err := expression.Evaluate(condition.When, map[string]interface{}{"vars": a.vars})
+		if err != nil {
+			return nil, model.NewUserErr(errors.Wrapf(err, "failed to evaluate condition of %s for check '%s' under requirement '%s' of chapter '%s'", condition.Level, item.Check.Id, item.Requirement.Id, item.Chapter.Id), "applicability evaluation failed")
+		}
+		if !ok {
+			return a.answer(item, notApplicableStatus, fmt.Sprintf("%s is not applicable, condition '%s' is not met", condition.Level, condition.When)), nil
+		}
+	}
+	return nil, nil
+}
+
+func (a applicability) answer(item model.Item, status, reason string) *model.ManualResult {
+	logger.Get().Infof("check '%s' under requirement '%s' of chapter '%s' is %s: %s", item.Check.Id, item.Requirement.Id, item.Chapter.Id, status, reason)
+	return &model.ManualResult{Status: status, Reason: reason}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/B-S-F/yaku/onyx/pkg/configuration"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicabilityTransform(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.Local)
+	vars := map[string]string{"HAS_UI": "false", "CLOUD": "true"}
+	testCases := map[string]struct {
+		conditions []model.Condition
+		want       *model.ManualResult
+	}{
+		"should keep checks without conditions": {},
+		"should keep checks whose conditions are met": {
+			conditions: []model.Condition{
+				{Level: "chapter '1'", When: "vars.CLOUD == 'true'"},
+				{Level: "check '3'", ValidFrom: "2024-07-01", ValidUntil: "2024-07-01"},
+			},
+		},
+		"should answer checks with NA if a condition is not met": {
+			conditions: []model.Condition{
+				{Level: "chapter '1'", When: "vars.CLOUD == 'true'"},
+				{Level: "requirement '2'", When: "vars.HAS_UI == 'true'"},
+			},
+			want: &model.ManualResult{Status: "NA", Reason: "requirement '2' is not applicable, condition 'vars.HAS_UI == 'true'' is not met"},
+		},
+		"should answer checks with SKIPPED before their validity period": {
+			conditions: []model.Condition{{Level: "check '3'", ValidFrom: "2024-07-02"}},
+			want:       &model.ManualResult{Status: "SKIPPED", Reason: "check '3' is only valid from 2024-07-02"},
+		},
+		"should answer checks with SKIPPED after their validity period": {
+			conditions: []model.Condition{{Level: "chapter '1'", ValidUntil: "2024-06-30", When: "vars.HAS_UI == 'true'"}},
+			want:       &model.ManualResult{Status: "SKIPPED", Reason: "chapter '1' was only valid until 2024-06-30"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			item := model.Item{
+				Chapter:     configuration.Chapter{Id: "1"},
+				Requirement: configuration.Requirement{Id: "2"},
+				Check:       configuration.Check{Id: "3"},
+				Conditions:  tc.conditions,
+			}
+			ep := &model.ExecutionPlan{
+				AutopilotChecks: []model.AutopilotCheck{{
+					Item:          item,
+					AppReferences: []*configuration.AppReference{{Name: "sharepoint-fetcher"}},
+				}},
+				ManualChecks: []model.ManualCheck{{
+					Item:   item,
+					Manual: configuration.Manual{Status: "GREEN", Reason: "reviewed", Expires: "2024-12-31"},
+				}},
+			}
+
+			err := NewApplicability(vars, now).Transform(ep)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ep.AutopilotChecks[0].NotApplicable)
+			if tc.want == nil {
+				assert.Len(t, ep.AutopilotChecks[0].AppReferences, 1)
+				assert.Equal(t, configuration.Manual{Status: "GREEN", Reason: "reviewed", Expires: "2024-12-31"}, ep.ManualChecks[0].Manual)
+			} else {
+				assert.Empty(t, ep.AutopilotChecks[0].AppReferences)
+				assert.Equal(t, configuration.Manual{Status: tc.want.Status, Reason: tc.want.Reason}, ep.ManualChecks[0].Manual)
+			}
+		})
+	}
+}
+
+func TestApplicabilityTransformUnknownVariable(t *testing.T) {
+	ep := &model.ExecutionPlan{
+		ManualChecks: []model.ManualCheck{{
+			Item: model.Item{
+				Chapter:     configuration.Chapter{Id: "1"},
+				Requirement: configuration.Requirement{Id: "2"},
+				Check:       configuration.Check{Id: "3"},
+				Conditions:  []model.Condition{{Level: "check '3'", When: "vars.SAFETY == 'true'"}},
+			},
+		}},
+	}
+
+	err := NewApplicability(map[string]string{}, time.Now()).Transform(ep)
+
+	assert.EqualError(t, err, "applicability evaluation failed: failed to evaluate condition of check '3' for check '3' under requirement '2' of chapter '1': failed to evaluate expression 'vars.SAFETY == 'true'': unknown variable 'vars.SAFETY'")
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TemplateSuffix marks config files which are rendered as template, the suffix is removed from the rendered file
+	TemplateSuffix = ".tmpl"
+	// maximum size of a rendered config file
+	maxTemplateOutput = 10 * 1024 * 1024
+)
+
+type configTemplates struct {
+	vars    map[string]string
+	secrets map[string]string
+}
+
+// NewConfigTemplates renders the templated config files of the steps.
+// A config file is a template if the step sets 'configTemplate' or its name ends with '.tmpl'.
+// It must run after the config files are loaded.
+// Environment variables containing secrets are not available in templates, the rendered files are not masked.
+func NewConfigTemplates(vars, secrets map[string]string) Transformer {
+	return &configTemplates{
+		vars:    vars,
+		secrets: secrets,
+	}
+}
+
+func (c configTemplates) Transform(ep *model.ExecutionPlan) error {
+	vars := helper.MergeMaps(ep.DefaultVars, c.vars)
+	var errs []error
+	for index := range ep.AutopilotChecks {
+		autopilotItem := &ep.AutopilotChecks[index]
+		for _, stepLevels := range autopilotItem.Autopilot.Steps {
+			for i := range stepLevels {
+				step := &stepLevels[i]
+				data := map[string]interface{}{
+					"vars":        vars,
+					"env":         c.withoutSecrets(helper.MergeMaps(ep.Env, autopilotItem.Autopilot.Env, step.Env, autopilotItem.CheckEnv)),
+					"chapter":     map[string]string{"id": autopilotItem.Chapter.Id, "title": autopilotItem.Chapter.Title},
+					"requirement": map[string]string{"id": autopilotItem.Requirement.Id, "title": autopilotItem.Requirement.Title},
+					"check":       map[string]string{"id": autopilotItem.Check.Id, "title": autopilotItem.Check.Title},
+					"autopilot":   map[string]string{"name": autopilotItem.Autopilot.Name},
+					"step":        map[string]string{"id": step.ID, "title": step.Title},
+				}
+				configs, err := renderConfigs(step.Configs, step.ConfigTemplate, data)
+				if err != nil {
+					errs = append(errs, errors.Wrapf(err, "check '%s' of requirement '%s' of chapter '%s'", autopilotItem.Check.Id, autopilotItem.Requirement.Id, autopilotItem.Chapter.Id))
+					continue
+				}
+				step.Configs = configs
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return model.NewUserErr(helper.Join(errs...), "rendering config templates failed")
+	}
+	return nil
+}
+
+// renderConfigs returns the config files with the templates rendered and their suffix removed.
+// A rendered file must not have the name of another config file.
+func renderConfigs(configs map[string]string, configTemplate bool, data map[string]interface{}) (map[string]string, error) {
+	rendered := make(map[string]string, len(configs))
+	var errs []error
+	for name, content := range configs {
+		if !IsConfigTemplate(name, configTemplate) {
+			rendered[name] = content
+		}
+	}
+	for name, content := range configs {
+		if !IsConfigTemplate(name, configTemplate) {
+			continue
+		}
+		target := strings.TrimSuffix(name, TemplateSuffix)
+		if _, ok := rendered[target]; ok {
+			errs = append(errs, errors.Errorf("config template '%s' would overwrite config file '%s'", name, target))
+			continue
+		}
+		content, err := renderTemplate(name, content, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rendered[target] = content
+	}
+	if len(errs) > 0 {
+		return nil, helper.Join(errs...)
+	}
+	return rendered, nil
+}
+
+// withoutSecrets returns the environment variables whose values contain none of the secrets
+func (c configTemplates) withoutSecrets(env map[string]string) map[string]string {
+	result := make(map[string]string, len(env))
+	for name, value := range env {
+		if !containsSecret(value, c.secrets) {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+func containsSecret(value string, secrets map[string]string) bool {
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(value, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConfigTemplate returns whether a config file of a step is rendered as template
+func IsConfigTemplate(name string, configTemplate bool) bool {
+	return configTemplate || strings.HasSuffix(name, TemplateSuffix)
+}
+
+// ParseConfigTemplate parses a config file as template without rendering it.
+// Syntax errors contain the name of the file and the line, e.g. "template: repos.yaml.tmpl:3: unexpected EOF".
+func ParseConfigTemplate(name, content string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(content)
+}
+
+// renderTemplate renders a config file with text/template.
+// Templates only have access to the given data and the functions of templateFuncs,
+// referencing missing keys is an error and the output is limited in size.
+func renderTemplate(name, content string, data map[string]interface{}) (string, error) {
+	tmpl, err := ParseConfigTemplate(name, content)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse config template '%s'", name)
+	}
+	out := &limitedBuffer{limit: maxTemplateOutput}
+	if err := tmpl.Execute(out, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render config template '%s'", name)
+	}
+	return out.String(), nil
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errors.Errorf("rendered config exceeds %d bytes", b.limit)
+	}
+	return b.Buffer.Write(p)
+}
+
+// templateFuncs are the functions available in config templates, none of them has side effects
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"split":      splitList,
+	"join":       func(sep string, list []string) string { return strings.Join(list, sep) },
+	"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"required": func(msg string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(msg)
+		}
+		return value, nil
+	},
+	"indent":   indent,
+	"nindent":  func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+	"toJson":   toJSON,
+	"fromJson": fromJSON,
+	"toYaml":   toYAML,
+}
+
+// splitList splits s at sep, trims the items and drops empty ones
+func splitList(sep, s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+func toJSON(v interface{}) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func fromJSON(s string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func toYAML(v interface{}) (string, error) {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(content), "\n"), nil
+}
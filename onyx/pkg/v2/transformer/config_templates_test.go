@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package transformer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/configuration"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateExecutionPlan(configTemplate bool, configs map[string]string) *model.ExecutionPlan {
+	return &model.ExecutionPlan{
+		DefaultVars: map[string]string{"ORG": "default-org", "REPOS": "unused"},
+		Env:         map[string]string{"STAGE": "prod"},
+		AutopilotChecks: []model.AutopilotCheck{
+			{
+				Item: model.Item{
+					Chapter:     configuration.Chapter{Id: "1", Title: "Chapter"},
+					Requirement: configuration.Requirement{Id: "2", Title: "Requirement"},
+					Check:       configuration.Check{Id: "3", Title: "Check"},
+				},
+				Autopilot: model.Autopilot{
+					Name: "scanner",
+					Env:  map[string]string{"STAGE": "dev"},
+					Steps: [][]model.Step{{{
+						ID:             "fetch",
+						Configs:        configs,
+						ConfigTemplate: configTemplate,
+					}}},
+				},
+				CheckEnv: map[string]string{"TOKEN_NAME": "scan", "TOKEN": "Bearer my-token"},
+			},
+		},
+	}
+}
+
+func TestConfigTemplatesTransform(t *testing.T) {
+	testCases := map[string]struct {
+		configTemplate bool
+		configs        map[string]string
+		want           map[string]string
+		wantErr        string
+	}{
+		"should render files with template suffix": {
+			configs: map[string]string{
+				"repos.yaml.tmpl": "repositories:\n{{- range split \",\" .vars.REPOS }}\n  - {{ $.vars.ORG }}/{{ . }}\n{{- end }}\n",
+				"plain.yaml":      "check: {{ .check.id }}",
+			},
+			want: map[string]string{
+				"repos.yaml": "repositories:\n  - my-org/api\n  - my-org/web\n",
+				"plain.yaml": "check: {{ .check.id }}",
+			},
+		},
+		"should render all files of steps with configTemplate": {
+			configTemplate: true,
+			configs: map[string]string{
+				"context.yaml": "{{ .chapter.id }}_{{ .requirement.id }}_{{ .check.id }} {{ .autopilot.name }} {{ .step.id }} {{ .env.STAGE }} {{ .env.TOKEN_NAME }}",
+			},
+			want: map[string]string{
+				"context.yaml": "1_2_3 scanner fetch dev scan",
+			},
+		},
+		"should provide functions": {
+			configTemplate: true,
+			configs: map[string]string{
+				"funcs.yaml": "{{ upper .vars.ORG }} {{ index .vars \"MISSING\" | default \"fallback\" }} {{ toJson (split \",\" .vars.REPOS) }}\nlist:{{ toYaml (split \",\" .vars.REPOS) | nindent 2 }}",
+			},
+			want: map[string]string{
+				"funcs.yaml": "MY-ORG fallback [\"api\",\"web\"]\nlist:\n  - api\n  - web",
+			},
+		},
+		"should fail for missing variables": {
+			configTemplate: true,
+			configs:        map[string]string{"config.yaml": "{{ .vars.MISSING }}"},
+			wantErr:        "check '3' of requirement '2' of chapter '1': failed to render config template 'config.yaml'",
+		},
+		"should fail for invalid templates": {
+			configs: map[string]string{"config.yaml.tmpl": "{{ if }}"},
+			wantErr: "failed to parse config template 'config.yaml.tmpl'",
+		},
+		"should fail for required values": {
+			configTemplate: true,
+			configs:        map[string]string{"config.yaml": "{{ index .vars \"MISSING\" | required \"MISSING must be set\" }}"},
+			wantErr:        "MISSING must be set",
+		},
+		"should not provide environment variables with secrets": {
+			configTemplate: true,
+			configs:        map[string]string{"config.yaml": "{{ .env.TOKEN }}"},
+			wantErr:        "map has no entry for key \"TOKEN\"",
+		},
+		"should fail if a rendered file overwrites a config file": {
+			configs: map[string]string{
+				"config.yaml.tmpl": "check: {{ .check.id }}",
+				"config.yaml":      "check: 1",
+			},
+			wantErr: "config template 'config.yaml.tmpl' would overwrite config file 'config.yaml'",
+		},
+		"should fail for too large output": {
+			configTemplate: true,
+			configs:        map[string]string{"config.yaml": "{{ range split \",\" .vars.MANY }}" + strings.Repeat("x", 1024*1024) + "{{ end }}"},
+			wantErr:        "rendered config exceeds",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ep := templateExecutionPlan(tc.configTemplate, tc.configs)
+			vars := map[string]string{"ORG": "my-org", "REPOS": "api, web", "MANY": strings.Repeat("a,", 20)}
+
+			secrets := map[string]string{"TOKEN": "my-token", "EMPTY": ""}
+
+			err := NewConfigTemplates(vars, secrets).Transform(ep)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ep.AutopilotChecks[0].Autopilot.Steps[0][0].Configs)
+		})
+	}
+}
+
+func TestConfigTemplatesTransformKeepsLoadedConfigs(t *testing.T) {
+	configs := map[string]string{"config.yaml.tmpl": "check: {{ .check.id }}"}
+	ep := templateExecutionPlan(false, configs)
+
+	err := NewConfigTemplates(nil, nil).Transform(ep)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"config.yaml": "check: 3"}, ep.AutopilotChecks[0].Autopilot.Steps[0][0].Configs)
+	assert.Equal(t, map[string]string{"config.yaml.tmpl": "check: {{ .check.id }}"}, configs)
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"github.com/B-S-F/yaku/onyx/pkg/helper"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+)
+
+type legacyEnv struct{}
+
+// NewLegacyEnv provides the runtime environment of v0 and v1 configs to their migrated autopilots.
+// In contrast to v2, the environment variables of a check were passed to the autopilot
+// and the autopilot could rely on the variables 'evidence_path' and 'APPS'.
+// The checks are marked as legacy checks, so that their autopilots can provide the status 'FAILED' and
+// work on the linked files of the input folder like on the legacy engine.
+// It must be applied after all variables are replaced.
+func NewLegacyEnv() Transformer {
+	return &legacyEnv{}
+}
+
+func (l legacyEnv) Transform(ep *model.ExecutionPlan) error {
+	for i := range ep.AutopilotChecks {
+		item := &ep.AutopilotChecks[i]
+		specialEnv := map[string]string{"evidence_path": "."}
+		if item.AppPath != "" {
+			specialEnv["APPS"] = item.AppPath
+		}
+		item.Autopilot.Evaluate.Env = helper.MergeMaps(item.Autopilot.Evaluate.Env, item.CheckEnv, specialEnv)
+		item.Legacy = true
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegacyEnvTransform(t *testing.T) {
+	testCases := map[string]struct {
+		item model.AutopilotCheck
+		want map[string]string
+	}{
+		"should pass check env to evaluation": {
+			item: model.AutopilotCheck{
+				CheckEnv: map[string]string{"ENV_1": "check", "ENV_2": "check"},
+				Autopilot: model.Autopilot{Evaluate: model.Evaluate{
+					Env: map[string]string{"ENV_1": "autopilot", "ENV_3": "autopilot"},
+				}},
+			},
+			want: map[string]string{"ENV_1": "check", "ENV_2": "check", "ENV_3": "autopilot", "evidence_path": "."},
+		},
+		"should provide apps path": {
+			item: model.AutopilotCheck{
+				AppPath: "/apps/1_1_1",
+			},
+			want: map[string]string{"evidence_path": ".", "APPS": "/apps/1_1_1"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ep := &model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{tc.item}}
+
+			err := NewLegacyEnv().Transform(ep)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, ep.AutopilotChecks[0].Autopilot.Evaluate.Env)
+			assert.True(t, ep.AutopilotChecks[0].Legacy)
+		})
+	}
+}
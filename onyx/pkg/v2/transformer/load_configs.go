@@ -37,6 +37,7 @@ func (d configsLoader) Transform(ep *model.ExecutionPlan) error {
 						step.Configs[config] = string(file)
 					}
 				}
+				addInlineConfigs(step.Configs, step.InlineConfigs)
 			}
 		}
 		for config := range autopilotItem.Autopilot.Evaluate.Configs {
@@ -47,18 +48,37 @@ func (d configsLoader) Transform(ep *model.ExecutionPlan) error {
 			}
 			autopilotItem.Autopilot.Evaluate.Configs[config] = string(file)
 		}
+		addInlineConfigs(autopilotItem.Autopilot.Evaluate.Configs, autopilotItem.Autopilot.Evaluate.InlineConfigs)
 	}
 	if ep.Finalize != nil {
-		if ep.Finalize.Configs != nil {
-			for config := range ep.Finalize.Configs {
-				file, err := os.ReadFile(filepath.Join(d.rootWorkDir, config))
-				if err != nil {
-					logger.Get().UserErrorf("error reading config file '%s'. Trying to continue without it.", config)
-					continue
-				}
-				ep.Finalize.Configs[config] = string(file)
-			}
-		}
+		d.loadFinalizeConfigs(ep.Finalize)
+	}
+	for i := range ep.Setup {
+		d.loadFinalizeConfigs(&ep.Setup[i])
+	}
+	for i := range ep.Teardown {
+		d.loadFinalizeConfigs(&ep.Teardown[i])
+	}
+	for i := range ep.Finalizers {
+		d.loadFinalizeConfigs(&ep.Finalizers[i])
 	}
 	return nil
 }
+
+func (d configsLoader) loadFinalizeConfigs(finalize *model.Finalize) {
+	for config := range finalize.Configs {
+		file, err := os.ReadFile(filepath.Join(d.rootWorkDir, config))
+		if err != nil {
+			logger.Get().UserErrorf("error reading config file '%s'. Trying to continue without it.", config)
+			continue
+		}
+		finalize.Configs[config] = string(file)
+	}
+}
+
+// addInlineConfigs adds the config files defined in the config to the loaded ones, the configs map is created by the mapper if there are any
+func addInlineConfigs(configs map[string]string, inline []model.InlineConfig) {
+	for _, config := range inline {
+		configs[config.Name] = config.Content
+	}
+}
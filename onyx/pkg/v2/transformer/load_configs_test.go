@@ -84,6 +84,35 @@ func TestConfigsLoaderTransform(t *testing.T) {
 						},
 					}}}}},
 		},
+		"should add inline config files": {
+			ep: &model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{{
+				Autopilot: model.Autopilot{
+					Steps: [][]model.Step{
+						{{
+							Configs: map[string]string{
+								"config.txt": "",
+							},
+							InlineConfigs: []model.InlineConfig{{Name: "filter.json", Content: "{}"}},
+						}}},
+					Evaluate: model.Evaluate{
+						Configs:       map[string]string{},
+						InlineConfigs: []model.InlineConfig{{Name: "rules.yaml", Content: "rules: []"}},
+					}}}}},
+			want: &model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{{
+				Autopilot: model.Autopilot{
+					Steps: [][]model.Step{
+						{{
+							Configs: map[string]string{
+								"config.txt":  "config content",
+								"filter.json": "{}",
+							},
+							InlineConfigs: []model.InlineConfig{{Name: "filter.json", Content: "{}"}},
+						}}},
+					Evaluate: model.Evaluate{
+						Configs:       map[string]string{"rules.yaml": "rules: []"},
+						InlineConfigs: []model.InlineConfig{{Name: "rules.yaml", Content: "rules: []"}},
+					}}}}},
+		},
 		"should load config file for finalizer": {
 			ep: &model.ExecutionPlan{
 				Finalize: &model.Finalize{
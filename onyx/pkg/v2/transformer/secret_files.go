@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+package transformer
+
+import (
+	"github.com/B-S-F/yaku/onyx/pkg/logger"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+type secretFilesChecker struct {
+	secrets map[string]string
+}
+
+// NewSecretFilesChecker marks checks as invalid if their secret files reference secrets which are not available.
+// Only the names of the secrets are checked, their values are never part of the error.
+func NewSecretFilesChecker(secrets map[string]string) Transformer {
+	return &secretFilesChecker{
+		secrets: secrets,
+	}
+}
+
+func (s secretFilesChecker) Transform(ep *model.ExecutionPlan) error {
+	for index := range ep.AutopilotChecks {
+		autopilotItem := &ep.AutopilotChecks[index]
+		for _, file := range autopilotItem.Autopilot.SecretFiles {
+			s.checkSecret(autopilotItem, file)
+		}
+		for _, stepLevels := range autopilotItem.Autopilot.Steps {
+			for _, step := range stepLevels {
+				for _, file := range step.SecretFiles {
+					s.checkSecret(autopilotItem, file)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s secretFilesChecker) checkSecret(autopilotItem *model.AutopilotCheck, file model.SecretFile) {
+	if _, ok := s.secrets[file.Secret]; ok {
+		return
+	}
+	validationErr := errors.Errorf("secret file '%s' of autopilot '%s' in check '%s' under requirement '%s' of chapter '%s' references the missing secret '%s'", file.Name, autopilotItem.Autopilot.Name, autopilotItem.Check.Id, autopilotItem.Requirement.Id, autopilotItem.Chapter.Id, file.Secret)
+	autopilotItem.ValidationErrs = append(autopilotItem.ValidationErrs, validationErr)
+	logger.Get().Warn(validationErr.Error())
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2024 grow platform GmbH
+//
+// SPDX-License-Identifier: MIT
+
+//go:build unit
+// +build unit
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/B-S-F/yaku/onyx/pkg/configuration"
+	"github.com/B-S-F/yaku/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFilesCheckerTransform(t *testing.T) {
+	testCases := map[string]struct {
+		secrets map[string]string
+		want    []string
+	}{
+		"should accept available secrets": {
+			secrets: map[string]string{"KUBECONFIG": "config", "TOKEN": "token"},
+		},
+		"should add validation errors for missing secrets": {
+			secrets: map[string]string{"KUBECONFIG": "config"},
+			want:    []string{"secret file 'token' of autopilot 'scanner' in check '3' under requirement '2' of chapter '1' references the missing secret 'TOKEN'"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ep := &model.ExecutionPlan{
+				AutopilotChecks: []model.AutopilotCheck{{
+					Item: model.Item{
+						Chapter:     configuration.Chapter{Id: "1"},
+						Requirement: configuration.Requirement{Id: "2"},
+						Check:       configuration.Check{Id: "3"},
+					},
+					Autopilot: model.Autopilot{
+						Name:        "scanner",
+						SecretFiles: []model.SecretFile{{Name: "kubeconfig", Secret: "KUBECONFIG"}},
+						Steps:       [][]model.Step{{{ID: "fetch", SecretFiles: []model.SecretFile{{Name: "token", Secret: "TOKEN"}}}}},
+					},
+				}},
+			}
+
+			err := NewSecretFilesChecker(tc.secrets).Transform(ep)
+
+			require.NoError(t, err)
+			var errs []string
+			for _, validationErr := range ep.AutopilotChecks[0].ValidationErrs {
+				errs = append(errs, validationErr.Error())
+			}
+			assert.Equal(t, tc.want, errs)
+		})
+	}
+}
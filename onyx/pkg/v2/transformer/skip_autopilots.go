@@ -52,6 +52,8 @@ func (d autopilotSkipper) Transform(ep *model.ExecutionPlan) error {
 	}
 
 	ep.Finalize = &model.Finalize{}
+	ep.Finalizers = nil
+	ep.Notifications = nil
 	return nil
 }
 